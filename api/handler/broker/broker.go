@@ -3,9 +3,11 @@ package broker
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/stack-labs/stack/api/handler"
 	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/server"
 	"github.com/stack-labs/stack/util/log"
 )
 
@@ -35,6 +38,7 @@ type conn struct {
 	cType string
 	topic string
 	queue string
+	start []broker.SubscribeOption
 	exit  chan bool
 
 	sync.Mutex
@@ -120,6 +124,8 @@ func (c *conn) writeLoop() {
 		opts = append(opts, broker.Queue(c.queue))
 	}
 
+	opts = append(opts, c.start...)
+
 	subscriber, err := c.b.Subscribe(c.topic, func(p broker.Event) error {
 		b, err := json.Marshal(p.Message())
 		if err != nil {
@@ -151,6 +157,52 @@ func (c *conn) writeLoop() {
 	}
 }
 
+// startOptions parses where a subscriber starts reading a topic, one of
+// earliest, latest or an offset. Only durable brokers keep earlier messages.
+func startOptions(start string) ([]broker.SubscribeOption, error) {
+	switch start {
+	case "", "latest":
+		return nil, nil
+	case "earliest":
+		return []broker.SubscribeOption{broker.StartEarliest()}, nil
+	}
+
+	offset, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return []broker.SubscribeOption{broker.StartAt(offset)}, nil
+}
+
+// replay publishes dead lettered messages back to the topic they failed on,
+// or the topic given. The body is a message or list of messages as they're
+// written to the websocket of the dead letter topic.
+func replay(br broker.Broker, topic string, body []byte) (int, error) {
+	var msgs []*broker.Message
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		msg := new(broker.Message)
+		if err := json.Unmarshal(body, msg); err != nil {
+			return 0, err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	for i, msg := range msgs {
+		t, m, err := server.Replay(msg)
+		if err != nil {
+			return i, err
+		}
+		if len(topic) > 0 {
+			t = topic
+		}
+		if err := br.Publish(t, m); err != nil {
+			return i, err
+		}
+	}
+
+	return len(msgs), nil
+}
+
 func (b *brokerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	br := b.opts.Service.Client().Options().Broker
 
@@ -164,6 +216,25 @@ func (b *brokerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	topic := r.Form.Get("topic")
 
+	// Replay dead lettered messages
+	if r.Method == "POST" && len(r.Form.Get("replay")) > 0 {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+
+		n, err := replay(br, topic, b)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Replayed %d messages: %v", n, err), 400)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]int{"replayed": n})
+		return
+	}
+
 	// Can't do anything without a topic
 	if len(topic) == 0 {
 		http.Error(w, "Topic not specified", 400)
@@ -206,6 +277,12 @@ func (b *brokerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	queue := r.Form.Get("queue")
 
+	start, err := startOptions(r.Form.Get("start"))
+	if err != nil {
+		http.Error(w, "Invalid start: "+err.Error(), 400)
+		return
+	}
+
 	ws, err := b.u.Upgrade(w, r, nil)
 	if err != nil {
 		log.Log(err.Error())
@@ -222,6 +299,7 @@ func (b *brokerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		cType: cType,
 		topic: topic,
 		queue: queue,
+		start: start,
 		exit:  make(chan bool),
 		ws:    ws,
 	}
@@ -0,0 +1,77 @@
+package broker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/broker/memory"
+	"github.com/stack-labs/stack/server"
+)
+
+func TestReplay(t *testing.T) {
+	br := memory.NewBroker()
+	if err := br.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*broker.Message
+	for _, topic := range []string{"orders", "other"} {
+		if _, err := br.Subscribe(topic, func(e broker.Event) error {
+			got = append(got, e.Message())
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dead := &broker.Message{
+		Header: map[string]string{
+			"Id":                            "1",
+			server.DeadLetterTopicHeader:    "orders",
+			server.DeadLetterErrorHeader:    "boom",
+			server.DeadLetterAttemptsHeader: "3",
+		},
+		Body: []byte("hello"),
+	}
+
+	b, err := json.Marshal(dead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := replay(br, "", b); err != nil || n != 1 {
+		t.Fatalf("expected 1 replayed got %d: %v", n, err)
+	}
+
+	b, err = json.Marshal([]*broker.Message{dead, dead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := replay(br, "other", b); err != nil || n != 2 {
+		t.Fatalf("expected 2 replayed got %d: %v", n, err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 messages got %d", len(got))
+	}
+	for _, m := range got {
+		if string(m.Body) != "hello" || m.Header["Id"] != "1" {
+			t.Fatalf("unexpected message %v", m)
+		}
+		if _, ok := m.Header[server.DeadLetterTopicHeader]; ok {
+			t.Fatalf("unexpected dead letter headers %v", m.Header)
+		}
+	}
+
+	b, _ = json.Marshal(&broker.Message{Body: []byte("hello")})
+	if _, err := replay(br, "", b); err != server.ErrNotDeadLetter {
+		t.Fatalf("expected %v got %v", server.ErrNotDeadLetter, err)
+	}
+
+	if _, err := startOptions("nope"); err == nil {
+		t.Fatal("expected invalid start error")
+	}
+	if opts, err := startOptions("10"); err != nil || len(opts) != 1 {
+		t.Fatalf("unexpected start options %v: %v", opts, err)
+	}
+}
@@ -4,6 +4,8 @@ import "github.com/google/uuid"
 
 var (
 	NoopAuth = &noop{}
+	// NoopRules grants access to every resource
+	NoopRules Rules = &noopRules{}
 )
 
 type noop struct {
@@ -4,8 +4,85 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 )
 
+type rules struct {
+	sync.RWMutex
+	rules []*Rule
+}
+
+// NewRules returns rules kept in memory
+func NewRules(r ...*Rule) Rules {
+	return &rules{rules: r}
+}
+
+func (r *rules) Grant(rule *Rule) error {
+	r.Lock()
+	defer r.Unlock()
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+func (r *rules) Revoke(rule *Rule) error {
+	r.Lock()
+	defer r.Unlock()
+
+	var rules []*Rule
+	for _, rr := range r.rules {
+		if rr.ID != rule.ID {
+			rules = append(rules, rr)
+		}
+	}
+
+	r.rules = rules
+	return nil
+}
+
+func (r *rules) Verify(acc *Account, res *Resource, opts ...VerifyOption) error {
+	r.RLock()
+	defer r.RUnlock()
+	return Verify(r.rules, acc, res)
+}
+
+func (r *rules) List(opts ...ListOption) ([]*Rule, error) {
+	r.RLock()
+	defer r.RUnlock()
+	return append([]*Rule{}, r.rules...), nil
+}
+
+// ParseRule parses a rule of the form access:scope:name:endpoint, the access is
+// grant or deny, a blank scope is public and * is any account. eg.
+// grant::stack.service.foo:Foo.Bar grants anyone access to the endpoint and
+// deny:*:*:* denies any account access to everything
+func ParseRule(s string) (*Rule, error) {
+	parts := strings.SplitN(s, ":", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid rule %q, expected access:scope:name:endpoint", s)
+	}
+
+	rule := &Rule{
+		ID:    s,
+		Scope: parts[1],
+		Resource: &Resource{
+			Type:     "*",
+			Name:     parts[2],
+			Endpoint: parts[3],
+		},
+	}
+
+	switch parts[0] {
+	case "grant":
+		rule.Access = AccessGranted
+	case "deny":
+		rule.Access = AccessDenied
+	default:
+		return nil, fmt.Errorf("invalid rule %q, access is grant or deny", s)
+	}
+
+	return rule, nil
+}
+
 // Verify an account has access to a resource using the rules provided. If the account does not have
 // access an error will be returned. If there are no rules provided which match the resource, an error
 // will be returned
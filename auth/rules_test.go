@@ -286,3 +286,39 @@ func TestVerify(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRule(t *testing.T) {
+	rules := NewRules()
+	for i, s := range []string{"grant::*:Foo.Public", "deny:*:*:Foo.Admin", "grant:*:stack.service.foo:*"} {
+		rule, err := ParseRule(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rule.Priority = int32(10 - i)
+		rules.Grant(rule)
+	}
+
+	res := func(endpoint string) *Resource {
+		return &Resource{Type: "service", Name: "stack.service.foo", Endpoint: endpoint}
+	}
+	acc := &Account{ID: "foo"}
+
+	if err := rules.Verify(nil, res("Foo.Public")); err != nil {
+		t.Fatalf("expected public access got %v", err)
+	}
+	if err := rules.Verify(nil, res("Foo.Bar")); err != ErrForbidden {
+		t.Fatalf("expected %v got %v", ErrForbidden, err)
+	}
+	if err := rules.Verify(acc, res("Foo.Bar")); err != nil {
+		t.Fatalf("expected account access got %v", err)
+	}
+	if err := rules.Verify(acc, res("Foo.Admin")); err != ErrForbidden {
+		t.Fatalf("expected %v got %v", ErrForbidden, err)
+	}
+
+	for _, s := range []string{"grant:*:*", "allow:*:*:*"} {
+		if _, err := ParseRule(s); err == nil {
+			t.Fatalf("expected %q to be invalid", s)
+		}
+	}
+}
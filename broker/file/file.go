@@ -0,0 +1,614 @@
+// Package file is a durable broker keeping a segmented log per topic on disk.
+// Queues commit their offset so they resume where they left off, and
+// messages which go unacked are redelivered.
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/util/log"
+	"github.com/stack-labs/stack/util/segment"
+)
+
+var (
+	// DefaultDir is the directory the topic logs are kept in
+	DefaultDir = filepath.Join(os.TempDir(), "stack", "broker")
+	// DefaultAckTimeout is how long a message may go unacked before it's redelivered
+	DefaultAckTimeout = time.Second * 30
+	// DefaultMaxInflight is the number of unacked messages of a subscription
+	// after which no more are delivered
+	DefaultMaxInflight = 1024
+	// DefaultCommitInterval is how often the offsets of queues are saved
+	DefaultCommitInterval = time.Second
+
+	// ErrNotConnected is returned using the broker before Connect
+	ErrNotConnected = errors.New("not connected")
+
+	offsetsDir = "offsets"
+)
+
+type fileBroker struct {
+	opts broker.Options
+
+	dir         string
+	segOpts     []segment.Option
+	ackTimeout  time.Duration
+	maxInflight int
+
+	sync.RWMutex
+	connected bool
+	logs      map[string]*segment.Log
+	// groups by topic and queue
+	groups map[string]*group
+}
+
+// message is the entry written to the log
+type message struct {
+	Header map[string]string `json:"header,omitempty"`
+	Body   []byte            `json:"body,omitempty"`
+}
+
+type delivery struct {
+	deadline time.Time
+	attempts int
+}
+
+// group delivers the messages of a topic to its subscribers in turn. A
+// queue is a durable group whose offset is committed to disk.
+type group struct {
+	b     *fileBroker
+	topic string
+	queue string
+	log   *segment.Log
+	// path of the committed offset, empty if not durable
+	path string
+
+	sync.Mutex
+	subs []*fileSubscriber
+	rr   int
+	// next offset to deliver
+	next int64
+	// unacked deliveries by offset
+	pending map[int64]*delivery
+	// last committed offset
+	committed  int64
+	commitTime time.Time
+
+	ack  chan bool
+	exit chan bool
+	done chan bool
+	once sync.Once
+}
+
+type fileSubscriber struct {
+	id      string
+	topic   string
+	handler broker.Handler
+	opts    broker.SubscribeOptions
+	group   *group
+}
+
+type fileEvent struct {
+	topic   string
+	message *broker.Message
+	offset  int64
+	attempt int
+	group   *group
+}
+
+func escape(s string) string {
+	return url.PathEscape(s)
+}
+
+func (f *fileBroker) topicLog(topic string) (*segment.Log, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.connected {
+		return nil, ErrNotConnected
+	}
+
+	if l, ok := f.logs[topic]; ok {
+		return l, nil
+	}
+
+	l, err := segment.Open(filepath.Join(f.dir, escape(topic)), f.segOpts...)
+	if err != nil {
+		return nil, err
+	}
+	f.logs[topic] = l
+
+	return l, nil
+}
+
+func (f *fileBroker) Options() broker.Options {
+	return f.opts
+}
+
+func (f *fileBroker) Address() string {
+	return f.dir
+}
+
+func (f *fileBroker) Connect() error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.connected {
+		return nil
+	}
+
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return err
+	}
+
+	f.connected = true
+
+	return nil
+}
+
+func (f *fileBroker) Disconnect() error {
+	f.Lock()
+	if !f.connected {
+		f.Unlock()
+		return nil
+	}
+	f.connected = false
+
+	groups := f.groups
+	f.groups = make(map[string]*group)
+	f.Unlock()
+
+	// stop delivering before the logs are closed
+	for _, g := range groups {
+		g.stop()
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	for topic, l := range f.logs {
+		l.Close()
+		delete(f.logs, topic)
+	}
+
+	return nil
+}
+
+func (f *fileBroker) Init(opts ...broker.Option) error {
+	for _, o := range opts {
+		o(&f.opts)
+	}
+	f.configure()
+	return nil
+}
+
+func (f *fileBroker) configure() {
+	f.dir = DefaultDir
+	f.ackTimeout = DefaultAckTimeout
+	f.maxInflight = DefaultMaxInflight
+	f.segOpts = nil
+
+	// the address of the broker is the dir unless set
+	if len(f.opts.Addrs) > 0 && len(f.opts.Addrs[0]) > 0 {
+		f.dir = f.opts.Addrs[0]
+	}
+
+	ctx := f.opts.Context
+	if ctx == nil {
+		return
+	}
+
+	if d, ok := ctx.Value(dirKey{}).(string); ok && len(d) > 0 {
+		f.dir = d
+	}
+	if n, ok := ctx.Value(segmentSizeKey{}).(int64); ok && n > 0 {
+		f.segOpts = append(f.segOpts, segment.SegmentSize(n))
+	}
+	if n, ok := ctx.Value(maxSegmentsKey{}).(int); ok {
+		f.segOpts = append(f.segOpts, segment.MaxSegments(n))
+	}
+	if b, ok := ctx.Value(syncKey{}).(bool); ok {
+		f.segOpts = append(f.segOpts, segment.Sync(b))
+	}
+	if d, ok := ctx.Value(ackTimeoutKey{}).(time.Duration); ok && d > 0 {
+		f.ackTimeout = d
+	}
+	if n, ok := ctx.Value(maxInflightKey{}).(int); ok && n > 0 {
+		f.maxInflight = n
+	}
+}
+
+func (f *fileBroker) Publish(topic string, m *broker.Message, opts ...broker.PublishOption) error {
+	l, err := f.topicLog(topic)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(&message{Header: m.Header, Body: m.Body})
+	if err != nil {
+		return err
+	}
+
+	_, err = l.Append(b)
+	return err
+}
+
+func (f *fileBroker) Subscribe(topic string, h broker.Handler, opts ...broker.SubscribeOption) (broker.Subscriber, error) {
+	l, err := f.topicLog(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	options := broker.NewSubscribeOptions(opts...)
+
+	sub := &fileSubscriber{
+		id:      uuid.New().String(),
+		topic:   topic,
+		handler: h,
+		opts:    options,
+	}
+
+	// subscribers without a queue each get every message
+	key := topic + "\xff" + sub.id
+	if len(options.Queue) > 0 {
+		key = topic + "\xff\xff" + options.Queue
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	if g, ok := f.groups[key]; ok {
+		g.Lock()
+		g.subs = append(g.subs, sub)
+		g.Unlock()
+		sub.group = g
+		return sub, nil
+	}
+
+	g := &group{
+		b:       f,
+		topic:   topic,
+		queue:   options.Queue,
+		log:     l,
+		subs:    []*fileSubscriber{sub},
+		pending: make(map[int64]*delivery),
+		ack:     make(chan bool, 1),
+		exit:    make(chan bool),
+		done:    make(chan bool),
+	}
+
+	if len(options.Queue) > 0 {
+		g.path = filepath.Join(f.dir, escape(topic), offsetsDir, escape(options.Queue))
+	}
+
+	if err := g.start(options); err != nil {
+		return nil, err
+	}
+
+	sub.group = g
+	f.groups[key] = g
+
+	go g.run()
+
+	return sub, nil
+}
+
+func (f *fileBroker) unsubscribe(s *fileSubscriber) {
+	g := s.group
+
+	g.Lock()
+	for i, sub := range g.subs {
+		if sub.id == s.id {
+			g.subs = append(g.subs[:i], g.subs[i+1:]...)
+			break
+		}
+	}
+	empty := len(g.subs) == 0
+	g.Unlock()
+
+	if !empty {
+		return
+	}
+
+	f.Lock()
+	for k, v := range f.groups {
+		if v == g {
+			delete(f.groups, k)
+		}
+	}
+	f.Unlock()
+
+	g.stop()
+}
+
+func (f *fileBroker) String() string {
+	return "file"
+}
+
+// start sets the offset the group starts delivering from
+func (g *group) start(opts broker.SubscribeOptions) error {
+	first, next := g.log.First(), g.log.Next()
+
+	committed := int64(-1)
+	if len(g.path) > 0 {
+		b, err := ioutil.ReadFile(g.path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil {
+			if committed, err = strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch {
+	case opts.Position == broker.PositionOffset:
+		g.next = opts.Offset
+	case committed >= 0:
+		g.next = committed
+	case opts.Position == broker.PositionEarliest:
+		g.next = first
+	default:
+		g.next = next
+	}
+
+	if g.next < first {
+		g.next = first
+	}
+	if g.next > next {
+		g.next = next
+	}
+
+	g.committed = committed
+	return g.commit(true)
+}
+
+// commit saves the offset before which every message has been acked
+func (g *group) commit(force bool) error {
+	if len(g.path) == 0 {
+		return nil
+	}
+
+	g.Lock()
+	offset := g.next
+	for o := range g.pending {
+		if o < offset {
+			offset = o
+		}
+	}
+	if offset == g.committed || (!force && time.Since(g.commitTime) < DefaultCommitInterval) {
+		g.Unlock()
+		return nil
+	}
+	g.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(g.path), 0700); err != nil {
+		return err
+	}
+
+	// write and rename so a crash never leaves a partial offset
+	tmp := g.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(strconv.FormatInt(offset, 10)), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, g.path); err != nil {
+		return err
+	}
+
+	g.Lock()
+	g.committed = offset
+	g.commitTime = time.Now()
+	g.Unlock()
+
+	return nil
+}
+
+// due returns the offsets to deliver now, redeliveries first, along with
+// how long to wait for the next redelivery
+func (g *group) due() ([]int64, time.Duration) {
+	g.Lock()
+	defer g.Unlock()
+
+	now := time.Now()
+	wait := DefaultCommitInterval
+
+	var offsets []int64
+	for o, d := range g.pending {
+		if left := d.deadline.Sub(now); left > 0 {
+			if left < wait {
+				wait = left
+			}
+			continue
+		}
+		offsets = append(offsets, o)
+	}
+	sort.Slice(offsets, func(i, j int) bool {
+		return offsets[i] < offsets[j]
+	})
+
+	for end := g.log.Next(); g.next < end && len(g.pending) < g.b.maxInflight; g.next++ {
+		g.pending[g.next] = &delivery{}
+		offsets = append(offsets, g.next)
+	}
+
+	return offsets, wait
+}
+
+// deliver hands the message at the offset to the next subscriber
+func (g *group) deliver(offset int64) {
+	g.Lock()
+	d, ok := g.pending[offset]
+	if !ok || len(g.subs) == 0 {
+		g.Unlock()
+		return
+	}
+	d.attempts++
+	d.deadline = time.Now().Add(g.b.ackTimeout)
+	sub := g.subs[g.rr%len(g.subs)]
+	g.rr++
+	attempt := d.attempts
+	g.Unlock()
+
+	b, err := g.log.Read(offset)
+	if err == segment.ErrRemoved {
+		// removed by retention before it was acked
+		g.acked(offset)
+		return
+	}
+	if err != nil {
+		log.Errorf("[broker] reading %s at offset %d: %v", g.topic, offset, err)
+		return
+	}
+
+	m := new(message)
+	if err := json.Unmarshal(b, m); err != nil {
+		log.Errorf("[broker] decoding %s at offset %d: %v", g.topic, offset, err)
+		g.acked(offset)
+		return
+	}
+
+	e := &fileEvent{
+		topic:   g.topic,
+		message: &broker.Message{Header: m.Header, Body: m.Body},
+		offset:  offset,
+		attempt: attempt,
+		group:   g,
+	}
+
+	// as for the other brokers an auto acked message is acked whatever the
+	// handler returns, others are redelivered after the ack timeout until acked
+	err = sub.handler(e)
+	if err != nil {
+		log.Errorf("[broker] handling %s at offset %d: %v", g.topic, offset, err)
+	}
+	if sub.opts.AutoAck {
+		e.Ack()
+	}
+}
+
+// acked marks the message at the offset as acked
+func (g *group) acked(offset int64) {
+	g.Lock()
+	delete(g.pending, offset)
+	g.Unlock()
+
+	select {
+	case g.ack <- true:
+	default:
+	}
+}
+
+func (g *group) run() {
+	defer close(g.done)
+
+	for {
+		// take the channel before reading the end of the log
+		changed := g.log.Changed()
+
+		offsets, wait := g.due()
+		for _, o := range offsets {
+			select {
+			case <-g.exit:
+				return
+			default:
+			}
+			g.deliver(o)
+		}
+
+		if err := g.commit(false); err != nil {
+			log.Errorf("[broker] committing offset of %s queue %s: %v", g.topic, g.queue, err)
+		}
+
+		if len(offsets) > 0 {
+			continue
+		}
+
+		select {
+		case <-g.exit:
+			return
+		case <-changed:
+		case <-g.ack:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// stop delivering and commit the offset
+func (g *group) stop() {
+	g.once.Do(func() {
+		close(g.exit)
+	})
+	<-g.done
+
+	if err := g.commit(true); err != nil {
+		log.Errorf("[broker] committing offset of %s queue %s: %v", g.topic, g.queue, err)
+	}
+}
+
+func (e *fileEvent) Topic() string {
+	return e.topic
+}
+
+func (e *fileEvent) Message() *broker.Message {
+	return e.message
+}
+
+// Ack the message so it isn't redelivered
+func (e *fileEvent) Ack() error {
+	e.group.acked(e.offset)
+	return nil
+}
+
+// Offset of the message in the topic log
+func (e *fileEvent) Offset() int64 {
+	return e.offset
+}
+
+// Attempt is the number of times the message has been delivered, starting at one
+func (e *fileEvent) Attempt() int {
+	return e.attempt
+}
+
+func (s *fileSubscriber) Options() broker.SubscribeOptions {
+	return s.opts
+}
+
+func (s *fileSubscriber) Topic() string {
+	return s.topic
+}
+
+func (s *fileSubscriber) Unsubscribe() error {
+	s.group.b.unsubscribe(s)
+	return nil
+}
+
+// NewBroker returns a broker keeping the messages in DefaultDir
+// unless another dir is set
+func NewBroker(opts ...broker.Option) broker.Broker {
+	var options broker.Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	f := &fileBroker{
+		opts:   options,
+		logs:   make(map[string]*segment.Log),
+		groups: make(map[string]*group),
+	}
+	f.configure()
+
+	return f
+}
@@ -0,0 +1,242 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+)
+
+func newBroker(t *testing.T, dir string, opts ...broker.Option) broker.Broker {
+	b := NewBroker(append([]broker.Option{Dir(dir)}, opts...)...)
+	if err := b.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func publish(t *testing.T, b broker.Broker, topic string, from, to int) {
+	for i := from; i < to; i++ {
+		if err := b.Publish(topic, &broker.Message{
+			Header: map[string]string{"id": fmt.Sprintf("%d", i)},
+			Body:   []byte(`hello world`),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// collect subscribes and waits for n messages
+func collect(t *testing.T, b broker.Broker, topic string, n int, opts ...broker.SubscribeOption) (broker.Subscriber, []string) {
+	var mtx sync.Mutex
+	var ids []string
+	done := make(chan bool)
+
+	sub, err := b.Subscribe(topic, func(e broker.Event) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+		ids = append(ids, e.Message().Header["id"])
+		if len(ids) == n {
+			close(done)
+		}
+		return nil
+	}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("Timed out waiting for %d messages", n)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	return sub, ids
+}
+
+func TestFileBrokerQueueResumes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "broker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := newBroker(t, dir)
+	publish(t, b, "test", 0, 5)
+
+	sub, ids := collect(t, b, "test", 5, broker.Queue("q"), broker.StartEarliest())
+	if ids[0] != "0" || ids[4] != "4" {
+		t.Fatalf("Expected messages in order got %v", ids)
+	}
+	sub.Unsubscribe()
+
+	// messages published while the queue is down are kept
+	publish(t, b, "test", 5, 8)
+	b.Disconnect()
+
+	b = newBroker(t, dir)
+	defer b.Disconnect()
+
+	_, ids = collect(t, b, "test", 3, broker.Queue("q"), broker.StartEarliest())
+	if ids[0] != "5" || ids[2] != "7" {
+		t.Fatalf("Expected queue to resume from its offset got %v", ids)
+	}
+
+	// an offset overrides the committed one
+	_, ids = collect(t, b, "test", 2, broker.Queue("other"), broker.StartAt(6))
+	if ids[0] != "6" || ids[1] != "7" {
+		t.Fatalf("Expected messages from offset 6 got %v", ids)
+	}
+}
+
+func TestFileBrokerRedelivery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "broker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := newBroker(t, dir, AckTimeout(time.Millisecond*50))
+	defer b.Disconnect()
+
+	var mtx sync.Mutex
+	attempts := make(map[string]int)
+	done := make(chan bool)
+
+	_, err = b.Subscribe("test", func(e broker.Event) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		id := e.Message().Header["id"]
+		attempts[id]++
+
+		switch {
+		// never acked
+		case id == "0" && attempts[id] < 3:
+			return nil
+		// failed
+		case id == "1" && attempts[id] < 2:
+			return errors.New("failed")
+		}
+
+		e.Ack()
+		if attempts["0"] == 3 && attempts["1"] == 2 {
+			close(done)
+		}
+		return nil
+	}, broker.DisableAutoAck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publish(t, b, "test", 0, 2)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("Expected unacked messages to be redelivered got %v", attempts)
+	}
+}
+
+func TestFileBrokerAutoAckFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "broker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := newBroker(t, dir, AckTimeout(time.Millisecond*50))
+	defer b.Disconnect()
+
+	var mtx sync.Mutex
+	attempts := make(map[string]int)
+	done := make(chan bool)
+
+	sub, err := b.Subscribe("test", func(e broker.Event) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		id := e.Message().Header["id"]
+		attempts[id]++
+		if id == "2" {
+			close(done)
+		}
+		// a poison message
+		if id == "0" {
+			return errors.New("failed")
+		}
+		return nil
+	}, broker.Queue("q"), broker.StartEarliest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publish(t, b, "test", 0, 3)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for messages")
+	}
+
+	// past a few ack timeouts
+	time.Sleep(time.Millisecond * 200)
+
+	mtx.Lock()
+	if attempts["0"] != 1 {
+		t.Fatalf("Expected the failed auto acked message not to be redelivered got %d attempts", attempts["0"])
+	}
+	mtx.Unlock()
+
+	// the committed offset moved past the failed message
+	sub.Unsubscribe()
+	publish(t, b, "test", 3, 4)
+
+	_, ids := collect(t, b, "test", 1, broker.Queue("q"), broker.StartEarliest())
+	if ids[0] != "3" {
+		t.Fatalf("Expected the queue to resume after the failed message got %v", ids)
+	}
+}
+
+func TestFileBrokerQueueShares(t *testing.T) {
+	dir, err := ioutil.TempDir("", "broker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := newBroker(t, dir)
+	defer b.Disconnect()
+
+	var mtx sync.Mutex
+	counts := make(map[int]int)
+	var wg sync.WaitGroup
+	wg.Add(10)
+
+	for i := 0; i < 2; i++ {
+		i := i
+		if _, err := b.Subscribe("test", func(e broker.Event) error {
+			mtx.Lock()
+			counts[i]++
+			mtx.Unlock()
+			wg.Done()
+			return nil
+		}, broker.Queue("q")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publish(t, b, "test", 0, 10)
+	wg.Wait()
+
+	if counts[0] != 5 || counts[1] != 5 {
+		t.Fatalf("Expected messages to be shared by the queue got %v", counts)
+	}
+}
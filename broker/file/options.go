@@ -0,0 +1,56 @@
+package file
+
+import (
+	"context"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+)
+
+type dirKey struct{}
+type segmentSizeKey struct{}
+type maxSegmentsKey struct{}
+type syncKey struct{}
+type ackTimeoutKey struct{}
+type maxInflightKey struct{}
+
+func setOption(k, v interface{}) broker.Option {
+	return func(o *broker.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// Dir sets the directory the topic logs are kept in
+func Dir(d string) broker.Option {
+	return setOption(dirKey{}, d)
+}
+
+// SegmentSize sets the size in bytes a log segment grows to
+func SegmentSize(n int64) broker.Option {
+	return setOption(segmentSizeKey{}, n)
+}
+
+// MaxSegments sets the number of segments kept per topic,
+// the oldest messages are removed first. Zero keeps all.
+func MaxSegments(n int) broker.Option {
+	return setOption(maxSegmentsKey{}, n)
+}
+
+// Sync sets whether every publish is synced to disk before returning
+func Sync(b bool) broker.Option {
+	return setOption(syncKey{}, b)
+}
+
+// AckTimeout sets how long a message may go unacked before it's redelivered
+func AckTimeout(d time.Duration) broker.Option {
+	return setOption(ackTimeoutKey{}, d)
+}
+
+// MaxInflight sets the number of unacked messages of a subscription
+// after which no more are delivered
+func MaxInflight(n int) broker.Option {
+	return setOption(maxInflightKey{}, n)
+}
@@ -35,12 +35,31 @@ type SubscribeOptions struct {
 	// will create a shared subscription where each
 	// receives a subset of messages.
 	Queue string
+	// Position a subscription starts reading from, used
+	// by brokers which keep a log of the messages
+	Position Position
+	// Offset read from first when the position is PositionOffset
+	Offset int64
 
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
 }
 
+// Position of the log a subscription starts from
+type Position int
+
+const (
+	// PositionLatest starts after the last message published. A queue
+	// which has committed an offset before resumes from it instead.
+	PositionLatest Position = iota
+	// PositionEarliest starts from the first message kept. A queue
+	// which has committed an offset before resumes from it instead.
+	PositionEarliest
+	// PositionOffset starts from the given offset
+	PositionOffset
+)
+
 type Option func(*Options)
 
 type PublishOption func(*PublishOptions)
@@ -88,6 +107,31 @@ func DisableAutoAck() SubscribeOption {
 	}
 }
 
+// StartLatest reads the messages published from now on,
+// unless the queue has committed an offset before
+func StartLatest() SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.Position = PositionLatest
+	}
+}
+
+// StartEarliest reads every message kept by the broker,
+// unless the queue has committed an offset before
+func StartEarliest() SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.Position = PositionEarliest
+	}
+}
+
+// StartAt reads the messages from the offset onwards,
+// whatever offset the queue has committed
+func StartAt(offset int64) SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.Position = PositionOffset
+		o.Offset = offset
+	}
+}
+
 // Queue sets the name of the queue to share messages on
 func Queue(name string) SubscribeOption {
 	return func(o *SubscribeOptions) {
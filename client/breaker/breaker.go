@@ -0,0 +1,331 @@
+// Package breaker is a client side circuit breaker
+package breaker
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/registry"
+	errs "github.com/stack-labs/stack/util/errors"
+)
+
+// State of a circuit
+type State int
+
+const (
+	// Closed lets requests through
+	Closed State = iota
+	// Open rejects requests until the cooldown has passed
+	Open
+	// HalfOpen lets a single request through to probe for recovery
+	HalfOpen
+)
+
+var (
+	DefaultThreshold = 5
+	DefaultWindow    = time.Second * 10
+	DefaultCooldown  = time.Second * 30
+
+	// ErrOpen is returned when a circuit is open
+	ErrOpen = errors.New("circuit breaker open")
+	// ErrorId is the id of the errors returned by calls to an open circuit
+	ErrorId = "stack.client.breaker"
+
+	// the breakers created and not closed, reported by the debug handler
+	breakersLock sync.RWMutex
+	breakers     []*Breaker
+
+	// DefaultBreaker is the breaker used when none is specified
+	DefaultBreaker = New()
+)
+
+// Circuit is a snapshot of a circuit
+type Circuit struct {
+	// Name of the circuit e.g. service/endpoint
+	Name string
+	// State of the circuit
+	State State
+	// Consecutive failures
+	Failures int
+	// Requests and errors within the current window
+	Requests int
+	Errors   int
+	// Time the circuit was last opened
+	Opened time.Time
+}
+
+type circuit struct {
+	state    State
+	failures int
+	requests int
+	errors   int
+	window   time.Time
+	opened   time.Time
+	// time the half open probe was reserved at, zero if none is in flight
+	probing time.Time
+}
+
+// Breaker keeps a circuit per name. Circuits trip open after consecutive
+// failures or a high error rate, are half opened after the cooldown and
+// closed again by a successful request.
+type Breaker struct {
+	opts Options
+
+	sync.Mutex
+	circuits map[string]*circuit
+}
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// IsOpen reports whether the error was returned for a call to an open circuit
+func IsOpen(err error) bool {
+	if err == nil {
+		return false
+	}
+	if err == ErrOpen {
+		return true
+	}
+	return errs.Parse(err.Error()).Id == ErrorId
+}
+
+// isFailure counts the failures of errors.IsFailure, timeouts included,
+// but not the errors of open circuits
+func isFailure(err error) bool {
+	return !IsOpen(err) && errs.IsFailure(err)
+}
+
+// available reports whether a circuit would let a request through,
+// without changing it. A probe reserved but never marked is released
+// after the cooldown.
+func (b *Breaker) available(c *circuit, now time.Time) bool {
+	switch c.state {
+	case Open:
+		return now.Sub(c.opened) >= b.opts.Cooldown
+	case HalfOpen:
+		return c.probing.IsZero() || now.Sub(c.probing) >= b.opts.Cooldown
+	}
+	return true
+}
+
+// ready reports whether a circuit lets requests through. An open
+// circuit which has cooled down is moved to half open.
+func (b *Breaker) ready(c *circuit, now time.Time) bool {
+	if !b.available(c, now) {
+		return false
+	}
+	if c.state == Open {
+		c.state = HalfOpen
+		c.probing = time.Time{}
+	}
+	return true
+}
+
+func (b *Breaker) trip(c *circuit) bool {
+	if b.opts.Threshold > 0 && c.failures >= b.opts.Threshold {
+		return true
+	}
+	if b.opts.ErrorRate > 0 && c.requests > 0 && c.requests >= b.opts.MinRequests {
+		return float64(c.errors)/float64(c.requests) >= b.opts.ErrorRate
+	}
+	return false
+}
+
+// Allow reserves a request against all the named circuits. ErrOpen is
+// returned, and nothing reserved, if any of them are open. Every allowed
+// request must be followed by a Mark of each circuit.
+func (b *Breaker) Allow(names ...string) error {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+
+	for _, name := range names {
+		c, ok := b.circuits[name]
+		if !ok {
+			continue
+		}
+		if !b.ready(c, now) {
+			return ErrOpen
+		}
+	}
+
+	for _, name := range names {
+		if c, ok := b.circuits[name]; ok && c.state == HalfOpen {
+			c.probing = now
+		}
+	}
+
+	return nil
+}
+
+// Ready reports whether the named circuit lets a request through. As for
+// Allow the single probe of a half open circuit is reserved until a Mark
+func (b *Breaker) Ready(name string) bool {
+	return b.Allow(name) == nil
+}
+
+// Available reports whether the named circuit would let a request through,
+// without reserving the probe of a half open circuit
+func (b *Breaker) Available(name string) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	c, ok := b.circuits[name]
+	return !ok || b.available(c, time.Now())
+}
+
+// Mark records the result of a request against the named circuit
+func (b *Breaker) Mark(name string, err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	failure := err != nil && b.opts.Failure(err)
+
+	c, ok := b.circuits[name]
+	if !ok {
+		c = &circuit{window: now}
+		b.circuits[name] = c
+	}
+
+	if b.opts.Window > 0 && now.Sub(c.window) > b.opts.Window {
+		c.requests = 0
+		c.errors = 0
+		c.window = now
+	}
+
+	c.requests++
+	if failure {
+		c.errors++
+		c.failures++
+	} else {
+		c.failures = 0
+	}
+
+	switch c.state {
+	case HalfOpen:
+		c.probing = time.Time{}
+		if failure {
+			c.state = Open
+			c.opened = now
+			return
+		}
+		c.state = Closed
+		c.requests = 0
+		c.errors = 0
+		c.window = now
+	case Closed:
+		if failure && b.trip(c) {
+			c.state = Open
+			c.opened = now
+		}
+	}
+}
+
+// Reset closes the named circuit
+func (b *Breaker) Reset(name string) {
+	b.Lock()
+	delete(b.circuits, name)
+	b.Unlock()
+}
+
+// Circuits returns a snapshot of the circuits sorted by name
+func (b *Breaker) Circuits() []*Circuit {
+	b.Lock()
+	defer b.Unlock()
+
+	circuits := make([]*Circuit, 0, len(b.circuits))
+	for name, c := range b.circuits {
+		circuits = append(circuits, &Circuit{
+			Name:     name,
+			State:    c.state,
+			Failures: c.failures,
+			Requests: c.requests,
+			Errors:   c.errors,
+			Opened:   c.opened,
+		})
+	}
+
+	sort.Slice(circuits, func(i, j int) bool {
+		return circuits[i].Name < circuits[j].Name
+	})
+
+	return circuits
+}
+
+// Close removes the breaker from Breakers
+func (b *Breaker) Close() {
+	breakersLock.Lock()
+	defer breakersLock.Unlock()
+
+	for i, br := range breakers {
+		if br == b {
+			breakers = append(breakers[:i], breakers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Options returns the options of the breaker
+func (b *Breaker) Options() Options {
+	return b.opts
+}
+
+// EndpointName is the name of the circuit of a service endpoint
+func EndpointName(service, endpoint string) string {
+	return service + "/" + endpoint
+}
+
+// NodeName is the name of the circuit of a service node
+func NodeName(service string, node *registry.Node) string {
+	id := node.Id
+	if len(id) == 0 {
+		id = node.Address
+	}
+	return service + "@" + id
+}
+
+// New returns a breaker, reported by Breakers until it's closed. By
+// default a circuit trips after DefaultThreshold consecutive failures.
+func New(opts ...Option) *Breaker {
+	options := Options{
+		Threshold: DefaultThreshold,
+		Window:    DefaultWindow,
+		Cooldown:  DefaultCooldown,
+		Failure:   isFailure,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	b := &Breaker{
+		opts:     options,
+		circuits: make(map[string]*circuit),
+	}
+
+	breakersLock.Lock()
+	breakers = append(breakers, b)
+	breakersLock.Unlock()
+
+	return b
+}
+
+// Breakers returns the breakers created and not closed
+func Breakers() []*Breaker {
+	breakersLock.RLock()
+	defer breakersLock.RUnlock()
+	return append([]*Breaker{}, breakers...)
+}
@@ -0,0 +1,252 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/codec"
+	"github.com/stack-labs/stack/registry"
+	errs "github.com/stack-labs/stack/util/errors"
+)
+
+func TestBreakerThreshold(t *testing.T) {
+	b := New(Threshold(3), Cooldown(50*time.Millisecond))
+	fail := errors.New("connection refused")
+
+	for i := 0; i < 3; i++ {
+		if err := b.Allow("foo"); err != nil {
+			t.Fatalf("Expected circuit to be closed after %d failures", i)
+		}
+		b.Mark("foo", fail)
+	}
+
+	if err := b.Allow("foo"); err != ErrOpen {
+		t.Fatalf("Expected %v got %v", ErrOpen, err)
+	}
+
+	// errors returned for bad requests don't count
+	b.Mark("bar", errs.NotFound("bar", "not found"))
+	b.Mark("bar", errs.NotFound("bar", "not found"))
+	b.Mark("bar", errs.NotFound("bar", "not found"))
+	if err := b.Allow("bar"); err != nil {
+		t.Fatalf("Expected circuit to be closed got %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	// a single probe is let through once cooled down
+	if err := b.Allow("foo"); err != nil {
+		t.Fatalf("Expected half open circuit to allow a probe got %v", err)
+	}
+	if err := b.Allow("foo"); err != ErrOpen {
+		t.Fatalf("Expected half open circuit to reject got %v", err)
+	}
+
+	b.Mark("foo", fail)
+	if c := b.Circuits()[1]; c.Name != "foo" || c.State != Open {
+		t.Fatalf("Expected failed probe to open circuit got %+v", c)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := b.Allow("foo"); err != nil {
+		t.Fatal(err)
+	}
+	b.Mark("foo", nil)
+
+	if c := b.Circuits()[1]; c.State != Closed {
+		t.Fatalf("Expected successful probe to close circuit got %v", c.State)
+	}
+}
+
+func TestBreakerErrorRate(t *testing.T) {
+	b := New(Threshold(0), ErrorRate(0.5, 4))
+	fail := errors.New("connection refused")
+
+	b.Mark("foo", fail)
+	b.Mark("foo", nil)
+	b.Mark("foo", fail)
+
+	if err := b.Allow("foo"); err != nil {
+		t.Fatalf("Expected circuit to be closed below min requests got %v", err)
+	}
+
+	b.Mark("foo", fail)
+
+	if err := b.Allow("foo", "bar"); err != ErrOpen {
+		t.Fatalf("Expected %v got %v", ErrOpen, err)
+	}
+}
+
+func TestBreakerReady(t *testing.T) {
+	b := New(Threshold(1), Cooldown(50*time.Millisecond))
+	b.Mark("foo", errors.New("connection refused"))
+
+	if b.Ready("foo") {
+		t.Fatal("Expected open circuit not to be ready")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	// a single probe passes the filter of concurrent calls
+	if !b.Ready("foo") {
+		t.Fatal("Expected half open circuit to let a probe through")
+	}
+	if b.Ready("foo") {
+		t.Fatal("Expected the probe to be reserved")
+	}
+
+	// a probe never marked is released after the cooldown
+	time.Sleep(50 * time.Millisecond)
+	if !b.Ready("foo") {
+		t.Fatal("Expected the lost probe to be released")
+	}
+}
+
+func TestBreakerIsOpen(t *testing.T) {
+	b := New(Threshold(1))
+	b.Mark("foo/Foo.Bar", errors.New("connection refused"))
+
+	err := b.Allow("foo/Foo.Bar")
+	if !IsOpen(err) || !IsOpen(errs.ServiceUnavailable(ErrorId, "%v", err)) {
+		t.Fatalf("Expected an open circuit error got %v", err)
+	}
+	if IsOpen(errs.ServiceUnavailable("foo", "upstream unavailable")) {
+		t.Fatal("Expected an upstream 503 not to be an open circuit")
+	}
+
+	// open circuits don't count against others
+	b.Mark("bar", errs.ServiceUnavailable(ErrorId, "%v", err))
+	if err := b.Allow("bar"); err != nil {
+		t.Fatalf("Expected circuit to be closed got %v", err)
+	}
+
+	found := false
+	for _, br := range Breakers() {
+		found = found || br == b
+	}
+	if !found {
+		t.Fatal("Expected the breaker to be registered")
+	}
+
+	b.Close()
+	for _, br := range Breakers() {
+		if br == b {
+			t.Fatal("Expected the closed breaker to be removed")
+		}
+	}
+}
+
+func TestFilterProbe(t *testing.T) {
+	b := New(Threshold(1), Cooldown(10*time.Millisecond))
+	defer b.Close()
+
+	nodes := []*registry.Node{{Id: "foo-1"}, {Id: "foo-2"}}
+	for _, node := range nodes {
+		b.Mark(NodeName("foo", node), errors.New("connection refused"))
+	}
+
+	services := []*registry.Service{{Name: "foo", Nodes: nodes}}
+	if s := Filter(b, "foo")(services); len(s) != 0 {
+		t.Fatalf("Expected the open nodes to be filtered got %v", s)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	// filtering doesn't reserve the probes of the half open nodes
+	for i := 0; i < 2; i++ {
+		if s := Filter(b, "foo")(services); len(s) != 1 || len(s[0].Nodes) != 2 {
+			t.Fatalf("Expected the cooled down nodes to be kept got %v", s)
+		}
+	}
+
+	// the probe of the node selected is reserved
+	if !b.Ready(NodeName("foo", nodes[0])) {
+		t.Fatal("Expected the probe to be reserved")
+	}
+	s := Filter(b, "foo")(services)
+	if len(s) != 1 || len(s[0].Nodes) != 1 || s[0].Nodes[0].Id != "foo-2" {
+		t.Fatalf("Expected the node probing to be filtered got %v", s)
+	}
+}
+
+type testRequest struct {
+	service  string
+	endpoint string
+}
+
+func newTestRequest(service, endpoint string) client.Request {
+	return &testRequest{service: service, endpoint: endpoint}
+}
+
+func (r *testRequest) Service() string     { return r.service }
+func (r *testRequest) Method() string      { return r.endpoint }
+func (r *testRequest) Endpoint() string    { return r.endpoint }
+func (r *testRequest) ContentType() string { return "" }
+func (r *testRequest) Body() interface{}   { return nil }
+func (r *testRequest) Codec() codec.Writer { return nil }
+func (r *testRequest) Stream() bool        { return false }
+
+func TestCallWrapper(t *testing.T) {
+	b := New(Threshold(2))
+
+	calls := 0
+	call := NewCallWrapper(b)(func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		calls++
+		return errors.New("connection refused")
+	})
+
+	req := newTestRequest("foo", "Foo.Bar")
+	for i := 0; i < 2; i++ {
+		if err := call(context.Background(), nil, req, nil, client.CallOptions{}); IsOpen(err) {
+			t.Fatalf("Expected call %d to be let through got %v", i, err)
+		}
+	}
+
+	// the open circuit fails the call without calling the service
+	err := call(context.Background(), nil, req, nil, client.CallOptions{})
+	if !IsOpen(err) || errs.Parse(err.Error()).Code != 503 {
+		t.Fatalf("Expected an open circuit error got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("Expected 2 calls got %d", calls)
+	}
+
+	// circuits are kept per endpoint
+	if err := call(context.Background(), nil, newTestRequest("foo", "Foo.Baz"), nil, client.CallOptions{}); IsOpen(err) {
+		t.Fatalf("Expected the circuit of another endpoint to be closed got %v", err)
+	}
+}
+
+func TestBreakerTimeout(t *testing.T) {
+	b := New(Threshold(2))
+
+	// a hung service times out the calls, which count as failures
+	call := NewCallWrapper(b)(func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		<-ctx.Done()
+		return errs.Timeout("stack.rpc.client", "%v", ctx.Err())
+	})
+
+	req := newTestRequest("foo", "Foo.Bar")
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		err := call(ctx, nil, req, nil, client.CallOptions{})
+		cancel()
+		if errs.Parse(err.Error()).Code != 408 {
+			t.Fatalf("Expected a timeout got %v", err)
+		}
+	}
+
+	if err := b.Allow(EndpointName("foo", "Foo.Bar")); err != ErrOpen {
+		t.Fatalf("Expected timeouts to open the circuit got %v", err)
+	}
+
+	b.Mark("bar", context.DeadlineExceeded)
+	b.Mark("bar", context.DeadlineExceeded)
+	if err := b.Allow("bar"); err != ErrOpen {
+		t.Fatalf("Expected deadline errors to open the circuit got %v", err)
+	}
+}
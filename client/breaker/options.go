@@ -0,0 +1,62 @@
+package breaker
+
+import (
+	"time"
+)
+
+type Options struct {
+	// Threshold is the number of consecutive failures
+	// which trips a circuit, zero disables it
+	Threshold int
+	// ErrorRate between 0 and 1 within the window
+	// which trips a circuit, zero disables it
+	ErrorRate float64
+	// MinRequests within the window before
+	// the error rate is considered
+	MinRequests int
+	// Window the error rate is measured over
+	Window time.Duration
+	// Cooldown before an open circuit is half opened
+	Cooldown time.Duration
+	// Failure reports whether an error counts against a circuit
+	Failure func(err error) bool
+}
+
+type Option func(*Options)
+
+// Threshold sets the number of consecutive failures which trips a circuit
+func Threshold(n int) Option {
+	return func(o *Options) {
+		o.Threshold = n
+	}
+}
+
+// ErrorRate trips a circuit once the rate of failures within the window
+// reaches rate, provided at least min requests were made
+func ErrorRate(rate float64, min int) Option {
+	return func(o *Options) {
+		o.ErrorRate = rate
+		o.MinRequests = min
+	}
+}
+
+// Window sets the period the error rate is measured over
+func Window(d time.Duration) Option {
+	return func(o *Options) {
+		o.Window = d
+	}
+}
+
+// Cooldown sets how long a circuit stays open before a request is let through
+func Cooldown(d time.Duration) Option {
+	return func(o *Options) {
+		o.Cooldown = d
+	}
+}
+
+// Failure sets the func deciding which errors count against a circuit
+func Failure(fn func(err error) bool) Option {
+	return func(o *Options) {
+		o.Failure = fn
+	}
+}
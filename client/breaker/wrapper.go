@@ -0,0 +1,57 @@
+package breaker
+
+import (
+	"context"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/selector"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+// NewCallWrapper returns a client.CallWrapper which trips a circuit per
+// service endpoint. Calls to an open circuit fail with a 503 of ErrorId,
+// checked by IsOpen.
+func NewCallWrapper(b *Breaker) client.CallWrapper {
+	return func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			name := EndpointName(req.Service(), req.Endpoint())
+
+			if err := b.Allow(name); err != nil {
+				return errors.ServiceUnavailable(ErrorId, "%s: %v", name, err)
+			}
+
+			err := cf(ctx, node, req, rsp, opts)
+			b.Mark(name, err)
+
+			return err
+		}
+	}
+}
+
+// Filter returns a selector.Filter which drops the nodes of the service
+// whose circuit is open or whose probe is in flight. The probe is only
+// reserved by the Allow of the node selected.
+func Filter(b *Breaker, service string) selector.Filter {
+	return func(old []*registry.Service) []*registry.Service {
+		var services []*registry.Service
+
+		for _, srv := range old {
+			s := new(registry.Service)
+			*s = *srv
+			s.Nodes = nil
+
+			for _, node := range srv.Nodes {
+				if b.Available(NodeName(service, node)) {
+					s.Nodes = append(s.Nodes, node)
+				}
+			}
+
+			if len(s.Nodes) > 0 {
+				services = append(services, s)
+			}
+		}
+
+		return services
+	}
+}
@@ -320,6 +320,11 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 		opt(&callOpts)
 	}
 
+	// pin calls carrying a hash key to a node
+	if key, ok := selector.HashKey(ctx); ok {
+		client.WithSelectOption(selector.WithStrategy(selector.ConsistentHash(key)))(&callOpts)
+	}
+
 	// check if we already have a deadline
 	d, ok := ctx.Deadline()
 	if !ok {
@@ -373,7 +378,11 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 		}
 
 		// make the call
+		start := time.Now()
 		err = gcall(ctx, node, req, rsp, callOpts)
+		if o, ok := g.opts.Selector.(selector.Observer); ok {
+			o.Observe(service, node, time.Since(start))
+		}
 		g.opts.Selector.Mark(service, node, err)
 		return err
 	}
@@ -418,6 +427,11 @@ func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...cli
 		opt(&callOpts)
 	}
 
+	// pin calls carrying a hash key to a node
+	if key, ok := selector.HashKey(ctx); ok {
+		client.WithSelectOption(selector.WithStrategy(selector.ConsistentHash(key)))(&callOpts)
+	}
+
 	// #200 - streams shouldn't have a request timeout set on the context
 
 	// should we noop right here?
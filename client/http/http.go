@@ -215,6 +215,11 @@ func (h *httpClient) Call(ctx context.Context, req client.Request, rsp interface
 		opt(&callOpts)
 	}
 
+	// pin calls carrying a hash key to a node
+	if key, ok := selector.HashKey(ctx); ok {
+		client.WithSelectOption(selector.WithStrategy(selector.ConsistentHash(key)))(&callOpts)
+	}
+
 	// check if we already have a deadline
 	d, ok := ctx.Deadline()
 	if !ok {
@@ -264,7 +269,11 @@ func (h *httpClient) Call(ctx context.Context, req client.Request, rsp interface
 		}
 
 		// make the call
+		start := time.Now()
 		err = hcall(ctx, node, req, rsp, callOpts)
+		if o, ok := h.opts.Selector.(selector.Observer); ok {
+			o.Observe(req.Service(), node, time.Since(start))
+		}
 		h.opts.Selector.Mark(req.Service(), node, err)
 		return err
 	}
@@ -309,6 +318,11 @@ func (h *httpClient) Stream(ctx context.Context, req client.Request, opts ...cli
 		opt(&callOpts)
 	}
 
+	// pin calls carrying a hash key to a node
+	if key, ok := selector.HashKey(ctx); ok {
+		client.WithSelectOption(selector.WithStrategy(selector.ConsistentHash(key)))(&callOpts)
+	}
+
 	// check if we already have a deadline
 	d, ok := ctx.Deadline()
 	if !ok {
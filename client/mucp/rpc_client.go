@@ -383,6 +383,11 @@ func (r *rpcClient) Call(ctx context.Context, request client.Request, response i
 		opt(&callOpts)
 	}
 
+	// pin calls carrying a hash key to a node
+	if key, ok := selector.HashKey(ctx); ok {
+		client.WithSelectOption(selector.WithStrategy(selector.ConsistentHash(key)))(&callOpts)
+	}
+
 	// check if we already have a deadline
 	d, ok := ctx.Deadline()
 	if !ok {
@@ -436,7 +441,11 @@ func (r *rpcClient) Call(ctx context.Context, request client.Request, response i
 		}
 
 		// make the call
+		start := time.Now()
 		err = rcall(ctx, node, request, response, callOpts)
+		if o, ok := r.opts.Selector.(selector.Observer); ok {
+			o.Observe(service, node, time.Since(start))
+		}
 		r.opts.Selector.Mark(service, node, err)
 		return err
 	}
@@ -489,6 +498,11 @@ func (r *rpcClient) Stream(ctx context.Context, request client.Request, opts ...
 		opt(&callOpts)
 	}
 
+	// pin calls carrying a hash key to a node
+	if key, ok := selector.HashKey(ctx); ok {
+		client.WithSelectOption(selector.WithStrategy(selector.ConsistentHash(key)))(&callOpts)
+	}
+
 	// should we noop right here?
 	select {
 	case <-ctx.Done():
@@ -0,0 +1,32 @@
+package selector
+
+import (
+	"context"
+
+	"github.com/stack-labs/stack/pkg/metadata"
+)
+
+var (
+	// HashKeyHeader is the metadata key of the consistent hashing key
+	HashKeyHeader = "Stack-Hash-Key"
+)
+
+type hashKey struct{}
+
+// WithHashKey returns a context carrying the key the node of a call
+// is picked with by the ConsistentHash strategy
+func WithHashKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, hashKey{}, key)
+}
+
+// HashKey returns the consistent hashing key of the context,
+// set by WithHashKey or in the HashKeyHeader metadata
+func HashKey(ctx context.Context) (string, bool) {
+	if key, ok := ctx.Value(hashKey{}).(string); ok && len(key) > 0 {
+		return key, true
+	}
+	if key, ok := metadata.Get(ctx, HashKeyHeader); ok && len(key) > 0 {
+		return key, true
+	}
+	return "", false
+}
@@ -10,6 +10,10 @@ type Options struct {
 	Name     string
 	Registry registry.Registry
 	Strategy Strategy
+	// Tracker of the outstanding requests of nodes
+	Tracker *Tracker
+	// Stats of the latency and errors of nodes
+	Stats *Stats
 
 	// Other options for implementations of the interface
 	// can be stored in a context
@@ -51,6 +55,20 @@ func SetStrategy(fn Strategy) Option {
 	}
 }
 
+// Track sets the tracker of the outstanding requests used by LeastConn
+func Track(t *Tracker) Option {
+	return func(o *Options) {
+		o.Tracker = t
+	}
+}
+
+// Collect sets the stats of the latency and errors of nodes used by P2C
+func Collect(s *Stats) Option {
+	return func(o *Options) {
+		o.Stats = s
+	}
+}
+
 // WithFilter adds a filter function to the list of filters
 // used during the Next call.
 func WithFilter(fn ...Filter) SelectOption {
@@ -4,9 +4,12 @@ import (
 	"context"
 	"time"
 
+	"github.com/stack-labs/stack/client/breaker"
 	"github.com/stack-labs/stack/client/selector"
 )
 
+type breakerKey struct{}
+
 // Set the registry cache ttl
 func TTL(t time.Duration) selector.Option {
 	return func(o *selector.Options) {
@@ -16,3 +19,14 @@ func TTL(t time.Duration) selector.Option {
 		o.Context = context.WithValue(o.Context, "selector_ttl", t)
 	}
 }
+
+// Breaker trips a circuit per node on the errors passed to Mark.
+// Nodes whose circuit is open are skipped by Next.
+func Breaker(b *breaker.Breaker) selector.Option {
+	return func(o *selector.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, breakerKey{}, b)
+	}
+}
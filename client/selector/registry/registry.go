@@ -1,8 +1,10 @@
 package registry
 
 import (
+	"strings"
 	"time"
 
+	"github.com/stack-labs/stack/client/breaker"
 	"github.com/stack-labs/stack/client/selector"
 	"github.com/stack-labs/stack/registry"
 	"github.com/stack-labs/stack/registry/cache"
@@ -23,6 +25,14 @@ func (c *registrySelector) newCache() cache.Cache {
 	return cache.New(c.opts.Registry, opts...)
 }
 
+func (c *registrySelector) breaker() *breaker.Breaker {
+	if c.opts.Context == nil {
+		return nil
+	}
+	b, _ := c.opts.Context.Value(breakerKey{}).(*breaker.Breaker)
+	return b
+}
+
 func (c *registrySelector) Init(opts ...selector.Option) error {
 	for _, o := range opts {
 		o(&c.opts)
@@ -63,18 +73,82 @@ func (c *registrySelector) Next(service string, opts ...selector.SelectOption) (
 		services = filter(services)
 	}
 
-	// if there's nothing left, return
-	if len(services) == 0 {
-		return nil, selector.ErrNoneAvailable
+	node, err := c.next(service, services, sopts.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.opts.Tracker != nil {
+		c.opts.Tracker.Acquire(node)
+	}
+
+	if c.opts.Stats != nil {
+		c.opts.Stats.Acquire(node)
 	}
 
-	return sopts.Strategy(services)
+	return node, nil
+}
+
+// next selects a node of the services, skipping the nodes with an open
+// circuit. The probe of a half open circuit is reserved for the node
+// selected only, a node whose probe was taken meanwhile is selected again.
+func (c *registrySelector) next(service string, services []*registry.Service, strategy selector.Strategy) (*registry.Node, error) {
+	b := c.breaker()
+
+	for {
+		candidates := services
+		if b != nil {
+			candidates = breaker.Filter(b, service)(services)
+		}
+
+		// if there's nothing left, return
+		if len(candidates) == 0 {
+			return nil, selector.ErrNoneAvailable
+		}
+
+		node, err := strategy(candidates)
+		if err != nil {
+			return nil, err
+		}
+
+		if b == nil || b.Allow(breaker.NodeName(service, node)) == nil {
+			return node, nil
+		}
+	}
 }
 
 func (c *registrySelector) Mark(service string, node *registry.Node, err error) {
+	if c.opts.Tracker != nil {
+		c.opts.Tracker.Release(node)
+	}
+
+	if c.opts.Stats != nil {
+		c.opts.Stats.Mark(node, err)
+	}
+
+	if b := c.breaker(); b != nil {
+		b.Mark(breaker.NodeName(service, node), err)
+	}
+}
+
+// Observe records the latency of a call to the node in the stats
+func (c *registrySelector) Observe(service string, node *registry.Node, d time.Duration) {
+	if c.opts.Stats != nil {
+		c.opts.Stats.Observe(node, d)
+	}
 }
 
 func (c *registrySelector) Reset(service string) {
+	b := c.breaker()
+	if b == nil {
+		return
+	}
+
+	for _, circuit := range b.Circuits() {
+		if strings.HasPrefix(circuit.Name, service+"@") {
+			b.Reset(circuit.Name)
+		}
+	}
 }
 
 // Close stops the watcher and destroys the cache
@@ -1,8 +1,11 @@
 package registry
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/stack-labs/stack/client/breaker"
 	"github.com/stack-labs/stack/client/selector"
 	"github.com/stack-labs/stack/registry"
 
@@ -67,3 +70,84 @@ func TestRegistrySelector(t *testing.T) {
 
 	t.Logf("Selector Counts %v", counts)
 }
+
+func TestRegistrySelectorBreaker(t *testing.T) {
+	r := memory.NewRegistry(memory.Services(testData))
+	b := breaker.New(breaker.Threshold(1), breaker.Cooldown(time.Hour))
+	cache := NewSelector(selector.Registry(r), Breaker(b))
+
+	node := &registry.Node{Id: "foo-1.0.0-123"}
+	cache.Mark("foo", node, errors.New("connection refused"))
+
+	for i := 0; i < 100; i++ {
+		n, err := cache.Next("foo")
+		if err != nil {
+			t.Fatalf("Expected node err, got err: %v", err)
+		}
+		if n.Id == node.Id {
+			t.Fatalf("Expected node %s to be skipped", node.Id)
+		}
+	}
+
+	cache.Reset("foo")
+
+	if !b.Ready(breaker.NodeName("foo", node)) {
+		t.Fatal("Expected circuit to be closed after reset")
+	}
+}
+
+func TestRegistrySelectorP2C(t *testing.T) {
+	r := memory.NewRegistry(memory.Services(testData))
+	st := selector.NewStats(selector.EjectFailures(3), selector.EjectTime(time.Hour))
+	cache := NewSelector(selector.Registry(r), selector.SetStrategy(selector.P2C(st)), selector.Collect(st))
+
+	o, ok := cache.(selector.Observer)
+	if !ok {
+		t.Fatal("Expected registry selector to observe latency")
+	}
+
+	latency := map[string]time.Duration{
+		"foo-1.0.0-123": time.Millisecond * 500,
+		"foo-1.0.0-321": time.Millisecond,
+		"foo-1.0.1-321": time.Millisecond,
+	}
+	counts := map[string]int{}
+
+	for i := 0; i < 300; i++ {
+		node, err := cache.Next("foo")
+		if err != nil {
+			t.Fatalf("Expected node err, got err: %v", err)
+		}
+		counts[node.Id]++
+		o.Observe("foo", node, latency[node.Id])
+		cache.Mark("foo", node, nil)
+	}
+
+	// the slow node only wins when it's picked twice, or once its
+	// latency has decayed, which takes longer than the test
+	if counts["foo-1.0.0-123"] > 1 {
+		t.Fatalf("Expected slow node to be avoided got %v", counts)
+	}
+
+	// eject a failing node
+	node := &registry.Node{Id: "foo-1.0.1-321"}
+	for i := 0; i < 3; i++ {
+		st.Acquire(node)
+		cache.Mark("foo", node, errors.New("connection refused"))
+	}
+
+	if !st.Ejected(node) {
+		t.Fatal("Expected failing node to be ejected")
+	}
+
+	for i := 0; i < 100; i++ {
+		n, err := cache.Next("foo")
+		if err != nil {
+			t.Fatalf("Expected node err, got err: %v", err)
+		}
+		if n.Id == node.Id {
+			t.Fatalf("Expected node %s to be ejected", node.Id)
+		}
+		cache.Mark("foo", n, nil)
+	}
+}
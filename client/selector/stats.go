@@ -0,0 +1,344 @@
+package selector
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+var (
+	// DefaultDecay is the time it takes the moving averages to forget a sample
+	DefaultDecay = time.Second * 10
+	// DefaultPenalty is the cost of a node with requests in flight but no latency yet
+	DefaultPenalty = time.Second
+	// DefaultEjectFailures is the number of consecutive failures which ejects a node
+	DefaultEjectFailures = 5
+	// DefaultEjectTime is how long a node is ejected for the first time.
+	// Each further ejection in a row lasts one DefaultEjectTime longer.
+	DefaultEjectTime = time.Second * 30
+	// DefaultMaxEjectPercent is the most nodes of a service ejected at once
+	DefaultMaxEjectPercent = 50
+)
+
+// Observer is implemented by selectors which take the latency of calls into
+// account. Clients call Observe with the latency of a call before Mark.
+type Observer interface {
+	Observe(service string, node *registry.Node, d time.Duration)
+}
+
+type StatsOptions struct {
+	// Decay is the time constant of the moving averages
+	Decay time.Duration
+	// Penalty is the cost of a node with requests in
+	// flight which hasn't reported a latency yet
+	Penalty time.Duration
+	// EjectFailures is the number of consecutive failures
+	// which ejects a node, zero disables it
+	EjectFailures int
+	// EjectRate between 0 and 1 is the error rate which
+	// ejects a node, zero disables it
+	EjectRate float64
+	// EjectMinRequests is the number of requests a node must
+	// have had before its error rate is considered
+	EjectMinRequests int
+	// EjectTime is the base time a node is ejected for
+	EjectTime time.Duration
+	// MaxEjectPercent is the most nodes of a service ejected at once
+	MaxEjectPercent int
+	// Failure reports whether an error counts against a node
+	Failure func(err error) bool
+}
+
+type StatsOption func(*StatsOptions)
+
+// Stats keeps an exponentially weighted moving average of the latency and
+// error rate of nodes, along with their requests in flight. Nodes which fail
+// repeatedly are ejected for a while. It is used by the P2C strategy.
+type Stats struct {
+	opts StatsOptions
+
+	sync.Mutex
+	nodes map[string]*nodeStats
+}
+
+type nodeStats struct {
+	// latency in nanoseconds
+	latency  float64
+	errors   float64
+	pending  int64
+	requests int
+	failures int
+	updated  time.Time
+	// time the latency was last observed
+	observed time.Time
+	// ejected until
+	ejected   time.Time
+	ejections int
+}
+
+// NodeStats is a snapshot of the stats of a node
+type NodeStats struct {
+	Latency   time.Duration
+	ErrorRate float64
+	Pending   int64
+	Ejected   bool
+}
+
+// Decay sets the time constant of the moving averages
+func Decay(d time.Duration) StatsOption {
+	return func(o *StatsOptions) {
+		o.Decay = d
+	}
+}
+
+// Penalty sets the cost of a node with requests in flight but no latency yet
+func Penalty(d time.Duration) StatsOption {
+	return func(o *StatsOptions) {
+		o.Penalty = d
+	}
+}
+
+// EjectFailures ejects a node after n consecutive failures
+func EjectFailures(n int) StatsOption {
+	return func(o *StatsOptions) {
+		o.EjectFailures = n
+	}
+}
+
+// EjectRate ejects a node once its error rate reaches rate,
+// provided it has had at least min requests
+func EjectRate(rate float64, min int) StatsOption {
+	return func(o *StatsOptions) {
+		o.EjectRate = rate
+		o.EjectMinRequests = min
+	}
+}
+
+// EjectTime sets the base time a node is ejected for
+func EjectTime(d time.Duration) StatsOption {
+	return func(o *StatsOptions) {
+		o.EjectTime = d
+	}
+}
+
+// MaxEjectPercent sets the most nodes of a service ejected at once
+func MaxEjectPercent(p int) StatsOption {
+	return func(o *StatsOptions) {
+		o.MaxEjectPercent = p
+	}
+}
+
+func (s *Stats) get(node *registry.Node) *nodeStats {
+	key := trackerKey(node)
+	n, ok := s.nodes[key]
+	if !ok {
+		n = &nodeStats{}
+		s.nodes[key] = n
+	}
+	return n
+}
+
+// decay returns the weight of the old average after the time since the last update
+func (s *Stats) decay(n *nodeStats, now time.Time) float64 {
+	if n.updated.IsZero() || s.opts.Decay <= 0 {
+		return 0
+	}
+	return math.Exp(-float64(now.Sub(n.updated)) / float64(s.opts.Decay))
+}
+
+// latency returns the latency of the node decayed toward zero by the time
+// since it was last observed, so a node isn't judged on an old peak forever
+func (s *Stats) latency(n *nodeStats, now time.Time) float64 {
+	if n.observed.IsZero() || s.opts.Decay <= 0 {
+		return n.latency
+	}
+	return n.latency * math.Exp(-float64(now.Sub(n.observed))/float64(s.opts.Decay))
+}
+
+// Acquire counts a request made to the node
+func (s *Stats) Acquire(node *registry.Node) {
+	s.Lock()
+	s.get(node).pending++
+	s.Unlock()
+}
+
+// Observe records the latency of a request to the node as a peak EWMA.
+// Latency peaks are taken as is so a node slowing down is noticed straight
+// away, and decay with time so a slow node is tried again later.
+func (s *Stats) Observe(node *registry.Node, d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	n := s.get(node)
+	sample := float64(d)
+
+	switch {
+	case n.observed.IsZero() || s.opts.Decay <= 0:
+		n.latency = sample
+	case sample > s.latency(n, now):
+		n.latency = sample
+	default:
+		w := math.Exp(-float64(now.Sub(n.observed)) / float64(s.opts.Decay))
+		n.latency = n.latency*w + sample*(1-w)
+	}
+	n.observed = now
+}
+
+// Mark records the result of a request to the node, ejecting it once it
+// has failed too often
+func (s *Stats) Mark(node *registry.Node, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	n := s.get(node)
+
+	if n.pending > 0 {
+		n.pending--
+	}
+
+	var sample float64
+	if err != nil && s.opts.Failure(err) {
+		sample = 1
+		n.failures++
+	} else {
+		n.failures = 0
+	}
+
+	w := s.decay(n, now)
+	n.errors = n.errors*w + sample*(1-w)
+	n.requests++
+	n.updated = now
+
+	if sample == 0 {
+		// a successful request after an ejection ends the run of ejections
+		if now.After(n.ejected) {
+			n.ejections = 0
+		}
+		return
+	}
+
+	if now.Before(n.ejected) {
+		return
+	}
+
+	eject := s.opts.EjectFailures > 0 && n.failures >= s.opts.EjectFailures
+	if s.opts.EjectRate > 0 && n.requests >= s.opts.EjectMinRequests && n.errors >= s.opts.EjectRate {
+		eject = true
+	}
+	if !eject {
+		return
+	}
+
+	n.ejections++
+	n.ejected = now.Add(s.opts.EjectTime * time.Duration(n.ejections))
+
+	// give the node a fresh start once it's back
+	n.latency = 0
+	n.errors = 0
+	n.requests = 0
+	n.failures = 0
+	n.updated = time.Time{}
+	n.observed = time.Time{}
+}
+
+// Ejected reports whether the node is ejected
+func (s *Stats) Ejected(node *registry.Node) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	n, ok := s.nodes[trackerKey(node)]
+	return ok && time.Now().Before(n.ejected)
+}
+
+// Available returns the nodes which are not ejected. Ejected nodes are kept
+// when more than MaxEjectPercent of the nodes would be left out.
+func (s *Stats) Available(nodes []*registry.Node) []*registry.Node {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	available := make([]*registry.Node, 0, len(nodes))
+
+	for _, node := range nodes {
+		if n, ok := s.nodes[trackerKey(node)]; ok && now.Before(n.ejected) {
+			continue
+		}
+		available = append(available, node)
+	}
+
+	if ejected := len(nodes) - len(available); ejected*100 > len(nodes)*s.opts.MaxEjectPercent {
+		return nodes
+	}
+
+	return available
+}
+
+// Cost of sending a request to the node. The decayed latency is scaled by the
+// requests in flight and the error rate so slow and failing nodes cost more.
+func (s *Stats) Cost(node *registry.Node) float64 {
+	s.Lock()
+	defer s.Unlock()
+
+	n, ok := s.nodes[trackerKey(node)]
+	if !ok {
+		return 0
+	}
+
+	if n.latency == 0 {
+		if n.pending == 0 {
+			return 0
+		}
+		return float64(s.opts.Penalty) + float64(n.pending)
+	}
+
+	now := time.Now()
+	w := s.decay(n, now)
+	errs := n.errors * w
+
+	return s.latency(n, now) * float64(n.pending+1) / math.Max(1-errs, 0.01)
+}
+
+// Node returns a snapshot of the stats of the node
+func (s *Stats) Node(node *registry.Node) NodeStats {
+	s.Lock()
+	defer s.Unlock()
+
+	n, ok := s.nodes[trackerKey(node)]
+	if !ok {
+		return NodeStats{}
+	}
+
+	return NodeStats{
+		Latency:   time.Duration(s.latency(n, time.Now())),
+		ErrorRate: n.errors,
+		Pending:   n.pending,
+		Ejected:   time.Now().Before(n.ejected),
+	}
+}
+
+// NewStats returns new stats. By default a node is ejected
+// after DefaultEjectFailures consecutive failures.
+func NewStats(opts ...StatsOption) *Stats {
+	options := StatsOptions{
+		Decay:           DefaultDecay,
+		Penalty:         DefaultPenalty,
+		EjectFailures:   DefaultEjectFailures,
+		EjectTime:       DefaultEjectTime,
+		MaxEjectPercent: DefaultMaxEjectPercent,
+		Failure:         errors.IsFailure,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &Stats{
+		opts:  options,
+		nodes: make(map[string]*nodeStats),
+	}
+}
@@ -1,13 +1,20 @@
 package selector
 
 import (
+	"hash/fnv"
 	"math/rand"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/stack-labs/stack/registry"
 )
 
+var (
+	// WeightKey is the node metadata key of the weight used by the weighted strategies
+	WeightKey = "weight"
+)
+
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
@@ -56,3 +63,194 @@ func RoundRobin() Strategy {
 		return nodes[i%len(nodes)], nil
 	}
 }
+
+// nodes flattens the nodes of the services
+func nodes(services []*registry.Service) []*registry.Node {
+	nodes := make([]*registry.Node, 0, len(services))
+
+	for _, service := range services {
+		nodes = append(nodes, service.Nodes...)
+	}
+
+	return nodes
+}
+
+// weight of the node set in its metadata, defaults to 1
+func weight(node *registry.Node) int {
+	w, err := strconv.Atoi(node.Metadata[WeightKey])
+	if err != nil || w < 1 {
+		return 1
+	}
+	return w
+}
+
+// WeightedRandom is a random strategy which picks nodes in proportion
+// to the weight set in their metadata
+func WeightedRandom() Strategy {
+	return func(services []*registry.Service) (*registry.Node, error) {
+		nodes := nodes(services)
+
+		if len(nodes) == 0 {
+			return nil, ErrNoneAvailable
+		}
+
+		var total int
+		for _, node := range nodes {
+			total += weight(node)
+		}
+
+		i := rand.Intn(total)
+		for _, node := range nodes {
+			if i -= weight(node); i < 0 {
+				return node, nil
+			}
+		}
+
+		return nodes[len(nodes)-1], nil
+	}
+}
+
+// WeightedRoundRobin is a smooth weighted round robin strategy which spreads
+// the picks of a node evenly while keeping to the weight set in its metadata
+func WeightedRoundRobin() Strategy {
+	var mtx sync.Mutex
+	current := make(map[string]int)
+
+	return func(services []*registry.Service) (*registry.Node, error) {
+		nodes := nodes(services)
+
+		if len(nodes) == 0 {
+			return nil, ErrNoneAvailable
+		}
+
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		var total int
+		var best *registry.Node
+
+		for _, node := range nodes {
+			w := weight(node)
+			total += w
+			current[node.Id] += w
+
+			if best == nil || current[node.Id] > current[best.Id] {
+				best = node
+			}
+		}
+
+		current[best.Id] -= total
+
+		// forget the nodes which have gone away
+		if len(current) > len(nodes) {
+			ids := make(map[string]bool, len(nodes))
+			for _, node := range nodes {
+				ids[node.Id] = true
+			}
+			for id := range current {
+				if !ids[id] {
+					delete(current, id)
+				}
+			}
+		}
+
+		return best, nil
+	}
+}
+
+// LeastConn is a strategy which picks the node with the least outstanding
+// requests in the tracker. Ties are broken at random.
+func LeastConn(t *Tracker) Strategy {
+	return func(services []*registry.Service) (*registry.Node, error) {
+		nodes := nodes(services)
+
+		if len(nodes) == 0 {
+			return nil, ErrNoneAvailable
+		}
+
+		var least []*registry.Node
+		var min int64
+
+		for _, node := range nodes {
+			n := t.Outstanding(node)
+			switch {
+			case len(least) == 0 || n < min:
+				least = append(least[:0], node)
+				min = n
+			case n == min:
+				least = append(least, node)
+			}
+		}
+
+		return least[rand.Intn(len(least))], nil
+	}
+}
+
+// ConsistentHash is a strategy which always picks the same node for a key
+// for as long as the node is available. It uses rendezvous hashing so only
+// the keys of a node which goes away are moved to other nodes.
+func ConsistentHash(key string) Strategy {
+	return func(services []*registry.Service) (*registry.Node, error) {
+		nodes := nodes(services)
+
+		if len(nodes) == 0 {
+			return nil, ErrNoneAvailable
+		}
+
+		var best *registry.Node
+		var max uint64
+
+		for _, node := range nodes {
+			h := fnv.New64a()
+			h.Write([]byte(node.Id))
+			h.Write([]byte{0})
+			h.Write([]byte(key))
+
+			if score := mix(h.Sum64()); best == nil || score > max {
+				best = node
+				max = score
+			}
+		}
+
+		return best, nil
+	}
+}
+
+// mix is the splitmix64 finalizer spreading the bits of fnv
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// P2C is a power of two choices strategy. It picks two nodes at random
+// and takes the one with the lower cost in the stats, which are a moving
+// average of the latency and error rate of the nodes. Ejected nodes are
+// left out.
+func P2C(s *Stats) Strategy {
+	return func(services []*registry.Service) (*registry.Node, error) {
+		nodes := s.Available(nodes(services))
+
+		switch len(nodes) {
+		case 0:
+			return nil, ErrNoneAvailable
+		case 1:
+			return nodes[0], nil
+		}
+
+		i := rand.Intn(len(nodes))
+		j := rand.Intn(len(nodes) - 1)
+		if j >= i {
+			j++
+		}
+
+		a, b := nodes[i], nodes[j]
+		if s.Cost(b) < s.Cost(a) {
+			return b, nil
+		}
+		return a, nil
+	}
+}
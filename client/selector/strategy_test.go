@@ -1,9 +1,13 @@
 package selector
 
 import (
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stack-labs/stack/registry"
+	errs "github.com/stack-labs/stack/util/errors"
 )
 
 func TestStrategies(t *testing.T) {
@@ -53,3 +57,201 @@ func TestStrategies(t *testing.T) {
 		t.Logf("%s: %+v\n", name, counts)
 	}
 }
+
+func TestWeightedStrategies(t *testing.T) {
+	testData := []*registry.Service{
+		{
+			Name: "test1",
+			Nodes: []*registry.Node{
+				{Id: "test1-1", Metadata: map[string]string{"weight": "3"}},
+				{Id: "test1-2", Metadata: map[string]string{"weight": "1"}},
+				{Id: "test1-3"},
+			},
+		},
+	}
+
+	counts := make(map[string]int)
+	strategy := WeightedRoundRobin()
+
+	for i := 0; i < 50; i++ {
+		node, err := strategy(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[node.Id]++
+	}
+
+	if counts["test1-1"] != 30 || counts["test1-2"] != 10 || counts["test1-3"] != 10 {
+		t.Fatalf("Expected picks in proportion to weight got %+v", counts)
+	}
+
+	counts = make(map[string]int)
+	strategy = WeightedRandom()
+
+	for i := 0; i < 1000; i++ {
+		node, err := strategy(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[node.Id]++
+	}
+
+	if counts["test1-1"] <= counts["test1-2"] || counts["test1-1"] <= counts["test1-3"] {
+		t.Fatalf("Expected heavier node to be picked more got %+v", counts)
+	}
+}
+
+func TestLeastConn(t *testing.T) {
+	testData := []*registry.Service{
+		{
+			Name: "test1",
+			Nodes: []*registry.Node{
+				{Id: "test1-1"},
+				{Id: "test1-2"},
+			},
+		},
+	}
+
+	tr := NewTracker()
+	strategy := LeastConn(tr)
+
+	tr.Acquire(testData[0].Nodes[0])
+
+	for i := 0; i < 10; i++ {
+		node, err := strategy(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node.Id != "test1-2" {
+			t.Fatalf("Expected node with least outstanding requests got %s", node.Id)
+		}
+	}
+
+	tr.Release(testData[0].Nodes[0])
+	tr.Release(testData[0].Nodes[0])
+
+	if n := tr.Outstanding(testData[0].Nodes[0]); n != 0 {
+		t.Fatalf("Expected no outstanding requests got %d", n)
+	}
+}
+
+func TestConsistentHash(t *testing.T) {
+	testData := []*registry.Service{
+		{
+			Name: "test1",
+			Nodes: []*registry.Node{
+				{Id: "test1-1"},
+				{Id: "test1-2"},
+				{Id: "test1-3"},
+			},
+		},
+	}
+
+	picks := make(map[string]string)
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		node, err := ConsistentHash(key)(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again, _ := ConsistentHash(key)(testData); again.Id != node.Id {
+			t.Fatalf("Expected %s to be pinned to %s got %s", key, node.Id, again.Id)
+		}
+		picks[key] = node.Id
+	}
+
+	// only the keys of the removed node move
+	testData[0].Nodes = testData[0].Nodes[:2]
+
+	for key, id := range picks {
+		node, _ := ConsistentHash(key)(testData)
+		if id != "test1-3" && node.Id != id {
+			t.Fatalf("Expected %s to stay on %s got %s", key, id, node.Id)
+		}
+	}
+}
+
+func TestP2CEjection(t *testing.T) {
+	testData := []*registry.Service{
+		{
+			Name: "test1",
+			Nodes: []*registry.Node{
+				{Id: "test1-1"},
+				{Id: "test1-2"},
+			},
+		},
+	}
+
+	st := NewStats(EjectFailures(1), EjectTime(time.Hour))
+	strategy := P2C(st)
+
+	st.Mark(testData[0].Nodes[0], errors.New("connection refused"))
+
+	if !st.Ejected(testData[0].Nodes[0]) {
+		t.Fatal("Expected failing node to be ejected")
+	}
+
+	for i := 0; i < 10; i++ {
+		node, err := strategy(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node.Id != "test1-2" {
+			t.Fatalf("Expected ejected node to be skipped got %s", node.Id)
+		}
+	}
+
+	// never eject more than half the nodes
+	st.Mark(testData[0].Nodes[1], errors.New("connection refused"))
+
+	if nodes := st.Available(testData[0].Nodes); len(nodes) != 2 {
+		t.Fatalf("Expected ejected nodes to be kept got %d nodes", len(nodes))
+	}
+}
+
+func TestP2CRecovery(t *testing.T) {
+	testData := []*registry.Service{
+		{
+			Name: "test1",
+			Nodes: []*registry.Node{
+				{Id: "test1-1"},
+				{Id: "test1-2"},
+			},
+		},
+	}
+
+	st := NewStats(Decay(time.Millisecond * 20))
+	strategy := P2C(st)
+	slow, fast := testData[0].Nodes[0], testData[0].Nodes[1]
+
+	st.Observe(slow, time.Second)
+	st.Observe(fast, time.Millisecond)
+
+	node, err := strategy(testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Id != fast.Id {
+		t.Fatalf("Expected the fast node got %s", node.Id)
+	}
+
+	// the peak of the slow node decays while only the fast node is sampled
+	time.Sleep(time.Millisecond * 200)
+	st.Observe(fast, time.Millisecond)
+
+	node, err = strategy(testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Id != slow.Id {
+		t.Fatalf("Expected the slow node to be tried again got %s", node.Id)
+	}
+
+	// timeouts count as failures
+	st = NewStats(EjectFailures(1), EjectTime(time.Hour))
+	st.Mark(slow, errs.Timeout("test", "context deadline exceeded"))
+	if !st.Ejected(slow) {
+		t.Fatal("Expected the timing out node to be ejected")
+	}
+}
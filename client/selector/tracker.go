@@ -0,0 +1,55 @@
+package selector
+
+import (
+	"sync"
+
+	"github.com/stack-labs/stack/registry"
+)
+
+// Tracker counts the outstanding requests of nodes. Selectors supporting
+// it acquire the node returned by Next and release it on Mark.
+type Tracker struct {
+	sync.Mutex
+	counts map[string]int64
+}
+
+func trackerKey(node *registry.Node) string {
+	if len(node.Id) > 0 {
+		return node.Id
+	}
+	return node.Address
+}
+
+// Acquire counts a request made to the node
+func (t *Tracker) Acquire(node *registry.Node) {
+	t.Lock()
+	t.counts[trackerKey(node)]++
+	t.Unlock()
+}
+
+// Release counts a request to the node as done
+func (t *Tracker) Release(node *registry.Node) {
+	t.Lock()
+	defer t.Unlock()
+
+	key := trackerKey(node)
+	if t.counts[key] <= 1 {
+		delete(t.counts, key)
+		return
+	}
+	t.counts[key]--
+}
+
+// Outstanding returns the number of requests made to the node which are not done
+func (t *Tracker) Outstanding(node *registry.Node) int64 {
+	t.Lock()
+	defer t.Unlock()
+	return t.counts[trackerKey(node)]
+}
+
+// NewTracker returns a new tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		counts: make(map[string]int64),
+	}
+}
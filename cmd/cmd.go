@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/pkg/config/secret"
+	"github.com/stack-labs/stack/pkg/config/secret/env"
+	"github.com/stack-labs/stack/pkg/config/secret/file"
 )
 
 type Cmd interface {
@@ -24,6 +27,9 @@ type stackCmd struct {
 	opts Options
 	app  *cli.App
 	conf string
+
+	// the built-in secret providers enabled by the flags
+	secrets []secret.Provider
 }
 
 var (
@@ -119,7 +125,7 @@ var (
 		cli.StringFlag{
 			Name:   "broker",
 			EnvVar: "STACK_BROKER",
-			Usage:  "Broker for pub/sub. http, nats, rabbitmq",
+			Usage:  "Broker for pub/sub. http, file, nats, rabbitmq",
 			Alias:  "stack_broker_name",
 		},
 		cli.StringFlag{
@@ -152,6 +158,12 @@ var (
 			Usage:  "Selector used to pick nodes for querying",
 			Alias:  "stack_selector_name",
 		},
+		cli.StringFlag{
+			Name:   "selector_strategy",
+			EnvVar: "STACK_SELECTOR_STRATEGY",
+			Usage:  "Strategy used by the selector to pick nodes; random, roundrobin, weighted, weighted_roundrobin, leastconn, p2c",
+			Alias:  "stack_selector_strategy",
+		},
 		cli.StringFlag{
 			Name:   "transport",
 			EnvVar: "STACK_TRANSPORT",
@@ -176,7 +188,7 @@ var (
 			Usage:  "Auth for role based access control, e.g. service",
 			Alias:  "stack_auth_name",
 		},
-		&cli.StringFlag{
+		&cli.BoolFlag{
 			Name:   "auth_enable",
 			EnvVar: "STACK_AUTH_ENABLE",
 			Usage:  "enable auth for role based access control, false",
@@ -219,6 +231,24 @@ var (
 			Usage:  "config file",
 			Alias:  "stack_config",
 		},
+		cli.BoolFlag{
+			Name:   "secret_env",
+			EnvVar: "STACK_SECRET_ENV",
+			Usage:  "Resolve the ${secret:name} config values from the STACK_SECRET_ environment variables",
+			Alias:  "stack_secret_env",
+		},
+		cli.StringFlag{
+			Name:   "secret_file",
+			EnvVar: "STACK_SECRET_FILE",
+			Usage:  "Secrets file for the ${secret:name} and ENC(...) config values",
+			Alias:  "stack_secret_file",
+		},
+		cli.StringFlag{
+			Name:   "secret_key_file",
+			EnvVar: "STACK_SECRET_KEY_FILE",
+			Usage:  "File of the base64 key of the secrets file and ENC(...) config values. Default: the STACK_SECRET_KEY env",
+			Alias:  "stack_secret_keyFile",
+		},
 	}
 )
 
@@ -268,9 +298,38 @@ func (c *stackCmd) before(ctx *cli.Context) (err error) {
 		c.conf = filePath
 	}
 
+	c.registerSecrets(ctx)
+
 	return nil
 }
 
+// registerSecrets registers the built-in secret providers enabled by the
+// flags, before the config is loaded
+func (c *stackCmd) registerSecrets(ctx *cli.Context) {
+	for _, p := range c.secrets {
+		secret.Deregister(p)
+	}
+	c.secrets = nil
+
+	if ctx.Bool("secret_env") {
+		c.secrets = append(c.secrets, env.NewProvider())
+	}
+
+	// the key alone decrypts the ENC(...) values
+	path, keyFile := ctx.String("secret_file"), ctx.String("secret_key_file")
+	if len(path) > 0 || len(keyFile) > 0 {
+		opts := []file.Option{file.Path(path)}
+		if len(keyFile) > 0 {
+			opts = append(opts, file.KeyFile(keyFile))
+		}
+		c.secrets = append(c.secrets, file.NewProvider(opts...))
+	}
+
+	for _, p := range c.secrets {
+		secret.Register(p)
+	}
+}
+
 func (c *stackCmd) App() *cli.App {
 	return c.app
 }
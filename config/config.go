@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/stack-labs/stack/pkg/config"
 	"github.com/stack-labs/stack/pkg/config/reader"
@@ -20,18 +21,25 @@ var (
 	DefaultHierarchySeparator = "."
 
 	// holds all the Options
-	optionsPool = make(map[string]reflect.Value)
+	optionsPool = make(map[string]*autowired)
+	optionsLock sync.RWMutex
 )
 
 type Config interface {
 	reader.Values
 	Init(opts ...Option) error
+	// Watch a value for changes
+	Watch(path ...string) (config.Watcher, error)
 	Close() error
 }
 
 type stackConfig struct {
 	config config.Config
 	opts   Options
+
+	// stop the autowired refresh
+	exit chan bool
+	done chan bool
 }
 
 func (c *stackConfig) Init(opts ...Option) (err error) {
@@ -49,6 +57,9 @@ func (c *stackConfig) Init(opts ...Option) (err error) {
 		}
 	}()
 
+	// the options follow the new config only
+	c.stopAutowired()
+
 	cfg, err := config.NewConfig(
 		config.Storage(c.opts.Storage),
 		config.Watch(c.opts.Watch),
@@ -67,8 +78,10 @@ func (c *stackConfig) Init(opts ...Option) (err error) {
 
 	// cache c as sugar
 	_sugar = c
-	// set the autowired values
-	injectAutowired(c.opts.Context)
+	// set the autowired values, the options must be valid to start
+	if err = c.injectAutowired(c.opts.Context); err != nil {
+		return
+	}
 
 	return nil
 }
@@ -84,6 +97,17 @@ func (c *stackConfig) Get(path ...string) reader.Value {
 	return c.config.Get(tempPath...)
 }
 
+func (c *stackConfig) Watch(path ...string) (config.Watcher, error) {
+	tempPath := path
+	if len(path) == 1 {
+		if strings.Contains(path[0], DefaultHierarchySeparator) {
+			tempPath = strings.Split(path[0], DefaultHierarchySeparator)
+		}
+	}
+
+	return c.config.Watch(tempPath...)
+}
+
 func (c *stackConfig) Bytes() []byte {
 	return c.config.Bytes()
 }
@@ -97,6 +121,7 @@ func (c *stackConfig) Scan(v interface{}) error {
 }
 
 func (c *stackConfig) Close() error {
+	c.stopAutowired()
 	return c.config.Close()
 }
 
@@ -114,7 +139,7 @@ func NewConfig(opts ...Option) Config {
 }
 
 func RegisterOptions(options ...interface{}) {
-	for _, option := range options {
+	for i, option := range options {
 		val := reflect.ValueOf(option)
 		if val.Kind() != reflect.Ptr {
 			log.Error("options must be a pointer")
@@ -124,7 +149,49 @@ func RegisterOptions(options ...interface{}) {
 		_, file, line, _ := runtime.Caller(1)
 
 		key := fmt.Sprintf("%s#L%d", file, line)
+		if len(options) > 1 {
+			key = fmt.Sprintf("%s-%d", key, i)
+		}
 
-		optionsPool[key] = val
+		a := newAutowired(val)
+
+		optionsLock.Lock()
+		optionsPool[key] = a
+		optionsLock.Unlock()
+
+		// registered after the config is loaded
+		if _sugar != nil {
+			if err := a.bind(_sugar); err != nil {
+				log.Errorf("config autowired values of %s rejected, %s", key, err)
+			}
+		}
+	}
+}
+
+// OnChange registers a func called after the registered options changed
+func OnChange(option interface{}, fn func()) {
+	a := lookup(option)
+	if a == nil {
+		log.Error("options must be registered")
+		return
 	}
+
+	a.Lock()
+	a.onChange = append(a.onChange, fn)
+	a.Unlock()
+}
+
+// Read calls fn with the registered options locked for reading, so all of
+// the values read in fn are of the same version of the config. Options are
+// rebound as the config changes, readers outside of Read may see a torn value
+func Read(option interface{}, fn func()) {
+	a := lookup(option)
+	if a == nil {
+		fn()
+		return
+	}
+
+	a.RLock()
+	defer a.RUnlock()
+	fn()
 }
@@ -2,88 +2,433 @@ package config
 
 import (
 	"context"
+	stdjson "encoding/json"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/stack-labs/stack/pkg/config/reader"
+	"github.com/stack-labs/stack/pkg/config/reader/json"
 	"github.com/stack-labs/stack/util/log"
 )
 
-func injectAutowired(ctx context.Context) {
-	refresh := func() {
-		var wg sync.WaitGroup
-		for s, value := range optionsPool {
-			wg.Add(1)
+// autowired is a registered options struct
+type autowired struct {
+	sync.RWMutex
+	value reflect.Value
+	// the config paths of the tagged fields
+	paths [][]string
+	// called after the value changed
+	onChange []func()
+}
+
+func newAutowired(val reflect.Value) *autowired {
+	return &autowired{value: val, paths: fieldPaths(reflect.Indirect(val).Type())}
+}
+
+// affected reports whether any of the changed paths is within or above a
+// path of the options
+func (a *autowired) affected(changed [][]string) bool {
+	for _, c := range changed {
+		for _, p := range a.paths {
+			if hasPrefix(c, p) || hasPrefix(p, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-			go func(key string, val reflect.Value) {
-				defer wg.Done()
-				log.Debugf("setting values for %s", key)
+// bind swaps in the value bound from the config, readers holding the
+// read lock see either the previous or the new value
+func (a *autowired) bind(values reader.Values) error {
+	a.Lock()
 
-				bindAutowiredValue(val)
-			}(s, value)
+	v := reflect.Indirect(a.value)
+	old := reflect.New(v.Type()).Elem()
+	old.Set(v)
+
+	err := bindAutowiredValue(values, a.value)
+	changed := err == nil && !reflect.DeepEqual(old.Interface(), v.Interface())
+	onChange := a.onChange
+
+	a.Unlock()
+
+	if changed {
+		for _, fn := range onChange {
+			fn()
 		}
-		wg.Wait()
 	}
 
+	return err
+}
+
+// lookup the registered options
+func lookup(option interface{}) *autowired {
+	optionsLock.RLock()
+	defer optionsLock.RUnlock()
+
+	for _, a := range optionsPool {
+		if a.value.Interface() == option {
+			return a
+		}
+	}
+	return nil
+}
+
+// refreshAutowired binds the options affected by the changed paths, or all
+// of them when changed is nil. The unchanged ones are left as they are.
+// Invalid options keep their previous values
+func refreshAutowired(values reader.Values, changed [][]string) error {
+	optionsLock.RLock()
+	pool := make([]*autowired, 0, len(optionsPool))
+	for _, a := range optionsPool {
+		if changed == nil || a.affected(changed) {
+			pool = append(pool, a)
+		}
+	}
+	optionsLock.RUnlock()
+
+	errs := validationErrors{}
+	for _, a := range pool {
+		if err := a.bind(values); err != nil {
+			for k, e := range err.(validationErrors) {
+				errs[k] = e
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// injectAutowired sets the values of the options and rebinds them as the config changes
+func (c *stackConfig) injectAutowired(ctx context.Context) error {
 	// refresh for the first time
-	refresh()
+	if err := refreshAutowired(c.config, nil); err != nil {
+		return err
+	}
+	last := c.config.Map()
+
+	w, err := c.config.Events()
+	if err != nil {
+		return err
+	}
+
+	exit := make(chan bool)
+	done := make(chan bool)
+	c.exit, c.done = exit, done
+
+	go func() {
+		select {
+		case <-exit:
+		case <-ctx.Done():
+			log.Infof("config autowired action stopped because of %v", ctx.Err())
+		}
+		_ = w.Stop()
+	}()
 
 	go func() {
+		defer close(done)
+
 		for {
-			select {
-			// todo configurable, maybe
-			case <-time.After(3 * time.Second):
-				refresh()
-			case data := <-ctx.Done():
-				log.Infof("config autowired action stopped because of %v", data)
+			rec, err := w.Next()
+			if err != nil {
+				return
+			}
+
+			// bind the values of the event, the config may not have them yet.
+			// Records skipped by the watcher are covered by diffing against
+			// the values of the last event rather than the previous record
+			values, err := json.NewReader().Values(rec.Snapshot.ChangeSet)
+			if err != nil {
+				log.Errorf("config autowired values of version %s error: %s", rec.Snapshot.Version, err)
+				continue
+			}
+
+			next := values.Map()
+			changed := changedPaths(last, next)
+			last = next
+			if len(changed) == 0 {
+				continue
+			}
+
+			if err := refreshAutowired(values, changed); err != nil {
+				log.Errorf("config autowired values of version %s rejected, %s", rec.Snapshot.Version, err)
 			}
 		}
 	}()
+
+	return nil
 }
 
-func bindAutowiredValue(obj reflect.Value, path ...string) {
-	value := _sugar.Get(path...)
+// stopAutowired stops rebinding the options and waits for the running refresh
+func (c *stackConfig) stopAutowired() {
+	if c.exit == nil {
+		return
+	}
+
+	close(c.exit)
+	<-c.done
+	c.exit, c.done = nil, nil
+}
+
+// fieldPaths returns the config paths of the tagged fields of t
+func fieldPaths(t reflect.Type, path ...string) [][]string {
+	if t.Kind() != reflect.Struct {
+		return [][]string{path}
+	}
+
+	var paths [][]string
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get(DefaultOptionsTagName)
+		if name == "" || name == "-" {
+			continue
+		}
+		paths = append(paths, fieldPaths(t.Field(i).Type, append(path[:len(path):len(path)], name)...)...)
+	}
+	return paths
+}
+
+// changedPaths returns the paths of the values which differ between the
+// old and the new config, nested maps are compared key by key
+func changedPaths(old, new interface{}, path ...string) [][]string {
+	om, ok := old.(map[string]interface{})
+	nm, nok := new.(map[string]interface{})
+	if !ok || !nok {
+		if reflect.DeepEqual(old, new) {
+			return nil
+		}
+		return [][]string{path}
+	}
+
+	var paths [][]string
+	for k, v := range om {
+		paths = append(paths, changedPaths(v, nm[k], append(path[:len(path):len(path)], k)...)...)
+	}
+	for k := range nm {
+		if _, ok := om[k]; !ok {
+			paths = append(paths, append(path[:len(path):len(path)], k))
+		}
+	}
+	return paths
+}
+
+func hasPrefix(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// bindAutowiredValue sets the tagged fields of obj from the values of the
+// path. obj is left unchanged unless all of its fields are valid
+func bindAutowiredValue(values reader.Values, obj reflect.Value, path ...string) error {
 	v := reflect.Indirect(obj)
+
+	nv := reflect.New(v.Type()).Elem()
+	nv.Set(v)
+
+	errs := validationErrors{}
+	bindValue(values, nv, "", errs, path...)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	v.Set(nv)
+
+	return nil
+}
+
+func bindValue(values reader.Values, v reflect.Value, tag reflect.StructTag, errs validationErrors, path ...string) {
+	if v.Kind() == reflect.Struct {
+		// Iterate over the struct fields
+		fields := v.Type()
+		for i := 0; i < fields.NumField(); i++ {
+			name := fields.Field(i).Tag.Get(DefaultOptionsTagName)
+			if name == "" || name == "-" {
+				continue
+			}
+
+			newPath := append(path[:len(path):len(path)], name)
+			bindValue(values, v.Field(i), fields.Field(i).Tag, errs, newPath...)
+		}
+		return
+	}
+
+	r, err := parseRules(tag.Get(DefaultValidateTagName))
+	if err != nil {
+		errs.add(path, err)
+		return
+	}
+
+	var raw interface{}
+	if err := values.Get(path...).Scan(&raw); err != nil {
+		errs.add(path, err)
+		return
+	}
+
+	// blank values are unset
+	if raw == nil || raw == "" {
+		def, ok := tag.Lookup(DefaultValueTagName)
+		if !ok {
+			if r.required {
+				errs.add(path, fmt.Errorf("is required"))
+			}
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		raw = def
+	}
+
+	if err := setValue(v, raw); err != nil {
+		errs.add(path, err)
+		return
+	}
+
+	if err := r.validate(v); err != nil {
+		errs.add(path, err)
+	}
+}
+
+// setValue converts the raw config value to the kind of v
+func setValue(v reflect.Value, raw interface{}) error {
 	switch v.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		n := int64(value.Int(0))
+		var n int64
+		switch t := raw.(type) {
+		case float64:
+			if t != float64(int64(t)) {
+				return fmt.Errorf("%v is not an integer", t)
+			}
+			n = int64(t)
+		case string:
+			var err error
+			if v.Type() == durationType {
+				var d time.Duration
+				d, err = time.ParseDuration(t)
+				n = int64(d)
+			} else {
+				n, err = strconv.ParseInt(t, 10, 64)
+			}
+			if err != nil {
+				return fmt.Errorf("invalid %s %q", v.Type(), t)
+			}
+		default:
+			return fmt.Errorf("expected %s got %v", v.Type(), raw)
+		}
 		if v.OverflowInt(n) {
-			log.Errorf("bindAutowiredValue can't assign value due to %s-overflow", v.Kind())
+			return fmt.Errorf("%d overflows %s", n, v.Kind())
 		}
 		v.SetInt(n)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		n := uint64(value.Int(0))
-		if v.OverflowInt(int64(n)) {
-			log.Errorf("bindAutowiredValue can't assign value due to %s-overflow", v.Kind())
+		var n uint64
+		switch t := raw.(type) {
+		case float64:
+			if t < 0 || t != float64(uint64(t)) {
+				return fmt.Errorf("%v is not an unsigned integer", t)
+			}
+			n = uint64(t)
+		case string:
+			var err error
+			if n, err = strconv.ParseUint(t, 10, 64); err != nil {
+				return fmt.Errorf("invalid %s %q", v.Kind(), t)
+			}
+		default:
+			return fmt.Errorf("expected %s got %v", v.Kind(), raw)
+		}
+		if v.OverflowUint(n) {
+			return fmt.Errorf("%d overflows %s", n, v.Kind())
 		}
 		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch t := raw.(type) {
+		case float64:
+			f = t
+		case string:
+			var err error
+			if f, err = strconv.ParseFloat(t, 64); err != nil {
+				return fmt.Errorf("invalid %s %q", v.Kind(), t)
+			}
+		default:
+			return fmt.Errorf("expected %s got %v", v.Kind(), raw)
+		}
+		if v.OverflowFloat(f) {
+			return fmt.Errorf("%v overflows %s", f, v.Kind())
+		}
+		v.SetFloat(f)
 	case reflect.String:
-		v.SetString(value.String(""))
+		switch t := raw.(type) {
+		case string:
+			v.SetString(t)
+		case float64:
+			v.SetString(strconv.FormatFloat(t, 'f', -1, 64))
+		case bool:
+			v.SetString(strconv.FormatBool(t))
+		default:
+			return fmt.Errorf("expected string got %v", raw)
+		}
 	case reflect.Bool:
-		v.SetBool(value.Bool(false))
-		// supports string only now
-	case reflect.Slice, reflect.Array:
-		values := value.StringSlice([]string{})
-		v.Set(reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), len(values), len(values)))
-		for idx, val := range values {
-			nvalue := reflect.Indirect(reflect.New(v.Type().Elem()))
-			nvalue.SetString(val)
-			v.Index(idx).Set(nvalue)
+		switch t := raw.(type) {
+		case bool:
+			v.SetBool(t)
+		case string:
+			b, err := strconv.ParseBool(t)
+			if err != nil {
+				return fmt.Errorf("invalid bool %q", t)
+			}
+			v.SetBool(b)
+		default:
+			return fmt.Errorf("expected bool got %v", raw)
 		}
-	case reflect.Struct:
-		// Iterate over the struct fields
-		fields := v.Type()
-		for i := 0; i < fields.NumField(); i++ {
-			tag := fields.Field(i).Tag.Get(DefaultOptionsTagName)
-			if tag == "" || tag == "-" {
-				continue
+	case reflect.Slice:
+		// a string is a comma separated list
+		var values []interface{}
+		switch t := raw.(type) {
+		case []interface{}:
+			values = t
+		case string:
+			for _, s := range strings.Split(t, ",") {
+				if s = strings.TrimSpace(s); len(s) > 0 {
+					values = append(values, s)
+				}
 			}
+		default:
+			values = []interface{}{t}
+		}
 
-			nextValue := v.Field(i)
-			newPath := append(path, tag)
-			bindAutowiredValue(nextValue, newPath...)
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for idx, val := range values {
+			if err := setValue(s.Index(idx), val); err != nil {
+				return fmt.Errorf("index %d: %s", idx, err)
+			}
 		}
+		v.Set(s)
+	case reflect.Map:
+		// the values of a map are decoded as JSON
+		b, err := stdjson.Marshal(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %s", v.Type(), err)
+		}
+		m := reflect.New(v.Type())
+		if err := stdjson.Unmarshal(b, m.Interface()); err != nil {
+			return fmt.Errorf("invalid %s: %s", v.Type(), err)
+		}
+		v.Set(m.Elem())
 	default:
 		log.Warnf("unsupported type: %s of %s", v.Kind().String(), v.String())
 	}
+
+	return nil
 }
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// sampleNode is a key of the sample config
+type sampleNode struct {
+	key      string
+	comments []string
+	value    string
+	children []*sampleNode
+}
+
+func (n *sampleNode) child(key string) *sampleNode {
+	for _, c := range n.children {
+		if c.key == key {
+			return c
+		}
+	}
+	c := &sampleNode{key: key}
+	n.children = append(n.children, c)
+	return c
+}
+
+func (n *sampleNode) write(w *bytes.Buffer, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, c := range n.children {
+		for _, comment := range c.comments {
+			fmt.Fprintf(w, "%s# %s\n", indent, comment)
+		}
+		if len(c.children) > 0 {
+			fmt.Fprintf(w, "%s%s:\n", indent, c.key)
+			c.write(w, depth+1)
+			continue
+		}
+		fmt.Fprintf(w, "%s%s: %s\n", indent, c.key, c.value)
+	}
+}
+
+// sampleValue is the yaml of the default of the field
+func sampleValue(t reflect.Type, def string, ok bool) string {
+	scalar := func(v interface{}) string {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(b))
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		var items []string
+		if ok {
+			for _, s := range strings.Split(def, ",") {
+				if s = strings.TrimSpace(s); len(s) > 0 {
+					items = append(items, scalar(s))
+				}
+			}
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.String:
+		return scalar(def)
+	}
+
+	if ok {
+		return def
+	}
+	if t == durationType {
+		return "0s"
+	}
+	return scalar(reflect.Zero(t).Interface())
+}
+
+func (n *sampleNode) add(t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Tag.Get(DefaultOptionsTagName)
+		if name == "" || name == "-" {
+			continue
+		}
+
+		c := n.child(name)
+
+		if f.Type.Kind() == reflect.Struct {
+			if err := c.add(f.Type); err != nil {
+				return err
+			}
+			if len(c.children) == 0 {
+				c.value = "{}"
+			}
+			continue
+		}
+
+		// the first registered options document the key
+		if len(c.comments) > 0 {
+			continue
+		}
+
+		r, err := parseRules(f.Tag.Get(DefaultValidateTagName))
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+
+		if usage := f.Tag.Get(DefaultUsageTagName); len(usage) > 0 {
+			c.comments = append(c.comments, usage)
+		}
+
+		kind := f.Type.String()
+		if f.Type == durationType || r.duration {
+			kind = "duration"
+		}
+		if rs := r.String(); len(rs) > 0 {
+			kind += ", " + rs
+		}
+		c.comments = append(c.comments, kind)
+
+		def, ok := f.Tag.Lookup(DefaultValueTagName)
+		c.value = sampleValue(f.Type, def, ok)
+	}
+
+	return nil
+}
+
+// Sample writes a sample stack.yml of the registered options, every key
+// is documented with its type, rules and default
+func Sample(w io.Writer) error {
+	optionsLock.RLock()
+	defer optionsLock.RUnlock()
+
+	keys := make([]string, 0, len(optionsPool))
+	for k := range optionsPool {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	root := new(sampleNode)
+	for _, k := range keys {
+		t := reflect.Indirect(optionsPool[k].value).Type()
+		if t.Kind() != reflect.Struct {
+			continue
+		}
+		if err := root.add(t); err != nil {
+			return fmt.Errorf("options of %s: %s", k, err)
+		}
+	}
+
+	var b bytes.Buffer
+	root.write(&b, 0)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -1,10 +1,16 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/stack-labs/stack/pkg/config/source"
 	"github.com/stack-labs/stack/pkg/config/source/memory"
+	"gopkg.in/yaml.v2"
 )
 
 var (
@@ -38,6 +44,7 @@ func TestAutowired(t *testing.T) {
 
 	testValue := testV{}
 	RegisterOptions(&testValue)
+	defer unregister(&testValue)
 
 	c.Init()
 
@@ -53,3 +60,250 @@ func TestAutowired(t *testing.T) {
 		t.Fatalf("registry interval should be 8, but it's %d", testValue.Stack.Registry.Interval)
 	}
 }
+
+type validV struct {
+	Stack struct {
+		Registry struct {
+			Interval int    `sc:"interval" validate:"min=1,max=10"`
+			TTL      string `sc:"ttl" default:"30s" validate:"duration,max=1m"`
+		} `sc:"registry"`
+		Broker struct {
+			Name    string   `sc:"name" validate:"required,enum=http|nats"`
+			Address string   `sc:"address" validate:"regexp=^:[0-9]+$"`
+			Nodes   []string `sc:"nodes" default:"a,b" usage:"Nodes of the broker"`
+		} `sc:"broker"`
+		Server struct {
+			Name string `sc:"name" validate:"required"`
+		} `sc:"server"`
+	} `sc:"stack"`
+}
+
+func unregister(option interface{}) {
+	optionsLock.Lock()
+	defer optionsLock.Unlock()
+
+	for k, a := range optionsPool {
+		if a.value.Interface() == option {
+			delete(optionsPool, k)
+		}
+	}
+}
+
+func TestValidation(t *testing.T) {
+	c := NewConfig(Source(memory.NewSource(memory.WithYAML(ymlFile))))
+
+	value := validV{}
+	RegisterOptions(&value)
+	defer unregister(&value)
+
+	err := c.Init()
+	if err == nil || err.Error() != "invalid config: stack.server.name: is required" {
+		t.Fatalf("expected the server name required got %v", err)
+	}
+
+	value.Stack.Server.Name = "kept"
+	if err := bindAutowiredValue(_sugar, reflect.ValueOf(&value)); err == nil {
+		t.Fatal("expected the invalid value rejected")
+	}
+	if value.Stack.Server.Name != "kept" || value.Stack.Broker.Name != "" {
+		t.Fatalf("expected the previous value kept got %+v", value)
+	}
+
+	c = NewConfig(Source(memory.NewSource(memory.WithYAML([]byte(`
+stack:
+  registry:
+    interval: 8
+  broker:
+    name: http
+    address: :8081
+  server:
+    name: demo
+`)))))
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if b := value.Stack.Broker; b.Name != "http" || len(b.Nodes) != 2 || b.Nodes[1] != "b" || value.Stack.Registry.TTL != "30s" {
+		t.Fatalf("unexpected value %+v", value)
+	}
+
+	c = NewConfig(Source(memory.NewSource(memory.WithYAML([]byte(`
+stack:
+  registry:
+    interval: 11
+    ttl: 2m
+  broker:
+    name: kafka
+    address: localhost
+    nodes: [a, b]
+  server:
+    name: demo
+`)))))
+	err = c.Init()
+	expected := []string{
+		"stack.broker.address: \"localhost\" doesn't match ^:[0-9]+$",
+		"stack.broker.name: \"kafka\" is not one of http|nats",
+		"stack.registry.interval: value 11 is greater than 10",
+		"stack.registry.ttl: duration 2m0s is greater than 1m",
+	}
+	if err == nil || err.Error() != "invalid config: "+strings.Join(expected, "; ") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestSample(t *testing.T) {
+	value := validV{}
+	RegisterOptions(&value)
+	defer unregister(&value)
+
+	var b bytes.Buffer
+	if err := Sample(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"stack:\n  registry:\n",
+		"    # int, min 1, max 10\n    interval: 0\n",
+		"    # duration, max 1m\n    ttl: 30s\n",
+		"    # Nodes of the broker\n    # []string\n    nodes: [a, b]\n",
+		"    # string, required, one of http|nats\n    name: \"\"\n",
+	} {
+		if !strings.Contains(b.String(), s) {
+			t.Fatalf("expected %q in the sample\n%s", s, b.String())
+		}
+	}
+
+	var v map[string]interface{}
+	if err := yaml.Unmarshal(b.Bytes(), &v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type refreshV struct {
+	Broker struct {
+		Name string `sc:"name" validate:"enum=http|nats"`
+	} `sc:"broker"`
+}
+
+type otherV struct {
+	Registry struct {
+		Interval int `sc:"interval"`
+	} `sc:"registry"`
+}
+
+func TestAutowiredRefresh(t *testing.T) {
+	src := memory.NewSource(memory.WithYAML([]byte(`
+broker:
+  name: http
+registry:
+  interval: 1
+`)))
+	// update until ch is signalled, the source may not be watched yet
+	update := func(data string, ch chan bool) bool {
+		for i := 0; i < 20; i++ {
+			src.(interface{ Update(*source.ChangeSet) }).Update(&source.ChangeSet{Data: []byte(data), Format: "yaml"})
+			select {
+			case <-ch:
+				return true
+			case <-time.After(time.Millisecond * 50):
+			}
+		}
+		return false
+	}
+
+	value, other := refreshV{}, otherV{}
+	RegisterOptions(&value, &other)
+	defer unregister(&value)
+	defer unregister(&other)
+
+	changed, otherChanged := make(chan bool, 10), make(chan bool, 10)
+	OnChange(&value, func() { changed <- true })
+	OnChange(&other, func() { otherChanged <- true })
+
+	c := NewConfig(Source(src))
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for len(changed) > 0 || len(otherChanged) > 0 {
+		select {
+		case <-changed:
+		case <-otherChanged:
+		}
+	}
+
+	if !update("broker:\n  name: nats\nregistry:\n  interval: 1\n", changed) {
+		t.Fatal("expected the broker options changed")
+	}
+	Read(&value, func() {
+		if value.Broker.Name != "nats" {
+			t.Fatalf("expected nats got %s", value.Broker.Name)
+		}
+	})
+	if len(otherChanged) != 0 {
+		t.Fatal("expected the registry options unchanged")
+	}
+
+	// invalid values keep the previous ones
+	if !update("broker:\n  name: kafka\nregistry:\n  interval: 2\n", otherChanged) {
+		t.Fatal("expected the registry options changed")
+	}
+	if value.Broker.Name != "nats" || other.Registry.Interval != 2 || len(changed) != 0 {
+		t.Fatalf("unexpected values %+v %+v", value, other)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if sc := c.(*stackConfig); sc.exit != nil {
+		t.Fatal("expected the autowired refresh stopped")
+	}
+}
+
+func TestChangedPaths(t *testing.T) {
+	old := map[string]interface{}{
+		"broker":   map[string]interface{}{"name": "http", "address": ":8081"},
+		"registry": map[string]interface{}{"interval": 1.0},
+	}
+	next := map[string]interface{}{
+		"broker":   map[string]interface{}{"name": "nats", "address": ":8081"},
+		"registry": map[string]interface{}{"interval": 1.0},
+		"store":    map[string]interface{}{"name": "file"},
+	}
+
+	changed := changedPaths(old, next)
+	if len(changed) != 2 {
+		t.Fatalf("expected 2 changed paths got %v", changed)
+	}
+
+	value, other := newAutowired(reflect.ValueOf(&refreshV{})), newAutowired(reflect.ValueOf(&otherV{}))
+	if !value.affected(changed) {
+		t.Fatal("expected the broker options affected")
+	}
+	if other.affected(changed) {
+		t.Fatal("expected the registry options unaffected")
+	}
+	// removing a whole subtree affects the options under it
+	if !other.affected(changedPaths(old, map[string]interface{}{})) {
+		t.Fatal("expected the registry options affected")
+	}
+}
+
+func TestAutowiredMap(t *testing.T) {
+	var value struct {
+		Limits map[string]struct {
+			Rate float64 `json:"rate"`
+		} `sc:"limits"`
+	}
+
+	c := NewConfig(Source(memory.NewSource(memory.WithYAML([]byte("limits:\n  greeter:\n    rate: 10\n")))))
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	RegisterOptions(&value)
+	defer unregister(&value)
+
+	if l, ok := value.Limits["greeter"]; !ok || l.Rate != 10 {
+		t.Fatalf("unexpected limits %+v", value.Limits)
+	}
+}
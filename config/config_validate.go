@@ -0,0 +1,230 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// DefaultValueTagName is the tag of the value used when the config has none
+	DefaultValueTagName = "default"
+	// DefaultValidateTagName is the tag of the rules the value must follow,
+	// eg. `validate:"required,min=1,max=10"`. The regexp rule must be the
+	// last as it may contain commas
+	DefaultValidateTagName = "validate"
+	// DefaultUsageTagName is the tag describing the value in the sample config
+	DefaultUsageTagName = "usage"
+
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// rules of a value parsed from the validate tag
+type rules struct {
+	required bool
+	duration bool
+	min      string
+	max      string
+	enum     []string
+	regexp   *regexp.Regexp
+}
+
+func parseRules(tag string) (*rules, error) {
+	r := new(rules)
+	if len(tag) == 0 {
+		return r, nil
+	}
+
+	parts := strings.Split(tag, ",")
+	for i, part := range parts {
+		kv := strings.SplitN(part, "=", 2)
+		name := strings.TrimSpace(kv[0])
+		arg := ""
+		if len(kv) == 2 {
+			arg = kv[1]
+		}
+
+		switch name {
+		case "required":
+			r.required = true
+		case "duration":
+			r.duration = true
+		case "min":
+			r.min = arg
+		case "max":
+			r.max = arg
+		case "enum":
+			r.enum = strings.Split(arg, "|")
+		case "regexp":
+			re, err := regexp.Compile(strings.Join(append([]string{arg}, parts[i+1:]...), ","))
+			if err != nil {
+				return nil, err
+			}
+			r.regexp = re
+			return r, nil
+		default:
+			return nil, fmt.Errorf("unknown validate rule %s", name)
+		}
+	}
+
+	return r, nil
+}
+
+// String describes the rules in the sample config
+func (r *rules) String() string {
+	var s []string
+	if r.required {
+		s = append(s, "required")
+	}
+	if len(r.min) > 0 {
+		s = append(s, "min "+r.min)
+	}
+	if len(r.max) > 0 {
+		s = append(s, "max "+r.max)
+	}
+	if len(r.enum) > 0 {
+		s = append(s, "one of "+strings.Join(r.enum, "|"))
+	}
+	if r.regexp != nil {
+		s = append(s, "matches "+r.regexp.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// validate the value bound from the config
+func (r *rules) validate(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if err := r.bounds(float64(v.Len()), "length"); err != nil {
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := r.match(fmt.Sprint(v.Index(i).Interface())); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.String:
+		s := v.String()
+		if r.duration {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("invalid duration %q", s)
+			}
+			if err := r.durationBounds(d); err != nil {
+				return err
+			}
+		} else if err := r.bounds(float64(len(s)), "length"); err != nil {
+			return err
+		}
+		return r.match(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			if err := r.durationBounds(time.Duration(v.Int())); err != nil {
+				return err
+			}
+		} else if err := r.bounds(float64(v.Int()), "value"); err != nil {
+			return err
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if err := r.bounds(float64(v.Uint()), "value"); err != nil {
+			return err
+		}
+	case reflect.Float32, reflect.Float64:
+		if err := r.bounds(v.Float(), "value"); err != nil {
+			return err
+		}
+	}
+
+	return r.match(fmt.Sprint(v.Interface()))
+}
+
+// match the value against the enum and regexp rules
+func (r *rules) match(s string) error {
+	if len(r.enum) > 0 {
+		found := false
+		for _, e := range r.enum {
+			if e == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%q is not one of %s", s, strings.Join(r.enum, "|"))
+		}
+	}
+	if r.regexp != nil && !r.regexp.MatchString(s) {
+		return fmt.Errorf("%q doesn't match %s", s, r.regexp)
+	}
+	return nil
+}
+
+func (r *rules) bounds(n float64, what string) error {
+	if len(r.min) > 0 {
+		min, err := strconv.ParseFloat(r.min, 64)
+		if err != nil {
+			return fmt.Errorf("invalid min rule %s", r.min)
+		}
+		if n < min {
+			return fmt.Errorf("%s %v is less than %s", what, n, r.min)
+		}
+	}
+	if len(r.max) > 0 {
+		max, err := strconv.ParseFloat(r.max, 64)
+		if err != nil {
+			return fmt.Errorf("invalid max rule %s", r.max)
+		}
+		if n > max {
+			return fmt.Errorf("%s %v is greater than %s", what, n, r.max)
+		}
+	}
+	return nil
+}
+
+func (r *rules) durationBounds(d time.Duration) error {
+	if len(r.min) > 0 {
+		min, err := time.ParseDuration(r.min)
+		if err != nil {
+			return fmt.Errorf("invalid min rule %s", r.min)
+		}
+		if d < min {
+			return fmt.Errorf("duration %s is less than %s", d, r.min)
+		}
+	}
+	if len(r.max) > 0 {
+		max, err := time.ParseDuration(r.max)
+		if err != nil {
+			return fmt.Errorf("invalid max rule %s", r.max)
+		}
+		if d > max {
+			return fmt.Errorf("duration %s is greater than %s", d, r.max)
+		}
+	}
+	return nil
+}
+
+// validationErrors of the fields of the options, by key
+type validationErrors map[string]string
+
+func (v validationErrors) add(path []string, err error) {
+	v[strings.Join(path, DefaultHierarchySeparator)] = err.Error()
+}
+
+func (v validationErrors) Error() string {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	errs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		errs = append(errs, k+": "+v[k])
+	}
+
+	return "invalid config: " + strings.Join(errs, "; ")
+}
@@ -6,9 +6,11 @@ import (
 	"time"
 
 	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/breaker"
+	"github.com/stack-labs/stack/debug/health"
 	"github.com/stack-labs/stack/debug/log"
+	"github.com/stack-labs/stack/debug/metrics"
 	proto "github.com/stack-labs/stack/debug/proto"
-	"github.com/stack-labs/stack/debug/stats"
 	"github.com/stack-labs/stack/debug/trace"
 	"github.com/stack-labs/stack/server"
 )
@@ -16,9 +18,11 @@ import (
 // NewHandler returns an instance of the Debug Handler
 func NewHandler(c client.Client) *Debug {
 	return &Debug{
-		log:   log.DefaultLog,
-		stats: stats.NewStats(),
-		trace: trace.DefaultTracer,
+		log:      log.DefaultLog,
+		health:   health.DefaultHealth,
+		metrics:  metrics.DefaultRegistry,
+		trace:    trace.DefaultTracer,
+		breakers: breaker.Breakers,
 	}
 }
 
@@ -27,37 +31,62 @@ type Debug struct {
 	proto.DebugHandler
 	// the logger for retrieving logs
 	log log.Log
-	// the stats collector
-	stats stats.Stats
+	// the health checks of the service
+	health *health.Health
+	// the metrics registry the stats are read from
+	metrics *metrics.Registry
 	// the tracer
 	trace trace.Tracer
+	// the client circuit breakers
+	breakers func() []*breaker.Breaker
 }
 
 func (d *Debug) Health(ctx context.Context, req *proto.HealthRequest, rsp *proto.HealthResponse) error {
-	rsp.Status = "ok"
+	health.Encode(d.health.Ready(ctx), rsp)
 	return nil
 }
 
 func (d *Debug) Stats(ctx context.Context, req *proto.StatsRequest, rsp *proto.StatsResponse) error {
-	stats, err := d.stats.Read()
-	if err != nil {
-		return err
+	rsp.Timestamp = uint64(time.Now().Unix())
+
+	for _, f := range d.metrics.Gather() {
+		for _, s := range f.Samples {
+			switch s.Name {
+			case "process_start_time_seconds":
+				rsp.Started = uint64(s.Value)
+			case "process_uptime_seconds":
+				rsp.Uptime = uint64(s.Value)
+			case "go_memstats_alloc_bytes":
+				rsp.Memory = uint64(s.Value)
+			case "go_goroutines":
+				rsp.Threads = uint64(s.Value)
+			case "go_gc_pause_seconds_total":
+				rsp.Gc = uint64(s.Value * float64(time.Second))
+			case metrics.ServerRequests:
+				rsp.Requests += uint64(s.Value)
+				if s.Label("code") != metrics.CodeOK {
+					rsp.Errors += uint64(s.Value)
+				}
+			}
+		}
 	}
 
-	if len(stats) == 0 {
-		return nil
+	for _, br := range d.breakers() {
+		for _, c := range br.Circuits() {
+			b := &proto.Breaker{
+				Name:     c.Name,
+				State:    c.State.String(),
+				Failures: uint64(c.Failures),
+				Requests: uint64(c.Requests),
+				Errors:   uint64(c.Errors),
+			}
+			if !c.Opened.IsZero() {
+				b.Opened = uint64(c.Opened.Unix())
+			}
+			rsp.Breakers = append(rsp.Breakers, b)
+		}
 	}
 
-	// write the response values
-	rsp.Timestamp = uint64(stats[0].Timestamp)
-	rsp.Started = uint64(stats[0].Started)
-	rsp.Uptime = uint64(stats[0].Uptime)
-	rsp.Memory = stats[0].Memory
-	rsp.Gc = stats[0].GC
-	rsp.Threads = stats[0].Threads
-	rsp.Requests = stats[0].Requests
-	rsp.Errors = stats[0].Errors
-
 	return nil
 }
 
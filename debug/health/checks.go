@@ -0,0 +1,36 @@
+package health
+
+import (
+	"context"
+
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/store"
+)
+
+// Registry checks the registry is reachable
+func Registry(r registry.Registry) Check {
+	return func(ctx context.Context) error {
+		_, err := r.ListServices()
+		return err
+	}
+}
+
+// Broker checks the broker is connected. Connecting a connected
+// broker does nothing, a disconnected one reconnects.
+func Broker(b broker.Broker) Check {
+	return func(ctx context.Context) error {
+		return b.Connect()
+	}
+}
+
+// Store checks the store is readable
+func Store(s store.Store) Check {
+	return func(ctx context.Context) error {
+		_, err := s.Read("health")
+		if err == store.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+}
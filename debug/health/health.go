@@ -0,0 +1,207 @@
+// Package health aggregates the named checks of a service into liveness and readiness
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK      Status = "ok"
+	StatusFailing Status = "failing"
+)
+
+var (
+	// DefaultHealth is the health of the running service
+	DefaultHealth = NewHealth()
+	// DefaultTimeout of a check
+	DefaultTimeout = time.Second * 5
+)
+
+// Status of a check or report
+type Status string
+
+// Check returns an error if the thing checked is unhealthy
+type Check func(context.Context) error
+
+// Result of a check
+type Result struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Liveness check, others only count for readiness
+	Liveness bool          `json:"liveness"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report aggregates the results of the checks
+type Report struct {
+	Status Status    `json:"status"`
+	Checks []*Result `json:"checks"`
+}
+
+// Health holds the checks of a service
+type Health struct {
+	opts Options
+
+	sync.RWMutex
+	checks map[string]*check
+}
+
+type check struct {
+	fn   Check
+	opts CheckOptions
+}
+
+// Ok returns true if every check passed
+func (r *Report) Ok() bool {
+	return r.Status == StatusOK
+}
+
+// Err returns an error listing the failing checks
+func (r *Report) Err() error {
+	if r.Ok() {
+		return nil
+	}
+
+	var failing []string
+	for _, c := range r.Checks {
+		if c.Status != StatusOK {
+			failing = append(failing, c.Name+": "+c.Error)
+		}
+	}
+
+	return errors.New(strings.Join(failing, "; "))
+}
+
+// Register a named check, replacing any check of the same name.
+// Checks count for readiness unless registered with Liveness.
+func (h *Health) Register(name string, fn Check, opts ...CheckOption) {
+	options := CheckOptions{
+		Timeout: h.opts.Timeout,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	h.Lock()
+	h.checks[name] = &check{fn: fn, opts: options}
+	h.Unlock()
+}
+
+// Deregister a named check
+func (h *Health) Deregister(name string) {
+	h.Lock()
+	delete(h.checks, name)
+	h.Unlock()
+}
+
+// run the checks concurrently
+func (h *Health) run(ctx context.Context, liveness bool) *Report {
+	h.RLock()
+	checks := make(map[string]*check, len(h.checks))
+	for name, c := range h.checks {
+		if liveness && !c.opts.Liveness {
+			continue
+		}
+		checks[name] = c
+	}
+	h.RUnlock()
+
+	report := &Report{
+		Status: StatusOK,
+		Checks: make([]*Result, 0, len(checks)),
+	}
+
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, c := range checks {
+		wg.Add(1)
+		go func(name string, c *check) {
+			defer wg.Done()
+
+			r := &Result{
+				Name:     name,
+				Status:   StatusOK,
+				Liveness: c.opts.Liveness,
+			}
+
+			cctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+			defer cancel()
+
+			start := time.Now()
+			errc := make(chan error, 1)
+			go func() {
+				errc <- c.fn(cctx)
+			}()
+
+			var err error
+			select {
+			case err = <-errc:
+			case <-cctx.Done():
+				err = cctx.Err()
+			}
+
+			r.Duration = time.Since(start)
+			if err != nil {
+				r.Status = StatusFailing
+				r.Error = err.Error()
+			}
+
+			mtx.Lock()
+			report.Checks = append(report.Checks, r)
+			if err != nil {
+				report.Status = StatusFailing
+			}
+			mtx.Unlock()
+		}(name, c)
+	}
+
+	wg.Wait()
+
+	sort.Slice(report.Checks, func(i, j int) bool {
+		return report.Checks[i].Name < report.Checks[j].Name
+	})
+
+	return report
+}
+
+// Live runs the liveness checks. A service failing them should be restarted.
+func (h *Health) Live(ctx context.Context) *Report {
+	return h.run(ctx, true)
+}
+
+// Ready runs every check. A service failing them shouldn't be sent requests.
+func (h *Health) Ready(ctx context.Context) *Report {
+	return h.run(ctx, false)
+}
+
+// NewHealth returns a health without checks
+func NewHealth(opts ...Option) *Health {
+	options := Options{
+		Timeout: DefaultTimeout,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &Health{
+		opts:   options,
+		checks: make(map[string]*check),
+	}
+}
+
+// Register a check with the default health
+func Register(name string, fn Check, opts ...CheckOption) {
+	DefaultHealth.Register(name, fn, opts...)
+}
+
+// Deregister a check from the default health
+func Deregister(name string) {
+	DefaultHealth.Deregister(name)
+}
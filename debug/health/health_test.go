@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb "github.com/stack-labs/stack/debug/proto"
+)
+
+func TestHealth(t *testing.T) {
+	h := NewHealth(Timeout(time.Millisecond * 50))
+
+	if r := h.Ready(context.Background()); !r.Ok() || len(r.Checks) != 0 {
+		t.Fatalf("expected ready without checks got %+v", r)
+	}
+
+	var failing error
+	h.Register("process", func(context.Context) error { return nil }, Liveness())
+	h.Register("store", func(context.Context) error { return failing })
+	h.Register("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, CheckTimeout(time.Millisecond*10))
+
+	r := h.Ready(context.Background())
+	if r.Ok() || len(r.Checks) != 3 {
+		t.Fatalf("expected the slow check failing got %+v", r)
+	}
+	if r.Checks[1].Name != "slow" || r.Checks[1].Status != StatusFailing || r.Checks[1].Error != context.DeadlineExceeded.Error() {
+		t.Fatalf("unexpected result %+v", r.Checks[1])
+	}
+	if err := r.Err(); err == nil || err.Error() != "slow: "+context.DeadlineExceeded.Error() {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	h.Deregister("slow")
+	failing = errors.New("unreachable")
+
+	live := h.Live(context.Background())
+	if !live.Ok() || len(live.Checks) != 1 || !live.Checks[0].Liveness {
+		t.Fatalf("expected only the liveness check got %+v", live)
+	}
+
+	r = h.Ready(context.Background())
+	if r.Ok() || r.Checks[1].Error != "unreachable" {
+		t.Fatalf("expected the store check failing got %+v", r)
+	}
+
+	// the report survives the debug endpoint
+	rsp := new(pb.HealthResponse)
+	Encode(r, rsp)
+	if d := Decode(rsp); d.Status != r.Status || len(d.Checks) != 2 || *d.Checks[1] != *r.Checks[1] {
+		t.Fatalf("expected %+v got %+v", r, d)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHealth()
+
+	var failing error
+	h.Register("process", func(context.Context) error { return nil }, Liveness())
+	h.Register("registry", func(context.Context) error { return failing })
+
+	srv := httptest.NewServer(Handler(h))
+	defer srv.Close()
+
+	get := func(path string) (int, *Report) {
+		rsp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rsp.Body.Close()
+
+		r := new(Report)
+		if err := json.NewDecoder(rsp.Body).Decode(r); err != nil {
+			t.Fatal(err)
+		}
+		return rsp.StatusCode, r
+	}
+
+	if code, r := get("/health/ready"); code != 200 || len(r.Checks) != 2 {
+		t.Fatalf("unexpected response %d %+v", code, r)
+	}
+
+	failing = errors.New("unreachable")
+
+	if code, r := get("/health/ready"); code != http.StatusServiceUnavailable || r.Status != StatusFailing {
+		t.Fatalf("unexpected response %d %+v", code, r)
+	}
+	if code, r := get("/health/live"); code != 200 || len(r.Checks) != 1 {
+		t.Fatalf("unexpected response %d %+v", code, r)
+	}
+}
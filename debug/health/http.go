@@ -0,0 +1,41 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+var (
+	// DefaultPath of the liveness and readiness endpoints
+	DefaultPath = "/health"
+)
+
+func serve(w http.ResponseWriter, r *Report) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !r.Ok() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(b)
+}
+
+// Handler serves the liveness report on /health/live and the readiness
+// report on /health/ready, with a 503 status while failing
+func Handler(h *Health) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(DefaultPath+"/live", func(w http.ResponseWriter, r *http.Request) {
+		serve(w, h.Live(r.Context()))
+	})
+
+	mux.HandleFunc(DefaultPath+"/ready", func(w http.ResponseWriter, r *http.Request) {
+		serve(w, h.Ready(r.Context()))
+	})
+
+	return mux
+}
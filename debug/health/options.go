@@ -0,0 +1,46 @@
+package health
+
+import (
+	"time"
+)
+
+// Option used by the health
+type Option func(*Options)
+
+// Options are health options
+type Options struct {
+	// Timeout of the checks not setting their own
+	Timeout time.Duration
+}
+
+// Timeout sets the default timeout of the checks
+func Timeout(t time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = t
+	}
+}
+
+// CheckOption used by a check
+type CheckOption func(*CheckOptions)
+
+// CheckOptions are the options of a check
+type CheckOptions struct {
+	// Liveness checks count for liveness and readiness
+	Liveness bool
+	// Timeout of the check
+	Timeout time.Duration
+}
+
+// Liveness counts the check for liveness as well as readiness
+func Liveness() CheckOption {
+	return func(o *CheckOptions) {
+		o.Liveness = true
+	}
+}
+
+// CheckTimeout sets the timeout of the check
+func CheckTimeout(t time.Duration) CheckOption {
+	return func(o *CheckOptions) {
+		o.Timeout = t
+	}
+}
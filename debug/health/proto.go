@@ -0,0 +1,43 @@
+package health
+
+import (
+	"time"
+
+	pb "github.com/stack-labs/stack/debug/proto"
+)
+
+// Encode the report as the response of the debug health endpoint
+func Encode(r *Report, rsp *pb.HealthResponse) {
+	rsp.Status = string(r.Status)
+	rsp.Checks = make([]*pb.HealthCheck, 0, len(r.Checks))
+
+	for _, c := range r.Checks {
+		rsp.Checks = append(rsp.Checks, &pb.HealthCheck{
+			Name:     c.Name,
+			Status:   string(c.Status),
+			Error:    c.Error,
+			Liveness: c.Liveness,
+			Duration: uint64(c.Duration),
+		})
+	}
+}
+
+// Decode the report from the response of the debug health endpoint
+func Decode(rsp *pb.HealthResponse) *Report {
+	r := &Report{
+		Status: Status(rsp.Status),
+		Checks: make([]*Result, 0, len(rsp.Checks)),
+	}
+
+	for _, c := range rsp.Checks {
+		r.Checks = append(r.Checks, &Result{
+			Name:     c.Name,
+			Status:   Status(c.Status),
+			Error:    c.Error,
+			Liveness: c.Liveness,
+			Duration: time.Duration(c.Duration),
+		})
+	}
+
+	return r
+}
@@ -0,0 +1,365 @@
+// Package metrics provides counters, gauges and histograms
+// exposed in the prometheus text format
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Type of a metric
+type Type int
+
+const (
+	TypeCounter Type = iota
+	TypeGauge
+	TypeHistogram
+)
+
+var (
+	// DefaultBuckets of histograms in seconds
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	// DefaultRegistry holds the metrics of the process
+	DefaultRegistry = NewRegistry()
+)
+
+func init() {
+	RegisterProcess(DefaultRegistry)
+}
+
+func (t Type) String() string {
+	switch t {
+	case TypeCounter:
+		return "counter"
+	case TypeGauge:
+		return "gauge"
+	case TypeHistogram:
+		return "histogram"
+	}
+	return "untyped"
+}
+
+// Label of a sample
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a value of a metric. The samples of a histogram
+// are its buckets, sum and count as in the text format.
+type Sample struct {
+	Name   string
+	Labels []Label
+	Value  float64
+}
+
+// Family is the samples of a metric
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Samples []*Sample
+}
+
+// Label returns the value of the named label
+func (s *Sample) Label(name string) string {
+	for _, l := range s.Labels {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+type series struct {
+	values []string
+	value  float64
+	// histogram buckets, sum and count
+	counts []uint64
+	count  uint64
+}
+
+type metric struct {
+	name    string
+	help    string
+	typ     Type
+	labels  []string
+	buckets []float64
+	fn      func() float64
+
+	sync.Mutex
+	series map[string]*series
+}
+
+// get returns the series of the label values, missing values are left empty
+func (m *metric) get(values []string) *series {
+	if len(values) != len(m.labels) {
+		v := make([]string, len(m.labels))
+		copy(v, values)
+		values = v
+	}
+
+	key := strings.Join(values, "\xff")
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{values: values}
+		if m.typ == TypeHistogram {
+			s.counts = make([]uint64, len(m.buckets))
+		}
+		m.series[key] = s
+	}
+	return s
+}
+
+func (m *metric) labelsOf(s *series, extra ...Label) []Label {
+	labels := make([]Label, 0, len(m.labels)+len(extra))
+	for i, name := range m.labels {
+		labels = append(labels, Label{Name: name, Value: s.values[i]})
+	}
+	return append(labels, extra...)
+}
+
+func (m *metric) gather() *Family {
+	f := &Family{
+		Name: m.name,
+		Help: m.help,
+		Type: m.typ,
+	}
+
+	if m.fn != nil {
+		f.Samples = append(f.Samples, &Sample{Name: m.name, Value: m.fn()})
+		return f
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	keys := make([]string, 0, len(m.series))
+	for k := range m.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := m.series[k]
+
+		if m.typ != TypeHistogram {
+			f.Samples = append(f.Samples, &Sample{Name: m.name, Labels: m.labelsOf(s), Value: s.value})
+			continue
+		}
+
+		var cumulative uint64
+		for i, le := range m.buckets {
+			cumulative += s.counts[i]
+			f.Samples = append(f.Samples, &Sample{
+				Name:   m.name + "_bucket",
+				Labels: m.labelsOf(s, Label{Name: "le", Value: formatFloat(le)}),
+				Value:  float64(cumulative),
+			})
+		}
+		f.Samples = append(f.Samples,
+			&Sample{Name: m.name + "_bucket", Labels: m.labelsOf(s, Label{Name: "le", Value: "+Inf"}), Value: float64(s.count)},
+			&Sample{Name: m.name + "_sum", Labels: m.labelsOf(s), Value: s.value},
+			&Sample{Name: m.name + "_count", Labels: m.labelsOf(s), Value: float64(s.count)},
+		)
+	}
+
+	return f
+}
+
+// Counter only goes up
+type Counter struct {
+	m *metric
+}
+
+// Inc adds one to the counter of the label values
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds v, which must not be negative, to the counter of the label values
+func (c *Counter) Add(v float64, values ...string) {
+	if v < 0 {
+		return
+	}
+	c.m.Lock()
+	c.m.get(values).value += v
+	c.m.Unlock()
+}
+
+// Gauge goes up and down
+type Gauge struct {
+	m *metric
+}
+
+// Set the gauge of the label values
+func (g *Gauge) Set(v float64, values ...string) {
+	g.m.Lock()
+	g.m.get(values).value = v
+	g.m.Unlock()
+}
+
+// Add v to the gauge of the label values
+func (g *Gauge) Add(v float64, values ...string) {
+	g.m.Lock()
+	g.m.get(values).value += v
+	g.m.Unlock()
+}
+
+// Inc adds one to the gauge of the label values
+func (g *Gauge) Inc(values ...string) {
+	g.Add(1, values...)
+}
+
+// Dec takes one from the gauge of the label values
+func (g *Gauge) Dec(values ...string) {
+	g.Add(-1, values...)
+}
+
+// Histogram counts observations in buckets
+type Histogram struct {
+	m *metric
+}
+
+// Observe a value for the label values
+func (h *Histogram) Observe(v float64, values ...string) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	s := h.m.get(values)
+	for i, le := range h.m.buckets {
+		if v <= le {
+			s.counts[i]++
+			break
+		}
+	}
+	s.value += v
+	s.count++
+}
+
+// Registry of metrics
+type Registry struct {
+	sync.RWMutex
+	metrics map[string]*metric
+}
+
+// register returns the metric of the name, creating it if it doesn't exist.
+// Registering a name again with another type, labels or buckets is a
+// programming error.
+func (r *Registry) register(m *metric) *metric {
+	r.Lock()
+	defer r.Unlock()
+
+	if old, ok := r.metrics[m.name]; ok {
+		switch {
+		case old.typ != m.typ:
+			panic(fmt.Sprintf("metrics: %s registered as %s and %s", m.name, old.typ, m.typ))
+		case !equalStrings(old.labels, m.labels):
+			panic(fmt.Sprintf("metrics: %s registered with labels %v and %v", m.name, old.labels, m.labels))
+		case !equalFloats(old.buckets, m.buckets):
+			panic(fmt.Sprintf("metrics: %s registered with buckets %v and %v", m.name, old.buckets, m.buckets))
+		case (old.fn == nil) != (m.fn == nil):
+			panic(fmt.Sprintf("metrics: %s registered with and without a func", m.name))
+		}
+		return old
+	}
+
+	m.series = make(map[string]*series)
+	r.metrics[m.name] = m
+	return m
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Counter returns the counter of the name, created with the help and labels
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r.register(&metric{name: name, help: help, typ: TypeCounter, labels: labels})}
+}
+
+// Gauge returns the gauge of the name, created with the help and labels
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r.register(&metric{name: name, help: help, typ: TypeGauge, labels: labels})}
+}
+
+// Histogram returns the histogram of the name, created with the help,
+// labels and bucket upper bounds. DefaultBuckets are used if none are set.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	b := make([]float64, len(buckets))
+	copy(b, buckets)
+	sort.Float64s(b)
+
+	return &Histogram{r.register(&metric{name: name, help: help, typ: TypeHistogram, labels: labels, buckets: b})}
+}
+
+// CounterFunc registers a counter whose value is read from fn when gathered
+func (r *Registry) CounterFunc(name, help string, fn func() float64) {
+	r.register(&metric{name: name, help: help, typ: TypeCounter, fn: fn})
+}
+
+// GaugeFunc registers a gauge whose value is read from fn when gathered
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	r.register(&metric{name: name, help: help, typ: TypeGauge, fn: fn})
+}
+
+// Unregister removes the metric of the name
+func (r *Registry) Unregister(name string) {
+	r.Lock()
+	delete(r.metrics, name)
+	r.Unlock()
+}
+
+// Gather returns the families of the metrics sorted by name
+func (r *Registry) Gather() []*Family {
+	r.RLock()
+	metrics := make([]*metric, 0, len(r.metrics))
+	for _, m := range r.metrics {
+		metrics = append(metrics, m)
+	}
+	r.RUnlock()
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].name < metrics[j].name
+	})
+
+	families := make([]*Family, 0, len(metrics))
+	for _, m := range metrics {
+		families = append(families, m.gather())
+	}
+
+	return families
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: make(map[string]*metric),
+	}
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stack-labs/stack/codec"
+	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+func TestWrite(t *testing.T) {
+	r := NewRegistry()
+
+	c := r.Counter("test_requests_total", "Total test requests.", "code")
+	c.Inc("200")
+	c.Add(2, "200")
+	c.Inc(`5"0"0`)
+
+	g := r.Gauge("test_inflight", "")
+	g.Inc()
+	g.Inc()
+	g.Dec()
+
+	h := r.Histogram("test_duration_seconds", "Test duration.", []float64{1, 0.1})
+	h.Observe(0.05)
+	h.Observe(0.5)
+	h.Observe(5)
+
+	r.GaugeFunc("test_func", "Test func.", func() float64 { return 42 })
+
+	// the same metric is returned when registered again
+	r.Counter("test_requests_total", "", "code").Inc("200")
+
+	buf := new(bytes.Buffer)
+	if err := r.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# HELP test_duration_seconds Test duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="0.1"} 1
+test_duration_seconds_bucket{le="1"} 2
+test_duration_seconds_bucket{le="+Inf"} 3
+test_duration_seconds_sum 5.55
+test_duration_seconds_count 3
+# HELP test_func Test func.
+# TYPE test_func gauge
+test_func 42
+# TYPE test_inflight gauge
+test_inflight 1
+# HELP test_requests_total Total test requests.
+# TYPE test_requests_total counter
+test_requests_total{code="200"} 4
+test_requests_total{code="5\"0\"0"} 1
+`
+
+	if buf.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected registering a counter as a gauge to panic")
+		}
+	}()
+	r.Gauge("test_requests_total", "")
+}
+
+func TestRegisterMismatch(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("test_requests_total", "", "code")
+	r.Histogram("test_duration_seconds", "", []float64{0.1, 1})
+	r.GaugeFunc("test_func", "", func() float64 { return 42 })
+
+	for name, register := range map[string]func(){
+		"labels":       func() { r.Counter("test_requests_total", "", "status") },
+		"no labels":    func() { r.Counter("test_requests_total", "") },
+		"buckets":      func() { r.Histogram("test_duration_seconds", "", []float64{0.5}) },
+		"default":      func() { r.Histogram("test_duration_seconds", "", nil) },
+		"without func": func() { r.Gauge("test_func", "") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Expected registering with other %s to panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+
+	// the buckets are compared sorted
+	r.Histogram("test_duration_seconds", "", []float64{1, 0.1})
+}
+
+type testRequest struct {
+	service  string
+	endpoint string
+}
+
+func (r *testRequest) Service() string           { return r.service }
+func (r *testRequest) Method() string            { return r.endpoint }
+func (r *testRequest) Endpoint() string          { return r.endpoint }
+func (r *testRequest) ContentType() string       { return "" }
+func (r *testRequest) Header() map[string]string { return nil }
+func (r *testRequest) Body() interface{}         { return nil }
+func (r *testRequest) Read() ([]byte, error)     { return nil, nil }
+func (r *testRequest) Codec() codec.Reader       { return nil }
+func (r *testRequest) Stream() bool              { return false }
+
+func TestHandlerWrapper(t *testing.T) {
+	r := NewRegistry()
+	w := NewHandlerWrapper(WithRegistry(r))
+
+	ok := w(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})
+	fail := w(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return errors.NotFound("foo", "not found")
+	})
+
+	ctx := metadata.NewContext(context.Background(), metadata.Metadata{"Remote": "10.0.0.1:51234"})
+	req := &testRequest{service: "foo", endpoint: "Foo.Bar"}
+
+	ok(ctx, req, nil)
+	ok(ctx, req, nil)
+	fail(ctx, req, nil)
+
+	rsp := httptest.NewRecorder()
+	Handler(r).ServeHTTP(rsp, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rsp.Header().Get("Content-Type"); ct != ContentType {
+		t.Fatalf("Unexpected content type %s", ct)
+	}
+
+	body := rsp.Body.String()
+	for _, line := range []string{
+		`stack_server_requests_total{service="foo",endpoint="Foo.Bar",code="200",peer="10.0.0.1"} 2`,
+		`stack_server_requests_total{service="foo",endpoint="Foo.Bar",code="404",peer="10.0.0.1"} 1`,
+		`stack_server_request_duration_seconds_count{service="foo",endpoint="Foo.Bar",code="200"} 2`,
+		`stack_server_requests_inflight{service="foo",endpoint="Foo.Bar"} 0`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Fatalf("Expected %s in\n%s", line, body)
+		}
+	}
+}
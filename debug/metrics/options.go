@@ -0,0 +1,37 @@
+package metrics
+
+type Options struct {
+	// Registry the metrics are recorded in
+	Registry *Registry
+	// Buckets of the duration histograms in seconds
+	Buckets []float64
+}
+
+type Option func(o *Options)
+
+// WithRegistry sets the registry the metrics are recorded in
+func WithRegistry(r *Registry) Option {
+	return func(o *Options) {
+		o.Registry = r
+	}
+}
+
+// WithBuckets sets the buckets of the duration histograms in seconds
+func WithBuckets(b ...float64) Option {
+	return func(o *Options) {
+		o.Buckets = b
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Registry: DefaultRegistry,
+		Buckets:  DefaultBuckets,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return options
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// memStats caches runtime.ReadMemStats, which stops the world,
+// so the metrics gathered at once read it a single time
+type memStats struct {
+	sync.Mutex
+	read  time.Time
+	stats runtime.MemStats
+}
+
+func (m *memStats) get() runtime.MemStats {
+	m.Lock()
+	defer m.Unlock()
+
+	if time.Since(m.read) > time.Second {
+		runtime.ReadMemStats(&m.stats)
+		m.read = time.Now()
+	}
+	return m.stats
+}
+
+// RegisterProcess registers the start time, uptime, goroutines,
+// memory and gc metrics of the process
+func RegisterProcess(r *Registry) {
+	started := time.Now()
+	ms := new(memStats)
+
+	r.GaugeFunc("process_start_time_seconds", "Start time of the process since unix epoch in seconds.", func() float64 {
+		return float64(started.Unix())
+	})
+	r.GaugeFunc("process_uptime_seconds", "Uptime of the process in seconds.", func() float64 {
+		return time.Since(started).Seconds()
+	})
+	r.GaugeFunc("go_goroutines", "Number of goroutines that currently exist.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+	r.GaugeFunc("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", func() float64 {
+		return float64(ms.get().Alloc)
+	})
+	r.GaugeFunc("go_memstats_sys_bytes", "Number of bytes obtained from system.", func() float64 {
+		return float64(ms.get().Sys)
+	})
+	r.CounterFunc("go_gc_pause_seconds_total", "Total time spent in gc pauses in seconds.", func() float64 {
+		return float64(ms.get().PauseTotalNs) / float64(time.Second)
+	})
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
+	"github.com/stack-labs/stack/util/wrapper"
+)
+
+const (
+	// CodeOK is the code label of requests which didn't fail
+	CodeOK = "200"
+
+	// ServerRequests is the counter of the requests handled
+	ServerRequests = "stack_server_requests_total"
+	// ServerDuration is the histogram of the time taken to handle requests
+	ServerDuration = "stack_server_request_duration_seconds"
+	// ServerInflight is the gauge of the requests being handled
+	ServerInflight = "stack_server_requests_inflight"
+
+	// ClientRequests is the counter of the calls made
+	ClientRequests = "stack_client_requests_total"
+	// ClientDuration is the histogram of the time taken by calls
+	ClientDuration = "stack_client_request_duration_seconds"
+
+	// SubscriberMessages is the counter of the messages handled
+	SubscriberMessages = "stack_subscriber_messages_total"
+	// SubscriberDuration is the histogram of the time taken to handle messages
+	SubscriberDuration = "stack_subscriber_message_duration_seconds"
+)
+
+// code of the error as a label, errors without a code count as a 500
+func code(err error) string {
+	if err == nil {
+		return CodeOK
+	}
+	e := errors.Parse(err.Error())
+	if e.Code == 0 {
+		return "500"
+	}
+	return strconv.Itoa(int(e.Code))
+}
+
+// serverPeer is the calling service if known or the host of the remote address.
+// The port is left out so the label doesn't grow with every connection.
+func serverPeer(ctx context.Context) string {
+	if from, ok := metadata.Get(ctx, wrapper.HeaderPrefix+"From-Service"); ok && len(from) > 0 {
+		return from
+	}
+	remote, ok := metadata.Get(ctx, "Remote")
+	if !ok {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
+
+// NewHandlerWrapper records the requests handled by the server, labelled
+// by service, endpoint, code and peer, along with their duration
+func NewHandlerWrapper(opts ...Option) server.HandlerWrapper {
+	options := newOptions(opts...)
+
+	requests := options.Registry.Counter(ServerRequests, "Total requests handled by the server.", "service", "endpoint", "code", "peer")
+	duration := options.Registry.Histogram(ServerDuration, "Time taken to handle requests in seconds.", options.Buckets, "service", "endpoint", "code")
+	inflight := options.Registry.Gauge(ServerInflight, "Requests being handled by the server.", "service", "endpoint")
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			service, endpoint := req.Service(), req.Endpoint()
+
+			inflight.Inc(service, endpoint)
+			start := time.Now()
+
+			err := h(ctx, req, rsp)
+
+			c := code(err)
+			duration.Observe(time.Since(start).Seconds(), service, endpoint, c)
+			requests.Inc(service, endpoint, c, serverPeer(ctx))
+			inflight.Dec(service, endpoint)
+
+			return err
+		}
+	}
+}
+
+// NewCallWrapper records the calls made by the client, labelled by
+// service, endpoint, code and the address of the node called
+func NewCallWrapper(opts ...Option) client.CallWrapper {
+	options := newOptions(opts...)
+
+	requests := options.Registry.Counter(ClientRequests, "Total calls made by the client.", "service", "endpoint", "code", "peer")
+	duration := options.Registry.Histogram(ClientDuration, "Time taken by calls in seconds.", options.Buckets, "service", "endpoint", "code")
+
+	return func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			start := time.Now()
+
+			err := cf(ctx, node, req, rsp, opts)
+
+			c := code(err)
+			duration.Observe(time.Since(start).Seconds(), req.Service(), req.Endpoint(), c)
+			requests.Inc(req.Service(), req.Endpoint(), c, node.Address)
+
+			return err
+		}
+	}
+}
+
+// NewSubscriberWrapper records the messages handled by subscribers,
+// labelled by topic and code, along with their duration
+func NewSubscriberWrapper(opts ...Option) server.SubscriberWrapper {
+	options := newOptions(opts...)
+
+	messages := options.Registry.Counter(SubscriberMessages, "Total messages handled by subscribers.", "topic", "code")
+	duration := options.Registry.Histogram(SubscriberDuration, "Time taken to handle messages in seconds.", options.Buckets, "topic", "code")
+
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) error {
+			start := time.Now()
+
+			err := fn(ctx, msg)
+
+			c := code(err)
+			duration.Observe(time.Since(start).Seconds(), msg.Topic(), c)
+			messages.Inc(msg.Topic(), c)
+
+			return err
+		}
+	}
+}
@@ -87,10 +87,12 @@ func (m *HealthRequest) GetService() string {
 
 type HealthResponse struct {
 	// default: ok
-	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Status string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// results of the readiness checks
+	Checks               []*HealthCheck `protobuf:"bytes,2,rep,name=checks,proto3" json:"checks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *HealthResponse) Reset()         { *m = HealthResponse{} }
@@ -125,6 +127,87 @@ func (m *HealthResponse) GetStatus() string {
 	return ""
 }
 
+func (m *HealthResponse) GetChecks() []*HealthCheck {
+	if m != nil {
+		return m.Checks
+	}
+	return nil
+}
+
+type HealthCheck struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// ok or failing
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error  string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// counts for liveness
+	Liveness bool `protobuf:"varint,4,opt,name=liveness,proto3" json:"liveness,omitempty"`
+	// in nanoseconds
+	Duration             uint64   `protobuf:"varint,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HealthCheck) Reset()         { *m = HealthCheck{} }
+func (m *HealthCheck) String() string { return proto.CompactTextString(m) }
+func (*HealthCheck) ProtoMessage()    {}
+func (*HealthCheck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d9d361be58531fb, []int{2}
+}
+
+func (m *HealthCheck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HealthCheck.Unmarshal(m, b)
+}
+func (m *HealthCheck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HealthCheck.Marshal(b, m, deterministic)
+}
+func (m *HealthCheck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HealthCheck.Merge(m, src)
+}
+func (m *HealthCheck) XXX_Size() int {
+	return xxx_messageInfo_HealthCheck.Size(m)
+}
+func (m *HealthCheck) XXX_DiscardUnknown() {
+	xxx_messageInfo_HealthCheck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HealthCheck proto.InternalMessageInfo
+
+func (m *HealthCheck) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *HealthCheck) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *HealthCheck) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *HealthCheck) GetLiveness() bool {
+	if m != nil {
+		return m.Liveness
+	}
+	return false
+}
+
+func (m *HealthCheck) GetDuration() uint64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
 type StatsRequest struct {
 	// optional service name
 	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
@@ -137,7 +220,7 @@ func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
 func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
 func (*StatsRequest) ProtoMessage()    {}
 func (*StatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{2}
+	return fileDescriptor_8d9d361be58531fb, []int{3}
 }
 
 func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
@@ -181,17 +264,19 @@ type StatsResponse struct {
 	// total number of requests
 	Requests uint64 `protobuf:"varint,7,opt,name=requests,proto3" json:"requests,omitempty"`
 	// total number of errors
-	Errors               uint64   `protobuf:"varint,8,opt,name=errors,proto3" json:"errors,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Errors uint64 `protobuf:"varint,8,opt,name=errors,proto3" json:"errors,omitempty"`
+	// client circuit breakers
+	Breakers             []*Breaker `protobuf:"bytes,9,rep,name=breakers,proto3" json:"breakers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
 func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
 func (*StatsResponse) ProtoMessage()    {}
 func (*StatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{3}
+	return fileDescriptor_8d9d361be58531fb, []int{4}
 }
 
 func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
@@ -268,6 +353,99 @@ func (m *StatsResponse) GetErrors() uint64 {
 	return 0
 }
 
+func (m *StatsResponse) GetBreakers() []*Breaker {
+	if m != nil {
+		return m.Breakers
+	}
+	return nil
+}
+
+// Breaker is a client circuit breaker
+type Breaker struct {
+	// name of the circuit e.g. service/endpoint
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// closed, open or half-open
+	State string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// consecutive failures
+	Failures uint64 `protobuf:"varint,3,opt,name=failures,proto3" json:"failures,omitempty"`
+	// requests within the window
+	Requests uint64 `protobuf:"varint,4,opt,name=requests,proto3" json:"requests,omitempty"`
+	// errors within the window
+	Errors uint64 `protobuf:"varint,5,opt,name=errors,proto3" json:"errors,omitempty"`
+	// unix timestamp the circuit was last opened
+	Opened               uint64   `protobuf:"varint,6,opt,name=opened,proto3" json:"opened,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Breaker) Reset()         { *m = Breaker{} }
+func (m *Breaker) String() string { return proto.CompactTextString(m) }
+func (*Breaker) ProtoMessage()    {}
+func (*Breaker) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d9d361be58531fb, []int{5}
+}
+
+func (m *Breaker) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Breaker.Unmarshal(m, b)
+}
+func (m *Breaker) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Breaker.Marshal(b, m, deterministic)
+}
+func (m *Breaker) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Breaker.Merge(m, src)
+}
+func (m *Breaker) XXX_Size() int {
+	return xxx_messageInfo_Breaker.Size(m)
+}
+func (m *Breaker) XXX_DiscardUnknown() {
+	xxx_messageInfo_Breaker.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Breaker proto.InternalMessageInfo
+
+func (m *Breaker) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Breaker) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Breaker) GetFailures() uint64 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *Breaker) GetRequests() uint64 {
+	if m != nil {
+		return m.Requests
+	}
+	return 0
+}
+
+func (m *Breaker) GetErrors() uint64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
+}
+
+func (m *Breaker) GetOpened() uint64 {
+	if m != nil {
+		return m.Opened
+	}
+	return 0
+}
+
 // LogRequest requests service logs
 type LogRequest struct {
 	// service to request logs for
@@ -289,7 +467,7 @@ func (m *LogRequest) Reset()         { *m = LogRequest{} }
 func (m *LogRequest) String() string { return proto.CompactTextString(m) }
 func (*LogRequest) ProtoMessage()    {}
 func (*LogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{4}
+	return fileDescriptor_8d9d361be58531fb, []int{6}
 }
 
 func (m *LogRequest) XXX_Unmarshal(b []byte) error {
@@ -355,7 +533,7 @@ func (m *Record) Reset()         { *m = Record{} }
 func (m *Record) String() string { return proto.CompactTextString(m) }
 func (*Record) ProtoMessage()    {}
 func (*Record) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{5}
+	return fileDescriptor_8d9d361be58531fb, []int{7}
 }
 
 func (m *Record) XXX_Unmarshal(b []byte) error {
@@ -409,7 +587,7 @@ func (m *TraceRequest) Reset()         { *m = TraceRequest{} }
 func (m *TraceRequest) String() string { return proto.CompactTextString(m) }
 func (*TraceRequest) ProtoMessage()    {}
 func (*TraceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{6}
+	return fileDescriptor_8d9d361be58531fb, []int{8}
 }
 
 func (m *TraceRequest) XXX_Unmarshal(b []byte) error {
@@ -448,7 +626,7 @@ func (m *TraceResponse) Reset()         { *m = TraceResponse{} }
 func (m *TraceResponse) String() string { return proto.CompactTextString(m) }
 func (*TraceResponse) ProtoMessage()    {}
 func (*TraceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{7}
+	return fileDescriptor_8d9d361be58531fb, []int{9}
 }
 
 func (m *TraceResponse) XXX_Unmarshal(b []byte) error {
@@ -501,7 +679,7 @@ func (m *Span) Reset()         { *m = Span{} }
 func (m *Span) String() string { return proto.CompactTextString(m) }
 func (*Span) ProtoMessage()    {}
 func (*Span) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{8}
+	return fileDescriptor_8d9d361be58531fb, []int{10}
 }
 
 func (m *Span) XXX_Unmarshal(b []byte) error {
@@ -582,8 +760,10 @@ func init() {
 	proto.RegisterEnum("SpanType", SpanType_name, SpanType_value)
 	proto.RegisterType((*HealthRequest)(nil), "HealthRequest")
 	proto.RegisterType((*HealthResponse)(nil), "HealthResponse")
+	proto.RegisterType((*HealthCheck)(nil), "HealthCheck")
 	proto.RegisterType((*StatsRequest)(nil), "StatsRequest")
 	proto.RegisterType((*StatsResponse)(nil), "StatsResponse")
+	proto.RegisterType((*Breaker)(nil), "Breaker")
 	proto.RegisterType((*LogRequest)(nil), "LogRequest")
 	proto.RegisterType((*Record)(nil), "Record")
 	proto.RegisterMapType((map[string]string)(nil), "Record.MetadataEntry")
@@ -596,42 +776,50 @@ func init() {
 func init() { proto.RegisterFile("debug.proto", fileDescriptor_8d9d361be58531fb) }
 
 var fileDescriptor_8d9d361be58531fb = []byte{
-	// 586 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdb, 0x6e, 0xd3, 0x40,
-	0x10, 0x8d, 0xed, 0x38, 0xb1, 0x27, 0x8d, 0xa9, 0x96, 0x8b, 0x2c, 0x73, 0xab, 0x2c, 0x21, 0x85,
-	0x8b, 0x0c, 0x94, 0x17, 0x04, 0x6f, 0xa8, 0x48, 0x20, 0x95, 0x56, 0xda, 0xb6, 0x1f, 0xb0, 0xb5,
-	0x47, 0xae, 0xa1, 0xbe, 0xb0, 0xbb, 0xae, 0x94, 0x6f, 0xe1, 0x0b, 0x78, 0xe3, 0x67, 0xf8, 0x1f,
-	0xb4, 0x17, 0xa7, 0xb1, 0x10, 0xea, 0x03, 0x6f, 0x3e, 0x67, 0x67, 0x4f, 0x66, 0x4e, 0xce, 0x0e,
-	0x2c, 0x0a, 0x3c, 0xef, 0xcb, 0xac, 0xe3, 0xad, 0x6c, 0xd3, 0xa7, 0xb0, 0xfc, 0x84, 0xec, 0x52,
-	0x5e, 0x50, 0xfc, 0xde, 0xa3, 0x90, 0x24, 0x86, 0xb9, 0x40, 0x7e, 0x55, 0xe5, 0x18, 0x3b, 0x7b,
-	0xce, 0x2a, 0xa4, 0x03, 0x4c, 0x57, 0x10, 0x0d, 0xa5, 0xa2, 0x6b, 0x1b, 0x81, 0xe4, 0x1e, 0xcc,
-	0x84, 0x64, 0xb2, 0x17, 0xb6, 0xd4, 0xa2, 0x74, 0x05, 0x3b, 0x27, 0x92, 0x49, 0x71, 0xb3, 0xe6,
-	0x6f, 0x07, 0x96, 0xb6, 0xd4, 0x6a, 0x3e, 0x80, 0x50, 0x56, 0x35, 0x0a, 0xc9, 0xea, 0x4e, 0x57,
-	0x4f, 0xe9, 0x35, 0xa1, 0x95, 0x24, 0xe3, 0x12, 0x8b, 0xd8, 0xd5, 0x67, 0x03, 0x54, 0xbd, 0xf4,
-	0x9d, 0x2a, 0x8c, 0x3d, 0x7d, 0x60, 0x91, 0xe2, 0x6b, 0xac, 0x5b, 0xbe, 0x8e, 0xa7, 0x86, 0x37,
-	0x48, 0x29, 0xc9, 0x0b, 0x8e, 0xac, 0x10, 0xb1, 0x6f, 0x94, 0x2c, 0x24, 0x11, 0xb8, 0x65, 0x1e,
-	0xcf, 0x34, 0xe9, 0x96, 0x39, 0x49, 0x20, 0xe0, 0x66, 0x10, 0x11, 0xcf, 0x35, 0xbb, 0xc1, 0x4a,
-	0x1d, 0x39, 0x6f, 0xb9, 0x88, 0x03, 0xa3, 0x6e, 0x50, 0xfa, 0x15, 0xe0, 0xb0, 0x2d, 0x6f, 0x9c,
-	0xdf, 0x38, 0xc8, 0x91, 0xd5, 0x7a, 0x9c, 0x80, 0x5a, 0x44, 0xee, 0x80, 0x9f, 0xb7, 0x7d, 0x23,
-	0xf5, 0x30, 0x1e, 0x35, 0x40, 0xb1, 0xa2, 0x6a, 0x72, 0xd4, 0xa3, 0x78, 0xd4, 0x80, 0xf4, 0x97,
-	0x03, 0x33, 0x8a, 0x79, 0xcb, 0x8b, 0xbf, 0xcd, 0xf3, 0xb6, 0xcd, 0x7b, 0x0d, 0x41, 0x8d, 0x92,
-	0x15, 0x4c, 0xb2, 0xd8, 0xdd, 0xf3, 0x56, 0x8b, 0xfd, 0xbb, 0x99, 0xb9, 0x98, 0x7d, 0xb1, 0xfc,
-	0xc7, 0x46, 0xf2, 0x35, 0xdd, 0x94, 0xa9, 0xce, 0x6b, 0x14, 0x82, 0x95, 0xc6, 0xd6, 0x90, 0x0e,
-	0x30, 0x79, 0x0f, 0xcb, 0xd1, 0x25, 0xb2, 0x0b, 0xde, 0x37, 0x5c, 0xdb, 0x01, 0xd5, 0xa7, 0x6a,
-	0xf7, 0x8a, 0x5d, 0xf6, 0xa8, 0x67, 0x0b, 0xa9, 0x01, 0xef, 0xdc, 0xb7, 0x4e, 0xfa, 0x08, 0x76,
-	0x4e, 0x39, 0xcb, 0x71, 0x30, 0x28, 0x02, 0xb7, 0x2a, 0xec, 0x55, 0xb7, 0x2a, 0xd2, 0x17, 0xb0,
-	0xb4, 0xe7, 0x36, 0x15, 0xf7, 0xc1, 0x17, 0x1d, 0x6b, 0x54, 0xd0, 0x54, 0xdf, 0x7e, 0x76, 0xd2,
-	0xb1, 0x86, 0x1a, 0x2e, 0xfd, 0xe1, 0xc2, 0x54, 0x61, 0xf5, 0x83, 0x52, 0x5d, 0xb3, 0x4a, 0x06,
-	0x58, 0x71, 0x77, 0x10, 0x57, 0x9e, 0x77, 0x8c, 0xa3, 0x35, 0x37, 0xa4, 0x16, 0x11, 0x02, 0xd3,
-	0x86, 0xd5, 0xc6, 0xdc, 0x90, 0xea, 0xef, 0xed, 0xbc, 0xf9, 0xe3, 0xbc, 0x25, 0x10, 0x14, 0x3d,
-	0x67, 0xb2, 0x6a, 0x1b, 0x9b, 0x95, 0x0d, 0x26, 0x2f, 0xb7, 0x8c, 0x9e, 0xeb, 0x86, 0x6f, 0xeb,
-	0x86, 0xff, 0x69, 0xf3, 0x43, 0x98, 0xca, 0x75, 0x87, 0x3a, 0x44, 0xd1, 0x7e, 0xa8, 0x8b, 0x4f,
-	0xd7, 0x1d, 0x52, 0x4d, 0xff, 0x97, 0xd7, 0xcf, 0x9e, 0x40, 0x30, 0xc8, 0x91, 0x05, 0xcc, 0x3f,
-	0x1f, 0x7d, 0x38, 0x3e, 0x3b, 0x3a, 0xd8, 0x9d, 0x90, 0x1d, 0x08, 0x8e, 0xcf, 0x4e, 0x0d, 0x72,
-	0xf6, 0x7f, 0x3a, 0xe0, 0x1f, 0xa8, 0xc5, 0x40, 0x1e, 0x83, 0x77, 0xd8, 0x96, 0x64, 0x91, 0x5d,
-	0x27, 0x38, 0x99, 0xdb, 0xa0, 0xa4, 0x93, 0x57, 0x0e, 0x79, 0x0e, 0x33, 0xb3, 0x08, 0x48, 0x94,
-	0x8d, 0x96, 0x47, 0x72, 0x2b, 0x1b, 0x6f, 0x88, 0x74, 0x42, 0x56, 0xe0, 0xeb, 0x07, 0x4e, 0x96,
-	0xd9, 0xf6, 0x4e, 0x48, 0xa2, 0x6c, 0xf4, 0xee, 0x4d, 0xa5, 0xfe, 0xd3, 0xc9, 0x32, 0xdb, 0x0e,
-	0x47, 0x12, 0x65, 0xa3, 0x2c, 0xa4, 0x93, 0xf3, 0x99, 0xde, 0x5d, 0x6f, 0xfe, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0x08, 0x25, 0x21, 0x57, 0xca, 0x04, 0x00, 0x00,
+	// 717 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0xdb, 0x6e, 0xdb, 0x46,
+	0x10, 0x15, 0x29, 0x92, 0xa2, 0x46, 0x97, 0x1a, 0x5b, 0xd7, 0x20, 0xd4, 0x9b, 0x40, 0xb8, 0x80,
+	0x7a, 0x01, 0xdb, 0xba, 0x2f, 0x45, 0xfb, 0xe6, 0x38, 0x40, 0x02, 0x38, 0x36, 0xb0, 0xb6, 0x3f,
+	0x60, 0x4d, 0x4e, 0x64, 0xc6, 0xe2, 0x25, 0xbb, 0x4b, 0x03, 0xfa, 0x82, 0x7c, 0x84, 0xbf, 0x20,
+	0x6f, 0xf9, 0xbf, 0xbc, 0x04, 0x7b, 0xa1, 0x4c, 0x22, 0x31, 0xfc, 0x90, 0x37, 0x9e, 0xd9, 0xd9,
+	0xd9, 0x33, 0x33, 0x67, 0x86, 0x30, 0xc9, 0xf0, 0xba, 0x59, 0x27, 0x35, 0xaf, 0x64, 0x15, 0xff,
+	0x0a, 0xb3, 0x17, 0xc8, 0x36, 0xf2, 0x86, 0xe2, 0xdb, 0x06, 0x85, 0x24, 0x11, 0x8c, 0x04, 0xf2,
+	0xbb, 0x3c, 0xc5, 0xc8, 0x59, 0x3a, 0xab, 0x31, 0x6d, 0x61, 0x7c, 0x06, 0xf3, 0xd6, 0x55, 0xd4,
+	0x55, 0x29, 0x90, 0x1c, 0x40, 0x20, 0x24, 0x93, 0x8d, 0xb0, 0xae, 0x16, 0x91, 0x43, 0x08, 0xd2,
+	0x1b, 0x4c, 0x6f, 0x45, 0xe4, 0x2e, 0x87, 0xab, 0xc9, 0xd1, 0x34, 0x31, 0x17, 0x9f, 0x29, 0x23,
+	0xb5, 0x67, 0xf1, 0x3b, 0x07, 0x26, 0x1d, 0x3b, 0x21, 0xe0, 0x95, 0xac, 0x68, 0x9f, 0xd5, 0xdf,
+	0x9d, 0x17, 0xdc, 0xde, 0x0b, 0xfb, 0xe0, 0x23, 0xe7, 0x15, 0x8f, 0x86, 0xda, 0x6c, 0x00, 0x59,
+	0x40, 0xb8, 0xc9, 0xef, 0xb0, 0x44, 0x21, 0x22, 0x6f, 0xe9, 0xac, 0x42, 0xba, 0xc3, 0xea, 0x2c,
+	0x6b, 0x38, 0x93, 0x79, 0x55, 0x46, 0xfe, 0xd2, 0x59, 0x79, 0x74, 0x87, 0xe3, 0x15, 0x4c, 0x2f,
+	0x24, 0x93, 0xe2, 0xe9, 0x1a, 0x7c, 0x74, 0x60, 0x66, 0x5d, 0x6d, 0x0d, 0x7e, 0x80, 0xb1, 0xcc,
+	0x0b, 0x14, 0x92, 0x15, 0xb5, 0xf6, 0xf6, 0xe8, 0x83, 0x41, 0x47, 0x92, 0x8c, 0x4b, 0xcc, 0x74,
+	0x02, 0x1e, 0x6d, 0xa1, 0xca, 0xac, 0xa9, 0x95, 0xa3, 0x4e, 0xc1, 0xa3, 0x16, 0x29, 0x7b, 0x81,
+	0x45, 0xc5, 0xb7, 0x3a, 0x03, 0x8f, 0x5a, 0xa4, 0x22, 0xc9, 0x1b, 0x8e, 0x2c, 0x13, 0x96, 0x7e,
+	0x0b, 0xc9, 0x1c, 0xdc, 0x75, 0x1a, 0x05, 0xda, 0xe8, 0xae, 0x53, 0x95, 0x29, 0x37, 0x89, 0x88,
+	0x68, 0x64, 0x32, 0x6d, 0xb1, 0x8a, 0xae, 0x4b, 0x25, 0xa2, 0xd0, 0x44, 0x37, 0x88, 0x1c, 0x42,
+	0x78, 0xcd, 0x91, 0xdd, 0x22, 0x17, 0xd1, 0x58, 0xf7, 0x2c, 0x4c, 0x8e, 0x8d, 0x81, 0xee, 0x4e,
+	0xe2, 0x7b, 0x07, 0x46, 0xd6, 0xfa, 0xc5, 0x6e, 0xed, 0x83, 0xaf, 0xfa, 0x83, 0xb6, 0x59, 0x06,
+	0x28, 0x3e, 0xaf, 0x59, 0xbe, 0x69, 0x38, 0x0a, 0x9b, 0xeb, 0x0e, 0xf7, 0xb8, 0x7a, 0x8f, 0x72,
+	0xf5, 0x7b, 0x5c, 0x0f, 0x20, 0xa8, 0x6a, 0x2c, 0x31, 0xb3, 0x39, 0x5b, 0x14, 0xbf, 0x01, 0x38,
+	0xad, 0xd6, 0x4f, 0xf6, 0xd0, 0x68, 0x8a, 0x23, 0x2b, 0x34, 0xcd, 0x90, 0x5a, 0xa4, 0xd8, 0xa7,
+	0x55, 0x53, 0x4a, 0x4d, 0x72, 0x48, 0x0d, 0xd0, 0x39, 0xe5, 0x65, 0x8a, 0x9a, 0xde, 0x90, 0x1a,
+	0x10, 0x7f, 0x70, 0x20, 0xa0, 0x98, 0x56, 0x3c, 0xfb, 0x5c, 0x00, 0xc3, 0xae, 0x00, 0xfe, 0x86,
+	0xb0, 0x40, 0xc9, 0x32, 0x26, 0x99, 0x1d, 0x86, 0xef, 0x12, 0x73, 0x31, 0x79, 0x65, 0xed, 0xcf,
+	0x4b, 0xc9, 0xb7, 0x74, 0xe7, 0xa6, 0x98, 0x17, 0x28, 0x04, 0x5b, 0xa3, 0x55, 0x77, 0x0b, 0x17,
+	0xff, 0xc3, 0xac, 0x77, 0x89, 0xec, 0xc1, 0xf0, 0x16, 0xb7, 0x36, 0x41, 0xf5, 0xa9, 0xe8, 0xde,
+	0xb1, 0x4d, 0xb3, 0x6b, 0x81, 0x06, 0xff, 0xb9, 0xff, 0x3a, 0xf1, 0x4f, 0x30, 0xbd, 0xe4, 0x2c,
+	0xc5, 0xb6, 0x40, 0x73, 0x70, 0xf3, 0xcc, 0x5e, 0x75, 0xf3, 0x2c, 0xfe, 0x03, 0x66, 0xf6, 0xdc,
+	0x2a, 0xfb, 0x7b, 0xf0, 0x45, 0xcd, 0x4a, 0x35, 0xdc, 0x8a, 0xb7, 0x9f, 0x5c, 0xd4, 0xac, 0xa4,
+	0xc6, 0x16, 0xdf, 0xbb, 0xe0, 0x29, 0xac, 0x1e, 0x94, 0xea, 0x9a, 0x8d, 0x64, 0x80, 0x0d, 0xee,
+	0xb6, 0xc1, 0x55, 0xcd, 0x6b, 0xc6, 0xd1, 0x16, 0x77, 0x4c, 0x2d, 0xda, 0xa9, 0xc8, 0xeb, 0xa8,
+	0xa8, 0x33, 0x33, 0x7e, 0x7f, 0x66, 0xba, 0x33, 0x1c, 0xf4, 0x67, 0x98, 0xfc, 0xd9, 0x29, 0xf4,
+	0x48, 0x13, 0xfe, 0x56, 0x13, 0x7e, 0xb4, 0xcc, 0x3f, 0x82, 0x27, 0xb7, 0x35, 0xea, 0x41, 0x98,
+	0x1f, 0x8d, 0xb5, 0xf3, 0xe5, 0xb6, 0x46, 0xaa, 0xcd, 0x5f, 0x55, 0xeb, 0xdf, 0x7e, 0x81, 0xb0,
+	0x0d, 0x47, 0x26, 0x30, 0x7a, 0x79, 0x76, 0x7c, 0x7e, 0x75, 0x76, 0xb2, 0x37, 0x20, 0x53, 0x08,
+	0xcf, 0xaf, 0x2e, 0x0d, 0x72, 0x8e, 0xde, 0x3b, 0xe0, 0x9f, 0xa8, 0x65, 0x4c, 0x7e, 0x86, 0xe1,
+	0x69, 0xb5, 0x26, 0x93, 0xe4, 0x41, 0xc1, 0x8b, 0x91, 0x15, 0x4a, 0x3c, 0xf8, 0xcb, 0x21, 0xbf,
+	0x43, 0x60, 0x76, 0x25, 0x99, 0x27, 0xbd, 0x85, 0xbd, 0xf8, 0x26, 0xe9, 0x6f, 0xe5, 0x78, 0x40,
+	0x56, 0xe0, 0xeb, 0x25, 0x45, 0x66, 0x49, 0x77, 0xaf, 0x2d, 0xe6, 0x49, 0x6f, 0x77, 0x19, 0x4f,
+	0xdd, 0x74, 0x32, 0x4b, 0xba, 0xe2, 0x58, 0xcc, 0x93, 0x9e, 0x16, 0xe2, 0xc1, 0x75, 0xa0, 0xff,
+	0x17, 0xff, 0x7c, 0x1a, 0x00, 0x54, 0x87, 0x48, 0x5d, 0x3e, 0x06, 0x00, 0x00,
 }
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/stack-labs/stack/debug/health"
 	"github.com/stack-labs/stack/debug/log"
 	proto "github.com/stack-labs/stack/debug/proto"
 	"github.com/stack-labs/stack/server"
@@ -19,18 +20,20 @@ var (
 type Debug struct {
 	started int64
 	proto.DebugHandler
-	log log.Log
+	log    log.Log
+	health *health.Health
 }
 
 func newDebug() *Debug {
 	return &Debug{
 		started: time.Now().Unix(),
 		log:     log.DefaultLog,
+		health:  health.DefaultHealth,
 	}
 }
 
 func (d *Debug) Health(ctx context.Context, req *proto.HealthRequest, rsp *proto.HealthResponse) error {
-	rsp.Status = "ok"
+	health.Encode(d.health.Ready(ctx), rsp)
 	return nil
 }
 
@@ -31,6 +31,8 @@ func (s *stats) Write(stat *Stat) error {
 
 // NewStats returns a new in memory stats buffer
 // TODO add options
+//
+// Deprecated: use metrics.NewRegistry of debug/metrics.
 func NewStats() Stats {
 	return &stats{
 		buffer: buffer.New(1024),
@@ -1,4 +1,7 @@
 // Package stats provides runtime stats
+//
+// Deprecated: the runtime stats are recorded by the debug/metrics registry,
+// which the Stats of the debug handler are read from.
 package stats
 
 // Stats provides stats interface
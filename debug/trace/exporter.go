@@ -0,0 +1,139 @@
+package trace
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/util/log"
+)
+
+// Exporter ships finished spans out of the process e.g. to a zipkin collector
+type Exporter interface {
+	// Export a batch of spans
+	Export([]*Span) error
+	// Name of the exporter
+	String() string
+}
+
+// Batcher queues finished spans and hands them to the exporters in
+// batches, once a batch is full or the batch interval has passed
+type Batcher struct {
+	opts Options
+
+	sync.Mutex
+	spans   []*Span
+	dropped int
+
+	flush chan bool
+	exit  chan bool
+	done  chan bool
+	once  sync.Once
+}
+
+// Add queues a finished span to be exported
+func (b *Batcher) Add(s *Span) {
+	b.Lock()
+	if len(b.spans) >= b.opts.QueueSize {
+		b.dropped++
+		b.Unlock()
+		return
+	}
+	b.spans = append(b.spans, s)
+	full := len(b.spans) >= b.opts.BatchSize
+	b.Unlock()
+
+	if !full {
+		return
+	}
+
+	select {
+	case b.flush <- true:
+	default:
+	}
+}
+
+// Flush exports the queued spans. The first error of an exporter is returned.
+func (b *Batcher) Flush() error {
+	b.Lock()
+	spans := b.spans
+	dropped := b.dropped
+	b.spans = nil
+	b.dropped = 0
+	b.Unlock()
+
+	if dropped > 0 {
+		log.Warnf("trace queue is full, dropped %d spans", dropped)
+	}
+
+	var gerr error
+
+	for len(spans) > 0 {
+		n := len(spans)
+		if n > b.opts.BatchSize {
+			n = b.opts.BatchSize
+		}
+
+		for _, e := range b.opts.Exporters {
+			if err := e.Export(spans[:n]); err != nil && gerr == nil {
+				gerr = err
+			}
+		}
+
+		spans = spans[n:]
+	}
+
+	return gerr
+}
+
+func (b *Batcher) run() {
+	defer close(b.done)
+
+	t := time.NewTicker(b.opts.BatchInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-b.exit:
+			return
+		case <-t.C:
+		case <-b.flush:
+		}
+
+		if err := b.Flush(); err != nil {
+			log.Errorf("trace export error: %v", err)
+		}
+	}
+}
+
+// Close stops the batcher and exports the queued spans
+func (b *Batcher) Close() error {
+	b.once.Do(func() {
+		close(b.exit)
+		<-b.done
+	})
+	return b.Flush()
+}
+
+// NewBatcher returns a batcher exporting to the exporters of the options
+func NewBatcher(opts Options) *Batcher {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.BatchInterval <= 0 {
+		opts.BatchInterval = DefaultBatchInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+
+	b := &Batcher{
+		opts:  opts,
+		flush: make(chan bool, 1),
+		exit:  make(chan bool),
+		done:  make(chan bool),
+	}
+
+	go b.run()
+
+	return b
+}
@@ -0,0 +1,65 @@
+package trace
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// NewTraceID returns a random 16 byte trace id in hex, as used by W3C trace context
+func NewTraceID() string {
+	return randomHex(16)
+}
+
+// NewSpanID returns a random 8 byte span id in hex, as used by W3C trace context
+func NewSpanID() string {
+	return randomHex(8)
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return strings.Trim(s, "0") != ""
+}
+
+// toHex returns the id as n hex characters. Ids which are not hex already,
+// e.g. uuids of older tracers, are hashed so they map to the same hex id
+// in every process.
+func toHex(id string, n int) string {
+	id = strings.ToLower(id)
+	if isHex(id, n) {
+		return id
+	}
+	if s := strings.Replace(id, "-", "", -1); isHex(s, n) {
+		return s
+	}
+	// pad 8 byte trace ids
+	if isHex(id, n/2) {
+		return strings.Repeat("0", n/2) + id
+	}
+	h := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(h[:n/2])
+}
+
+// HexTraceID returns the trace id as 32 hex characters
+func HexTraceID(id string) string {
+	return toHex(id, 32)
+}
+
+// HexSpanID returns the span id as 16 hex characters
+func HexSpanID(id string) string {
+	return toHex(id, 16)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/stack-labs/stack/debug/trace"
 	"github.com/stack-labs/stack/util/ring"
 )
@@ -14,6 +13,8 @@ type Tracer struct {
 
 	// ring buffer of traces
 	buffer *ring.Buffer
+	// batcher of the spans to export
+	batcher *trace.Batcher
 }
 
 func (t *Tracer) Read(opts ...trace.ReadOption) ([]*trace.Span, error) {
@@ -41,8 +42,8 @@ func (t *Tracer) Read(opts ...trace.ReadOption) ([]*trace.Span, error) {
 func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *trace.Span) {
 	span := &trace.Span{
 		Name:     name,
-		Trace:    uuid.New().String(),
-		Id:       uuid.New().String(),
+		Trace:    trace.NewTraceID(),
+		Id:       trace.NewSpanID(),
 		Started:  time.Now(),
 		Metadata: make(map[string]string),
 	}
@@ -73,18 +74,44 @@ func (t *Tracer) Finish(s *trace.Span) error {
 	// save the span
 	t.buffer.Put(s)
 
+	if t.batcher != nil {
+		t.batcher.Add(s)
+	}
+
 	return nil
 }
 
+// Flush exports the finished spans straight away
+func (t *Tracer) Flush() error {
+	if t.batcher == nil {
+		return nil
+	}
+	return t.batcher.Flush()
+}
+
+// Close exports the finished spans and stops exporting
+func (t *Tracer) Close() error {
+	if t.batcher == nil {
+		return nil
+	}
+	return t.batcher.Close()
+}
+
 func NewTracer(opts ...trace.Option) trace.Tracer {
-	var options trace.Options
+	options := trace.DefaultOptions()
 	for _, o := range opts {
 		o(&options)
 	}
 
-	return &Tracer{
+	t := &Tracer{
 		opts: options,
 		// the last 256 requests
 		buffer: ring.New(256),
 	}
+
+	if len(options.Exporters) > 0 {
+		t.batcher = trace.NewBatcher(options)
+	}
+
+	return t
 }
@@ -1,8 +1,21 @@
 package trace
 
+import (
+	"time"
+)
+
 type Options struct {
 	// Size is the size of ring buffer
 	Size int
+	// Exporters the finished spans are shipped to
+	Exporters []Exporter
+	// BatchSize is the number of spans exported at once
+	BatchSize int
+	// BatchInterval is the longest a span waits to be exported
+	BatchInterval time.Duration
+	// QueueSize is the most spans waiting to be exported,
+	// spans finished while the queue is full are dropped
+	QueueSize int
 }
 
 type Option func(o *Options)
@@ -21,14 +34,51 @@ func ReadTrace(t string) ReadOption {
 	}
 }
 
+// Export ships the finished spans to the exporters in batches
+func Export(e ...Exporter) Option {
+	return func(o *Options) {
+		o.Exporters = append(o.Exporters, e...)
+	}
+}
+
+// BatchSize sets the number of spans exported at once
+func BatchSize(n int) Option {
+	return func(o *Options) {
+		o.BatchSize = n
+	}
+}
+
+// BatchInterval sets the longest a span waits to be exported
+func BatchInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.BatchInterval = d
+	}
+}
+
+// QueueSize sets the most spans waiting to be exported
+func QueueSize(n int) Option {
+	return func(o *Options) {
+		o.QueueSize = n
+	}
+}
+
 const (
 	// DefaultSize of the buffer
 	DefaultSize = 64
+	// DefaultBatchSize of the exported spans
+	DefaultBatchSize = 128
+	// DefaultBatchInterval of the exported spans
+	DefaultBatchInterval = time.Second * 5
+	// DefaultQueueSize of the spans waiting to be exported
+	DefaultQueueSize = 2048
 )
 
 // DefaultOptions returns default options
 func DefaultOptions() Options {
 	return Options{
-		Size: DefaultSize,
+		Size:          DefaultSize,
+		BatchSize:     DefaultBatchSize,
+		BatchInterval: DefaultBatchInterval,
+		QueueSize:     DefaultQueueSize,
 	}
 }
@@ -0,0 +1,49 @@
+package otlp
+
+import (
+	"net/http"
+)
+
+type Options struct {
+	// Address of the otlp traces endpoint
+	Address string
+	// Service is the service.name of the resource
+	Service string
+	// Headers sent with every export e.g. for auth
+	Headers map[string]string
+	// Client used to post the spans
+	Client *http.Client
+}
+
+type Option func(o *Options)
+
+// Address sets the url of the otlp/http traces endpoint
+func Address(a string) Option {
+	return func(o *Options) {
+		o.Address = a
+	}
+}
+
+// Service sets the service.name of the resource
+func Service(s string) Option {
+	return func(o *Options) {
+		o.Service = s
+	}
+}
+
+// Header sets a header sent with every export
+func Header(k, v string) Option {
+	return func(o *Options) {
+		if o.Headers == nil {
+			o.Headers = make(map[string]string)
+		}
+		o.Headers[k] = v
+	}
+}
+
+// Client sets the http client used to post the spans
+func Client(c *http.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
@@ -0,0 +1,192 @@
+// Package otlp exports spans to an OpenTelemetry collector over OTLP/HTTP
+// using the JSON encoding
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/stack-labs/stack/debug/trace"
+)
+
+var (
+	// DefaultAddress is the traces endpoint of a local collector
+	DefaultAddress = "http://localhost:4318/v1/traces"
+)
+
+const (
+	spanKindServer = 2
+	spanKindClient = 3
+
+	statusCodeError = 2
+)
+
+type exporter struct {
+	opts Options
+}
+
+type value struct {
+	StringValue string `json:"stringValue"`
+}
+
+type keyValue struct {
+	Key   string `json:"key"`
+	Value value  `json:"value"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind,omitempty"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            *status    `json:"status,omitempty"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type scopeSpans struct {
+	Scope scope   `json:"scope"`
+	Spans []*span `json:"spans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes,omitempty"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+// request is an ExportTraceServiceRequest
+type request struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+func attributes(md map[string]string) []keyValue {
+	kv := make([]keyValue, 0, len(md))
+	for k, v := range md {
+		kv = append(kv, keyValue{Key: k, Value: value{StringValue: v}})
+	}
+	sort.Slice(kv, func(i, j int) bool {
+		return kv[i].Key < kv[j].Key
+	})
+	return kv
+}
+
+func toSpan(s *trace.Span) *span {
+	start := s.Started.UnixNano()
+
+	sp := &span{
+		TraceID:           trace.HexTraceID(s.Trace),
+		SpanID:            trace.HexSpanID(s.Id),
+		Name:              s.Name,
+		StartTimeUnixNano: strconv.FormatInt(start, 10),
+		EndTimeUnixNano:   strconv.FormatInt(start+int64(s.Duration), 10),
+		Attributes:        attributes(s.Metadata),
+	}
+
+	if len(s.Parent) > 0 {
+		sp.ParentSpanID = trace.HexSpanID(s.Parent)
+	}
+
+	switch s.Type {
+	case trace.SpanTypeRequestInbound:
+		sp.Kind = spanKindServer
+	case trace.SpanTypeRequestOutbound:
+		sp.Kind = spanKindClient
+	}
+
+	if msg, ok := s.Metadata["error"]; ok {
+		sp.Status = &status{Code: statusCodeError, Message: msg}
+	}
+
+	return sp
+}
+
+func (e *exporter) Export(spans []*trace.Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	ss := scopeSpans{
+		Scope: scope{Name: "stack"},
+		Spans: make([]*span, 0, len(spans)),
+	}
+	for _, s := range spans {
+		ss.Spans = append(ss.Spans, toSpan(s))
+	}
+
+	var res resource
+	if len(e.opts.Service) > 0 {
+		res.Attributes = attributes(map[string]string{"service.name": e.opts.Service})
+	}
+
+	b, err := json.Marshal(&request{
+		ResourceSpans: []resourceSpans{{Resource: res, ScopeSpans: []scopeSpans{ss}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", e.opts.Address, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	rsp, err := e.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	io.Copy(ioutil.Discard, rsp.Body)
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("otlp export to %s: %s", e.opts.Address, rsp.Status)
+	}
+
+	return nil
+}
+
+func (e *exporter) String() string {
+	return "otlp"
+}
+
+// NewExporter returns an exporter posting spans to DefaultAddress
+// unless another address is set
+func NewExporter(opts ...Option) trace.Exporter {
+	options := Options{
+		Address: DefaultAddress,
+		Client:  &http.Client{Timeout: time.Second * 10},
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &exporter{
+		opts: options,
+	}
+}
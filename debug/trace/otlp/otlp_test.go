@@ -0,0 +1,69 @@
+package otlp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/debug/trace"
+)
+
+func TestExporter(t *testing.T) {
+	ch := make(chan *request, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Unexpected content type %s", ct)
+		}
+		if key := r.Header.Get("Api-Key"); key != "secret" {
+			t.Errorf("Expected api key header got %s", key)
+		}
+		req := new(request)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Error(err)
+		}
+		ch <- req
+	}))
+	defer ts.Close()
+
+	e := NewExporter(Address(ts.URL), Service("greeter"), Header("Api-Key", "secret"))
+
+	err := e.Export([]*trace.Span{{
+		Trace:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		Id:       "00f067aa0ba902b7",
+		Name:     "greeter.Greeter.Hello",
+		Started:  time.Unix(1, 0),
+		Duration: time.Millisecond * 5,
+		Metadata: map[string]string{"error": "boom"},
+		Type:     trace.SpanTypeRequestOutbound,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-ch
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Unexpected request %+v", req)
+	}
+
+	rs := req.ResourceSpans[0]
+	if attrs := rs.Resource.Attributes; len(attrs) != 1 || attrs[0].Key != "service.name" || attrs[0].Value.StringValue != "greeter" {
+		t.Fatalf("Unexpected resource %+v", rs.Resource)
+	}
+
+	s := rs.ScopeSpans[0].Spans[0]
+	if s.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || s.SpanID != "00f067aa0ba902b7" || len(s.ParentSpanID) > 0 {
+		t.Fatalf("Unexpected ids %+v", s)
+	}
+	if s.Kind != spanKindClient {
+		t.Fatalf("Expected client span got %d", s.Kind)
+	}
+	if s.StartTimeUnixNano != "1000000000" || s.EndTimeUnixNano != "1005000000" {
+		t.Fatalf("Unexpected times %s %s", s.StartTimeUnixNano, s.EndTimeUnixNano)
+	}
+	if s.Status == nil || s.Status.Code != statusCodeError || s.Status.Message != "boom" {
+		t.Fatalf("Expected error status got %+v", s.Status)
+	}
+}
@@ -3,6 +3,7 @@ package trace
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/stack-labs/stack/pkg/metadata"
@@ -51,11 +52,42 @@ type Span struct {
 const (
 	traceIDKey = "Micro-Trace-Id"
 	spanIDKey  = "Micro-Span-Id"
+	// traceparentKey is the W3C trace context header
+	traceparentKey = "Traceparent"
 )
 
-// FromContext returns a span from context
+// Traceparent returns the W3C traceparent header of a sampled span
+func Traceparent(traceID, spanID string) string {
+	return "00-" + HexTraceID(traceID) + "-" + HexSpanID(spanID) + "-01"
+}
+
+// ParseTraceparent returns the trace and parent span ids of a W3C traceparent header
+func ParseTraceparent(v string) (traceID string, parentSpanID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", "", false
+	}
+	// version 00 has exactly four fields
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", "", false
+	}
+	if !isHex(parts[1], 32) || !isHex(parts[2], 16) {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// FromContext returns a span from context. The Micro-Trace-Id and
+// Micro-Span-Id metadata are used first, then a W3C traceparent.
 func FromContext(ctx context.Context) (traceID string, parentSpanID string, isFound bool) {
 	traceID, traceOk := metadata.Get(ctx, traceIDKey)
+	if !traceOk {
+		if tp, ok := getTraceparent(ctx); ok {
+			if traceID, parentSpanID, ok := ParseTraceparent(tp); ok {
+				return traceID, parentSpanID, true
+			}
+		}
+	}
 	microID, microOk := metadata.Get(ctx, "Micro-Id")
 	if !traceOk && !microOk {
 		isFound = false
@@ -68,12 +100,28 @@ func FromContext(ctx context.Context) (traceID string, parentSpanID string, isFo
 	return traceID, parentSpanID, ok
 }
 
-// ToContext saves the trace and span ids in the context
+// getTraceparent reads the header as set by stack or lower cased by grpc and http2
+func getTraceparent(ctx context.Context) (string, bool) {
+	if v, ok := metadata.Get(ctx, traceparentKey); ok {
+		return v, ok
+	}
+	return metadata.Get(ctx, strings.ToLower(traceparentKey))
+}
+
+// ToContext saves the trace and span ids in the context,
+// along with the matching W3C traceparent
 func ToContext(ctx context.Context, traceID, parentSpanID string) context.Context {
-	return metadata.MergeContext(ctx, map[string]string{
-		traceIDKey: traceID,
-		spanIDKey:  parentSpanID,
-	}, true)
+	md, _ := metadata.FromContext(ctx)
+	md = metadata.Copy(md)
+
+	// don't pass on the traceparent of the caller
+	delete(md, strings.ToLower(traceparentKey))
+
+	md[traceIDKey] = traceID
+	md[spanIDKey] = parentSpanID
+	md[traceparentKey] = Traceparent(traceID, parentSpanID)
+
+	return metadata.NewContext(ctx, md)
 }
 
 var (
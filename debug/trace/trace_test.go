@@ -0,0 +1,116 @@
+package trace
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/pkg/metadata"
+)
+
+func TestTraceparent(t *testing.T) {
+	traceID, spanID := NewTraceID(), NewSpanID()
+
+	ctx := ToContext(context.Background(), traceID, spanID)
+
+	tp, ok := metadata.Get(ctx, "Traceparent")
+	if !ok {
+		t.Fatal("Expected traceparent to be set")
+	}
+	if tp != "00-"+traceID+"-"+spanID+"-01" {
+		t.Fatalf("Unexpected traceparent %s", tp)
+	}
+
+	// a caller only passing the traceparent e.g. an otel instrumented service
+	ctx = metadata.NewContext(context.Background(), metadata.Metadata{"traceparent": tp})
+
+	tid, sid, ok := FromContext(ctx)
+	if !ok || tid != traceID || sid != spanID {
+		t.Fatalf("Expected %s %s got %s %s", traceID, spanID, tid, sid)
+	}
+
+	for _, v := range []string{
+		"",
+		"00-" + traceID + "-" + spanID,
+		"ff-" + traceID + "-" + spanID + "-01",
+		"00-00000000000000000000000000000000-" + spanID + "-01",
+		"00-" + traceID + "-xyz-01",
+	} {
+		if _, _, ok := ParseTraceparent(v); ok {
+			t.Fatalf("Expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestHexIDs(t *testing.T) {
+	testData := []struct {
+		id    string
+		trace string
+	}{
+		{"4bf92f3577b34da6a3ce929d0e0e4736", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"4bf92f35-77b3-4da6-a3ce-929d0e0e4736", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"a3ce929d0e0e4736", "0000000000000000a3ce929d0e0e4736"},
+	}
+
+	for _, d := range testData {
+		if id := HexTraceID(d.id); id != d.trace {
+			t.Fatalf("Expected %s got %s", d.trace, id)
+		}
+	}
+
+	// ids which aren't hex are hashed the same everywhere
+	if a, b := HexSpanID("not-hex"), HexSpanID("not-hex"); a != b || !isHex(a, 16) {
+		t.Fatalf("Expected stable hex span id got %s %s", a, b)
+	}
+}
+
+type testExporter struct {
+	sync.Mutex
+	batches [][]*Span
+}
+
+func (e *testExporter) Export(spans []*Span) error {
+	e.Lock()
+	e.batches = append(e.batches, spans)
+	e.Unlock()
+	return nil
+}
+
+func (e *testExporter) String() string {
+	return "test"
+}
+
+func TestBatcher(t *testing.T) {
+	e := new(testExporter)
+	b := NewBatcher(Options{
+		Exporters:     []Exporter{e},
+		BatchSize:     2,
+		BatchInterval: time.Hour,
+	})
+
+	// a full batch is exported straight away
+	b.Add(&Span{Name: "1"})
+	b.Add(&Span{Name: "2"})
+
+	for i := 0; i < 100; i++ {
+		e.Lock()
+		n := len(e.batches)
+		e.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	// the rest on close
+	b.Add(&Span{Name: "3"})
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.batches) != 2 || len(e.batches[0]) != 2 || len(e.batches[1]) != 1 {
+		t.Fatalf("Expected batches of 2 and 1 spans got %v", e.batches)
+	}
+}
@@ -0,0 +1,37 @@
+package zipkin
+
+import (
+	"net/http"
+)
+
+type Options struct {
+	// Address of the zipkin spans endpoint
+	Address string
+	// Service is the name of the local service
+	Service string
+	// Client used to post the spans
+	Client *http.Client
+}
+
+type Option func(o *Options)
+
+// Address sets the url of the zipkin v2 spans endpoint
+func Address(a string) Option {
+	return func(o *Options) {
+		o.Address = a
+	}
+}
+
+// Service sets the name of the local service
+func Service(s string) Option {
+	return func(o *Options) {
+		o.Service = s
+	}
+}
+
+// Client sets the http client used to post the spans
+func Client(c *http.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
@@ -0,0 +1,122 @@
+// Package zipkin exports spans to a zipkin collector in the JSON v2 format
+package zipkin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/stack-labs/stack/debug/trace"
+)
+
+var (
+	// DefaultAddress is the spans endpoint of a local zipkin
+	DefaultAddress = "http://localhost:9411/api/v2/spans"
+)
+
+type exporter struct {
+	opts Options
+}
+
+type endpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+type span struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint *endpoint         `json:"localEndpoint,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+func toSpan(s *trace.Span, service string) *span {
+	sp := &span{
+		TraceID:   trace.HexTraceID(s.Trace),
+		ID:        trace.HexSpanID(s.Id),
+		Name:      s.Name,
+		Timestamp: s.Started.UnixNano() / int64(time.Microsecond),
+		Duration:  int64(s.Duration / time.Microsecond),
+		Tags:      s.Metadata,
+	}
+
+	if len(s.Parent) > 0 {
+		sp.ParentID = trace.HexSpanID(s.Parent)
+	}
+
+	// zipkin drops spans without a duration
+	if sp.Duration == 0 {
+		sp.Duration = 1
+	}
+
+	switch s.Type {
+	case trace.SpanTypeRequestInbound:
+		sp.Kind = "SERVER"
+	case trace.SpanTypeRequestOutbound:
+		sp.Kind = "CLIENT"
+	}
+
+	if len(service) > 0 {
+		sp.LocalEndpoint = &endpoint{ServiceName: service}
+	}
+
+	return sp
+}
+
+func (e *exporter) Export(spans []*trace.Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	zs := make([]*span, 0, len(spans))
+	for _, s := range spans {
+		zs = append(zs, toSpan(s, e.opts.Service))
+	}
+
+	b, err := json.Marshal(zs)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := e.opts.Client.Post(e.opts.Address, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	io.Copy(ioutil.Discard, rsp.Body)
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("zipkin export to %s: %s", e.opts.Address, rsp.Status)
+	}
+
+	return nil
+}
+
+func (e *exporter) String() string {
+	return "zipkin"
+}
+
+// NewExporter returns an exporter posting spans to DefaultAddress
+// unless another address is set
+func NewExporter(opts ...Option) trace.Exporter {
+	options := Options{
+		Address: DefaultAddress,
+		Client:  &http.Client{Timeout: time.Second * 10},
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &exporter{
+		opts: options,
+	}
+}
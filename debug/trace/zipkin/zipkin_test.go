@@ -0,0 +1,74 @@
+package zipkin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/debug/trace"
+)
+
+func TestExporter(t *testing.T) {
+	ch := make(chan []map[string]interface{}, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var spans []map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&spans); err != nil {
+			t.Error(err)
+		}
+		ch <- spans
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	e := NewExporter(Address(ts.URL), Service("greeter"))
+
+	err := e.Export([]*trace.Span{{
+		Trace:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		Id:       "00f067aa0ba902b7",
+		Parent:   "a3ce929d0e0e4736",
+		Name:     "greeter.Greeter.Hello",
+		Started:  time.Unix(1, 0),
+		Duration: time.Millisecond * 5,
+		Metadata: map[string]string{"error": "boom"},
+		Type:     trace.SpanTypeRequestInbound,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := <-ch
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span got %d", len(spans))
+	}
+
+	s := spans[0]
+	for k, v := range map[string]interface{}{
+		"traceId":   "4bf92f3577b34da6a3ce929d0e0e4736",
+		"id":        "00f067aa0ba902b7",
+		"parentId":  "a3ce929d0e0e4736",
+		"kind":      "SERVER",
+		"timestamp": float64(1000000),
+		"duration":  float64(5000),
+	} {
+		if s[k] != v {
+			t.Fatalf("Expected %s to be %v got %v", k, v, s[k])
+		}
+	}
+
+	if s["localEndpoint"].(map[string]interface{})["serviceName"] != "greeter" {
+		t.Fatalf("Unexpected local endpoint %v", s["localEndpoint"])
+	}
+
+	// collector errors are returned
+	nf := httptest.NewServer(http.NotFoundHandler())
+	defer nf.Close()
+
+	e = NewExporter(Address(nf.URL))
+
+	if err := e.Export([]*trace.Span{{Name: "foo"}}); err == nil {
+		t.Fatal("Expected export error")
+	}
+}
@@ -31,7 +31,10 @@ func main() {
 	)
 	service.Init()
 
-	log.Infof("demoA: %s", value.Source.DemoA)
+	// the value is rebound as the config changes, read it under config.Read
+	config.Read(&value, func() {
+		log.Infof("demoA: %s", value.Source.DemoA)
+	})
 
 	go func() {
 		for {
@@ -39,7 +42,9 @@ func main() {
 			case <-time.After(2 * time.Second):
 				// try to change DemoA value in source.yml
 				// there will log the new value
-				log.Infof("demoA: %s", value.Source.DemoA)
+				config.Read(&value, func() {
+					log.Infof("demoA: %s", value.Source.DemoA)
+				})
 			}
 		}
 	}()
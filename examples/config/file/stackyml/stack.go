@@ -33,8 +33,11 @@ func main() {
 	service := stack.NewService()
 	service.Init()
 
-	log.Infof("demoA: %s", value.IncludeA.DemoA)
-	log.Infof("demoB: %s", value.IncludeA.IncludeB.DemoB)
+	// the value is rebound as the config changes, read it under config.Read
+	config.Read(&value, func() {
+		log.Infof("demoA: %s", value.IncludeA.DemoA)
+		log.Infof("demoB: %s", value.IncludeA.IncludeB.DemoB)
+	})
 	log.Infof("demoA used get: %s", config.Get("includeA", "demoA").String(""))
 
 	go func() {
@@ -43,7 +46,9 @@ func main() {
 			case <-time.After(2 * time.Second):
 				// try to change DemoB value in includeA.yml
 				// there will log the new value
-				log.Infof("demoB: %s", value.IncludeA.IncludeB.DemoB)
+				config.Read(&value, func() {
+					log.Infof("demoB: %s", value.IncludeA.IncludeB.DemoB)
+				})
 			}
 		}
 	}()
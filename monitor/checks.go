@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/debug/health"
+	pb "github.com/stack-labs/stack/debug/proto"
+	"github.com/stack-labs/stack/registry"
+)
+
+// noDebug returns true if the node answered without the debug handler,
+// so it's reachable but can't report its checks
+func noDebug(err error) bool {
+	e := err.Error()
+	return strings.Contains(e, "can't find service") || strings.Contains(e, "unknown service")
+}
+
+// RPCCheck calls the debug health endpoint of the node with the client
+func RPCCheck(c client.Client) CheckFunc {
+	return func(ctx context.Context, service *registry.Service, node *registry.Node) (*health.Report, error) {
+		debug := pb.NewDebugService(service.Name, c)
+
+		rsp, err := debug.Health(
+			ctx,
+			&pb.HealthRequest{Service: service.Name},
+			// call this specific node
+			client.WithAddress(node.Address),
+			// retry in the event of failure
+			client.WithRetries(3),
+		)
+		if err != nil && noDebug(err) {
+			return &health.Report{Status: health.StatusOK}, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		return health.Decode(rsp), nil
+	}
+}
+
+// HTTPCheck gets the readiness of the node from its health endpoint
+func HTTPCheck(c *http.Client) CheckFunc {
+	return func(ctx context.Context, service *registry.Service, node *registry.Node) (*health.Report, error) {
+		req, err := http.NewRequest("GET", "http://"+node.Address+health.DefaultPath+"/ready", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		rsp, err := c.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer rsp.Body.Close()
+
+		// served without the health endpoints
+		if rsp.StatusCode == http.StatusNotFound {
+			return &health.Report{Status: health.StatusOK}, nil
+		}
+
+		report := new(health.Report)
+		if err := json.NewDecoder(rsp.Body).Decode(report); err != nil {
+			return nil, fmt.Errorf("health endpoint returned %s", rsp.Status)
+		}
+
+		return report, nil
+	}
+}
@@ -2,16 +2,23 @@ package monitor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	gohttp "net/http"
 	"sync"
 	"time"
 
+	"github.com/stack-labs/stack/broker"
+	bhttp "github.com/stack-labs/stack/broker/http"
 	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/grpc"
 	"github.com/stack-labs/stack/client/mucp"
-	pb "github.com/stack-labs/stack/debug/proto"
+	"github.com/stack-labs/stack/debug/health"
+	"github.com/stack-labs/stack/registry"
 	"github.com/stack-labs/stack/registry/cache"
 	"github.com/stack-labs/stack/registry/mdns"
 	"github.com/stack-labs/stack/transport/http"
+	"github.com/stack-labs/stack/util/log"
 )
 
 type monitor struct {
@@ -24,6 +31,13 @@ type monitor struct {
 	sync.RWMutex
 	running  bool
 	services map[string]*Status
+	nodes    map[string]*nodeStatus
+}
+
+// nodeStatus is the status of a node by its id
+type nodeStatus struct {
+	code     StatusCode
+	failures int
 }
 
 func (m *monitor) Check(service string) error {
@@ -42,59 +56,60 @@ func (m *monitor) Check(service string) error {
 	return nil
 }
 
-// check provides binary running/failed status.
-// In the event Debug.Health cannot be called on a service we reap the node.
+// check the nodes of the service with the check of their protocol. The
+// service is running if any node is. A node failing its checks too many
+// times in a row is deregistered.
 func (m *monitor) check(service string) (*Status, error) {
 	services, err := m.registry.GetService(service)
 	if err != nil {
 		return nil, err
 	}
 
-	// create debug client
-	debug := pb.NewDebugService(service, m.client)
-
 	var status *Status
 	var gerr error
+	var checks []*health.Result
 
 	// iterate through multiple versions of a service
 	for _, service := range services {
 		for _, node := range service.Nodes {
-			// TODO: checks that are not just RPC based
-			// TODO: better matching of the protocol
-			// TODO: maybe everything has to be a stack service?
-			if node.Metadata["server"] != m.client.String() {
-				continue
+			protocol := node.Metadata["protocol"]
+			if len(protocol) == 0 {
+				protocol = node.Metadata["server"]
 			}
-			// check the transport matches
-			if node.Metadata["transport"] != m.client.Options().Transport.String() {
+
+			fn, ok := m.options.Checks[protocol]
+			if !ok {
 				continue
 			}
 
-			rsp, err := debug.Health(
-				context.Background(),
-				// empty health request
-				&pb.HealthRequest{},
-				// call this specific node
-				client.WithAddress(node.Address),
-				// retry in the event of failure
-				client.WithRetries(3),
-			)
+			ctx, cancel := context.WithTimeout(context.Background(), m.options.Timeout)
+			report, err := fn(ctx, service, node)
+			cancel()
+
+			if err == nil && !report.Ok() {
+				if err = report.Err(); len(report.Checks) == 0 {
+					err = errors.New(string(report.Status))
+				}
+			}
+
 			if err != nil {
 				// save the error
 				gerr = err
+				checks = nil
+				if report != nil {
+					checks = report.Checks
+				}
+				m.record(service, node, StatusFailed, err, checks)
 				continue
 			}
 
-			// expecting ok response status
-			if rsp.Status != "ok" {
-				gerr = errors.New(rsp.Status)
-				continue
-			}
+			m.record(service, node, StatusRunning, nil, report.Checks)
 
 			// no error set status
 			status = &Status{
-				Code: StatusRunning,
-				Info: "running",
+				Code:   StatusRunning,
+				Info:   "running",
+				Checks: report.Checks,
 			}
 		}
 	}
@@ -107,9 +122,10 @@ func (m *monitor) check(service string) (*Status, error) {
 	// if gerr is not nil return it
 	if gerr != nil {
 		return &Status{
-			Code:  StatusFailed,
-			Info:  "not running",
-			Error: gerr.Error(),
+			Code:   StatusFailed,
+			Info:   "not running",
+			Error:  gerr.Error(),
+			Checks: checks,
 		}, nil
 	}
 
@@ -120,6 +136,93 @@ func (m *monitor) check(service string) (*Status, error) {
 	}, nil
 }
 
+// record the status of the node, publishing it if changed and
+// deregistering the node once it failed too many times
+func (m *monitor) record(service *registry.Service, node *registry.Node, code StatusCode, err error, checks []*health.Result) {
+	m.Lock()
+	n, ok := m.nodes[node.Id]
+	if !ok {
+		n = &nodeStatus{code: StatusUnknown}
+		m.nodes[node.Id] = n
+	}
+
+	changed := n.code != code
+	n.code = code
+
+	if code == StatusFailed {
+		n.failures++
+	} else {
+		n.failures = 0
+	}
+
+	reap := code == StatusFailed && m.options.Failures > 0 && n.failures >= m.options.Failures
+	if reap {
+		delete(m.nodes, node.Id)
+	}
+	m.Unlock()
+
+	if !changed && !reap {
+		return
+	}
+
+	event := &Event{
+		Service:   service.Name,
+		Version:   service.Version,
+		Node:      node.Id,
+		Address:   node.Address,
+		Status:    code,
+		Checks:    checks,
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	if reap {
+		log.Logf("[monitor] deregistering node %s of %s after %d failed checks: %v", node.Id, service.Name, m.options.Failures, err)
+		if err := m.deregister(service, node); err != nil {
+			log.Logf("[monitor] error deregistering node %s: %v", node.Id, err)
+		} else {
+			event.Reaped = true
+		}
+	} else {
+		log.Logf("[monitor] node %s of %s is %s", node.Id, service.Name, code)
+	}
+
+	m.publish(event)
+}
+
+// deregister a single node of the service
+func (m *monitor) deregister(service *registry.Service, node *registry.Node) error {
+	return m.options.Registry.Deregister(&registry.Service{
+		Name:     service.Name,
+		Version:  service.Version,
+		Metadata: service.Metadata,
+		Nodes:    []*registry.Node{node},
+	})
+}
+
+func (m *monitor) publish(e *Event) {
+	if m.options.Broker == nil {
+		return
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.Logf("[monitor] error encoding event: %v", err)
+		return
+	}
+
+	if err := m.options.Broker.Publish(m.options.Topic, &broker.Message{
+		Header: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: b,
+	}); err != nil {
+		log.Logf("[monitor] error publishing event: %v", err)
+	}
+}
+
 func (m *monitor) reap() {
 	services, err := m.registry.ListServices()
 	if err != nil {
@@ -146,7 +249,7 @@ func (m *monitor) reap() {
 
 func (m *monitor) run() {
 	// check the status every tick
-	t := time.NewTicker(time.Minute)
+	t := time.NewTicker(m.options.Interval)
 	defer t.Stop()
 
 	// reap dead services
@@ -229,12 +332,34 @@ func (m *monitor) Reap(service string) error {
 	if err != nil {
 		return nil
 	}
+
 	m.Lock()
-	defer m.Unlock()
 	delete(m.services, service)
 	for _, service := range services {
-		m.registry.Deregister(service)
+		for _, node := range service.Nodes {
+			delete(m.nodes, node.Id)
+		}
+	}
+	m.Unlock()
+
+	for _, service := range services {
+		if err := m.options.Registry.Deregister(service); err != nil {
+			return err
+		}
+
+		for _, node := range service.Nodes {
+			m.publish(&Event{
+				Service:   service.Name,
+				Version:   service.Version,
+				Node:      node.Id,
+				Address:   node.Address,
+				Status:    StatusUnknown,
+				Reaped:    true,
+				Timestamp: time.Now(),
+			})
+		}
 	}
+
 	return nil
 }
 
@@ -248,11 +373,11 @@ func (m *monitor) Status(service string) (Status, error) {
 }
 
 func (m *monitor) Watch(service string) error {
-	m.Lock()
-	defer m.Unlock()
-
 	// check if we're watching
-	if _, ok := m.services[service]; ok {
+	m.RLock()
+	_, ok := m.services[service]
+	m.RUnlock()
+	if ok {
 		return nil
 	}
 
@@ -263,7 +388,9 @@ func (m *monitor) Watch(service string) error {
 	}
 
 	// set the status
+	m.Lock()
 	m.services[service] = status
+	m.Unlock()
 	return nil
 }
 
@@ -275,6 +402,12 @@ func (m *monitor) Run() error {
 		return nil
 	}
 
+	if m.options.Broker != nil {
+		if err := m.options.Broker.Connect(); err != nil {
+			return err
+		}
+	}
+
 	// reset the exit channel
 	m.exit = make(chan bool)
 	// setup a new cache
@@ -305,6 +438,9 @@ func (m *monitor) Stop() error {
 		for s := range m.services {
 			delete(m.services, s)
 		}
+		for n := range m.nodes {
+			delete(m.nodes, n)
+		}
 		m.registry.Stop()
 		m.running = false
 		return nil
@@ -313,19 +449,55 @@ func (m *monitor) Stop() error {
 
 func newMonitor(opts ...Option) Monitor {
 	options := Options{
-		Client:   mucp.NewClient(client.Transport(http.NewTransport())),
-		Registry: mdns.NewRegistry(),
+		Topic:    DefaultTopic,
+		Interval: time.Minute,
+		Timeout:  time.Second * 10,
 	}
 
 	for _, o := range opts {
 		o(&options)
 	}
 
+	if options.Registry == nil {
+		options.Registry = mdns.NewRegistry()
+	}
+	if options.Client == nil {
+		options.Client = mucp.NewClient(
+			client.Transport(http.NewTransport()),
+			client.Registry(options.Registry),
+		)
+	}
+	if options.Broker == nil {
+		options.Broker = options.Client.Options().Broker
+	}
+
+	// the checks don't publish but the grpc client requires a broker
+	gb := options.Broker
+	if gb == nil {
+		gb = bhttp.NewBroker()
+	}
+
+	// check the nodes with a client of their protocol
+	checks := map[string]CheckFunc{
+		"mucp": RPCCheck(options.Client),
+		"grpc": RPCCheck(grpc.NewClient(client.Registry(options.Registry), client.Broker(gb))),
+		"http": HTTPCheck(new(gohttp.Client)),
+	}
+	if options.Client.String() != "mucp" {
+		checks["mucp"] = RPCCheck(mucp.NewClient(client.Registry(options.Registry)))
+		checks[options.Client.String()] = RPCCheck(options.Client)
+	}
+	for protocol, fn := range options.Checks {
+		checks[protocol] = fn
+	}
+	options.Checks = checks
+
 	return &monitor{
 		options:  options,
 		exit:     make(chan bool),
 		client:   options.Client,
 		registry: cache.New(options.Registry),
 		services: make(map[string]*Status),
+		nodes:    make(map[string]*nodeStatus),
 	}
 }
@@ -1,7 +1,19 @@
 package monitor
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/stack-labs/stack/broker"
+	bmemory "github.com/stack-labs/stack/broker/memory"
+	"github.com/stack-labs/stack/debug/health"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/registry/memory"
 )
 
 func TestMonitor(t *testing.T) {
@@ -35,3 +47,151 @@ func TestMonitor(t *testing.T) {
 		t.Fatalf("failed to stop monitor: %v", err)
 	}
 }
+
+func TestMonitorReap(t *testing.T) {
+	r := memory.NewRegistry()
+	b := bmemory.NewBroker()
+
+	var mtx sync.Mutex
+	var events []*Event
+
+	if err := b.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Subscribe(DefaultTopic, func(p broker.Event) error {
+		e := new(Event)
+		if err := json.Unmarshal(p.Message().Body, e); err != nil {
+			return err
+		}
+		mtx.Lock()
+		events = append(events, e)
+		mtx.Unlock()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var failing error
+	h := health.NewHealth()
+	h.Register("store", func(context.Context) error { return failing })
+
+	srv := httptest.NewServer(health.Handler(h))
+	defer srv.Close()
+
+	node := &registry.Node{
+		Id:       "foo-1",
+		Address:  strings.TrimPrefix(srv.URL, "http://"),
+		Metadata: map[string]string{"protocol": "http"},
+	}
+	if err := r.Register(&registry.Service{Name: "foo", Version: "latest", Nodes: []*registry.Node{node}}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMonitor(Registry(r), Broker(b), Failures(2))
+
+	if err := m.Check("foo"); err != nil {
+		t.Fatal(err)
+	}
+	status, err := m.Status("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Code != StatusRunning || len(status.Checks) != 1 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	failing = errors.New("unreachable")
+
+	if err := m.Check("foo"); err == nil {
+		t.Fatal("expected the service failing")
+	}
+	status, _ = m.Status("foo")
+	if status.Code != StatusFailed || status.Error != "store: unreachable" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	// still registered after one failure
+	if services, err := r.GetService("foo"); err != nil || len(services[0].Nodes) != 1 {
+		t.Fatalf("expected the node registered got %v: %v", services, err)
+	}
+
+	m.Check("foo")
+
+	if services, err := r.GetService("foo"); err != registry.ErrNotFound && len(services) > 0 && len(services[0].Nodes) > 0 {
+		t.Fatalf("expected the node deregistered got %v", services[0].Nodes)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events got %d", len(events))
+	}
+	for i, code := range []StatusCode{StatusRunning, StatusFailed, StatusFailed} {
+		if events[i].Status != code || events[i].Node != "foo-1" {
+			t.Fatalf("unexpected event %+v", events[i])
+		}
+	}
+	if events[1].Reaped || !events[2].Reaped {
+		t.Fatal("expected only the last event reaping the node")
+	}
+	if len(events[2].Checks) != 1 || events[2].Checks[0].Error != "unreachable" {
+		t.Fatalf("unexpected checks %+v", events[2].Checks)
+	}
+}
+
+func TestMonitorNoReap(t *testing.T) {
+	r := memory.NewRegistry()
+
+	h := health.NewHealth()
+	h.Register("store", func(context.Context) error { return errors.New("unreachable") })
+
+	srv := httptest.NewServer(health.Handler(h))
+	defer srv.Close()
+
+	node := &registry.Node{
+		Id:       "foo-1",
+		Address:  strings.TrimPrefix(srv.URL, "http://"),
+		Metadata: map[string]string{"protocol": "http"},
+	}
+	if err := r.Register(&registry.Service{Name: "foo", Version: "latest", Nodes: []*registry.Node{node}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// failed nodes are only reported by default
+	m := NewMonitor(Registry(r), Broker(bmemory.NewBroker()))
+	for i := 0; i < 5; i++ {
+		if err := m.Check("foo"); err == nil {
+			t.Fatal("expected the service failing")
+		}
+	}
+
+	if services, err := r.GetService("foo"); err != nil || len(services[0].Nodes) != 1 {
+		t.Fatalf("expected the node registered got %v: %v", services, err)
+	}
+}
+
+func TestMonitorCheck(t *testing.T) {
+	r := memory.NewRegistry()
+
+	r.Register(&registry.Service{Name: "bar", Nodes: []*registry.Node{{
+		Id:       "bar-1",
+		Address:  "localhost:1",
+		Metadata: map[string]string{"protocol": "custom"},
+	}}})
+
+	m := NewMonitor(
+		Registry(r),
+		Broker(bmemory.NewBroker()),
+		Check("custom", func(ctx context.Context, service *registry.Service, node *registry.Node) (*health.Report, error) {
+			return &health.Report{Status: health.StatusOK}, nil
+		}),
+	)
+
+	if err := m.Watch("bar"); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := m.Status("bar"); status.Code != StatusRunning {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
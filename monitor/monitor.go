@@ -2,7 +2,12 @@
 package monitor
 
 import (
+	"context"
 	"errors"
+	"time"
+
+	"github.com/stack-labs/stack/debug/health"
+	"github.com/stack-labs/stack/registry"
 )
 
 const (
@@ -13,6 +18,10 @@ const (
 
 type StatusCode int
 
+// CheckFunc checks a node of a service. An error or a failing report
+// fails the node.
+type CheckFunc func(ctx context.Context, service *registry.Service, node *registry.Node) (*health.Report, error)
+
 // Monitor monitors a service and reaps dead instances
 type Monitor interface {
 	// Reap a service and stop monitoring
@@ -33,12 +42,43 @@ type Status struct {
 	Code  StatusCode
 	Info  string
 	Error string
+	// Checks reported by the service
+	Checks []*health.Result
+}
+
+// Event is published when the status of a node changes
+type Event struct {
+	Service string     `json:"service"`
+	Version string     `json:"version"`
+	Node    string     `json:"node"`
+	Address string     `json:"address"`
+	Status  StatusCode `json:"status"`
+	Error   string     `json:"error,omitempty"`
+	// Checks reported by the node
+	Checks []*health.Result `json:"checks,omitempty"`
+	// Reaped is set once the node is deregistered
+	Reaped    bool      `json:"reaped"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 var (
+	// DefaultTopic the status changes are published to
+	DefaultTopic = "stack.monitor.status"
+
 	ErrNotWatching = errors.New("not watching")
 )
 
+func (s StatusCode) String() string {
+	switch s {
+	case StatusRunning:
+		return "running"
+	case StatusFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
 // NewMonitor returns a new monitor
 func NewMonitor(opts ...Option) Monitor {
 	return newMonitor(opts...)
@@ -1,6 +1,9 @@
 package monitor
 
 import (
+	"time"
+
+	"github.com/stack-labs/stack/broker"
 	"github.com/stack-labs/stack/client"
 	"github.com/stack-labs/stack/registry"
 )
@@ -8,6 +11,19 @@ import (
 type Options struct {
 	Client   client.Client
 	Registry registry.Registry
+	// Broker the status changes are published to, defaults to the broker of the client
+	Broker broker.Broker
+	// Topic the status changes are published to
+	Topic string
+	// Interval the services are checked at
+	Interval time.Duration
+	// Timeout of a node check
+	Timeout time.Duration
+	// Failures is the number of consecutive failed checks before a
+	// node is deregistered, zero, the default, never deregisters
+	Failures int
+	// Checks of the nodes by protocol
+	Checks map[string]CheckFunc
 }
 
 type Option func(*Options)
@@ -23,3 +39,48 @@ func Registry(r registry.Registry) Option {
 		o.Registry = r
 	}
 }
+
+// Broker sets the broker the status changes are published to
+func Broker(b broker.Broker) Option {
+	return func(o *Options) {
+		o.Broker = b
+	}
+}
+
+// Topic sets the topic the status changes are published to
+func Topic(t string) Option {
+	return func(o *Options) {
+		o.Topic = t
+	}
+}
+
+// Interval sets how often the services are checked
+func Interval(t time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = t
+	}
+}
+
+// Timeout sets the timeout of a node check
+func Timeout(t time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = t
+	}
+}
+
+// Failures sets the consecutive failed checks before a node is deregistered
+func Failures(n int) Option {
+	return func(o *Options) {
+		o.Failures = n
+	}
+}
+
+// Check sets the check of the nodes serving the protocol
+func Check(protocol string, fn CheckFunc) Option {
+	return func(o *Options) {
+		if o.Checks == nil {
+			o.Checks = make(map[string]CheckFunc)
+		}
+		o.Checks[protocol] = fn
+	}
+}
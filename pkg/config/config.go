@@ -31,6 +31,17 @@ type Config interface {
 	Sync() error
 	// Watch a value for changes
 	Watch(path ...string) (Watcher, error)
+	// History of the loaded config, newest first
+	History() []*loader.Record
+	// At returns the config live at the time
+	At(t time.Time) (*loader.Record, error)
+	// Diff the keys of two versions
+	Diff(from, to string) ([]*loader.Change, error)
+	// Rollback to a previous version until a source changes
+	Rollback(version string) error
+	// Events of the config changes, a watcher falling
+	// behind skips its oldest records to the latest
+	Events() (loader.EventWatcher, error)
 }
 
 type config struct {
@@ -54,7 +65,10 @@ func NewConfig(opts ...Option) (Config, error) {
 func newConfig(opts ...Option) (Config, error) {
 	options := NewOptions(opts...)
 
-	l := loader.NewLoader(loader.WithWatch(options.Watch))
+	l := loader.NewLoader(
+		loader.WithWatch(options.Watch),
+		loader.WithHistory(options.History),
+	)
 	if err := l.Load(); err != nil {
 		return nil, err
 	}
@@ -125,7 +139,11 @@ func (c *config) run() {
 				return err
 			}
 
-			c.writeStorage(snap)
+			// the watched snapshot has the resolved values, the
+			// storage keeps the merged sources with their references
+			if raw, err := c.loader.Snapshot(); err == nil {
+				c.writeStorage(raw)
+			}
 
 			c.Lock()
 
@@ -323,6 +341,48 @@ func (c *config) Watch(path ...string) (Watcher, error) {
 	}, nil
 }
 
+func (c *config) History() []*loader.Record {
+	return c.loader.History()
+}
+
+func (c *config) At(t time.Time) (*loader.Record, error) {
+	return c.loader.At(t)
+}
+
+func (c *config) Diff(from, to string) ([]*loader.Change, error) {
+	return c.loader.Diff(from, to)
+}
+
+// Rollback makes the config of the version live, the next change of a source replaces it
+func (c *config) Rollback(version string) error {
+	if err := c.loader.Rollback(version); err != nil {
+		return err
+	}
+
+	snap, err := c.loader.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	c.writeStorage(snap)
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.snap = snap
+	values, err := c.loader.Values(snap.ChangeSet)
+	if err != nil {
+		return err
+	}
+	c.values = values
+
+	return nil
+}
+
+func (c *config) Events() (loader.EventWatcher, error) {
+	return c.loader.Events()
+}
+
 func (c *config) String() string {
 	return "config"
 }
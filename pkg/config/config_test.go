@@ -2,14 +2,19 @@ package config
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/stack-labs/stack/pkg/config/secret"
+	senv "github.com/stack-labs/stack/pkg/config/secret/env"
+	"github.com/stack-labs/stack/pkg/config/source"
 	"github.com/stack-labs/stack/pkg/config/source/env"
 	"github.com/stack-labs/stack/pkg/config/source/file"
+	"github.com/stack-labs/stack/pkg/config/source/memory"
 )
 
 func createFile(t *testing.T, content string, format string) *os.File {
@@ -288,3 +293,65 @@ func TestSomeConfigLoad(t *testing.T) {
 		}
 	}
 }
+
+func TestConfigSecrets(t *testing.T) {
+	os.Setenv("STACK_SECRET_DB_PASSWORD", "s3cret")
+	defer os.Unsetenv("STACK_SECRET_DB_PASSWORD")
+
+	p := senv.NewProvider()
+	secret.Register(p)
+	defer secret.Deregister(p)
+
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := memory.NewSource(memory.WithJSON([]byte(`{"db": {"user": "stack"}}`)))
+
+	conf, err := NewConfig(Storage(true), StorageDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conf.Close()
+
+	if err := conf.Load(src); err != nil {
+		t.Fatal(err)
+	}
+
+	// a change of the watched source is written to the storage
+	w, err := conf.Watch("db", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	data := []byte(`{"db": {"user": "stack", "password": "${secret:db.password}"}}`)
+	for i := 0; i < 20; i++ {
+		src.(interface{ Update(*source.ChangeSet) }).Update(&source.ChangeSet{Data: data, Format: "json"})
+		if conf.Get("db", "password").String("") == "s3cret" {
+			break
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+	if v := conf.Get("db", "password").String(""); v != "s3cret" {
+		t.Fatalf("expected the secret resolved got %s", v)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "stack_config.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "s3cret") || !strings.Contains(string(b), "${secret:db.password}") {
+		t.Fatalf("expected the secret reference stored got %s", b)
+	}
+
+	for _, rec := range conf.History() {
+		for _, c := range rec.Changes {
+			if strings.Contains(string(c.To), "s3cret") {
+				t.Fatalf("expected the secret masked got %s", c.To)
+			}
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stack-labs/stack/pkg/config/reader"
+	"github.com/stack-labs/stack/pkg/config/secret"
+	"github.com/stack-labs/stack/pkg/config/source"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultHistory is the number of snapshots kept by the loader
+	DefaultHistory = 20
+
+	// ErrVersionNotFound is returned for versions not kept in the history
+	ErrVersionNotFound = errors.New("version not found")
+	// ErrEventsStopped is returned by a stopped event watcher
+	ErrEventsStopped = errors.New("events stopped")
+)
+
+const (
+	// SourceSync is the source of the changes made by Sync
+	SourceSync = "sync"
+	// SourceRollback is the source of the changes made by Rollback
+	SourceRollback = "rollback"
+)
+
+// Record is a snapshot kept in the history of the loader
+type Record struct {
+	// The merged snapshot
+	Snapshot *Snapshot
+	// The change sets merged into the snapshot, without their data
+	Sources []*source.ChangeSet
+	// Source of the change, the source name, "sync" or "rollback"
+	Source string
+	// Rollback is the version rolled back to
+	Rollback string
+	// Changes of the keys since the previous version
+	Changes []*Change
+	// Timestamp the snapshot was loaded at
+	Timestamp time.Time
+}
+
+// Change of a key, From is nil for added keys and To for removed ones
+type Change struct {
+	// Key path separated by dots
+	Key string
+	// Source the value was loaded from
+	Source string
+	// JSON encoded values
+	From []byte
+	To   []byte
+}
+
+// EventWatcher returns the records as the config changes
+type EventWatcher interface {
+	Next() (*Record, error)
+	Stop() error
+}
+
+type events struct {
+	exit    chan bool
+	records chan *Record
+}
+
+func (e *events) Next() (*Record, error) {
+	select {
+	case <-e.exit:
+		return nil, ErrEventsStopped
+	case r := <-e.records:
+		return r, nil
+	}
+}
+
+func (e *events) Stop() error {
+	select {
+	case <-e.exit:
+	default:
+		close(e.exit)
+	}
+	return nil
+}
+
+func (r *Record) clone() *Record {
+	c := *r
+	c.Snapshot = r.Snapshot.Clone()
+	return &c
+}
+
+// sums copies the sets without their data
+func sums(sets []*source.ChangeSet) []*source.ChangeSet {
+	//nolint:prealloc
+	var s []*source.ChangeSet
+	for _, set := range sets {
+		if set == nil {
+			continue
+		}
+		cs := &source.ChangeSet{
+			Checksum:  set.Checksum,
+			Format:    set.Format,
+			Source:    set.Source,
+			Timestamp: set.Timestamp,
+		}
+		if len(cs.Checksum) == 0 {
+			cs.Checksum = set.Sum()
+		}
+		s = append(s, cs)
+	}
+	return s
+}
+
+// flatten the values into JSON encoded values by key
+func flatten(v reader.Values) map[string][]byte {
+	keys := make(map[string][]byte)
+	if v == nil {
+		return keys
+	}
+
+	walk(nil, v.Map(), func(key string, val interface{}) {
+		b, err := json.Marshal(val)
+		if err != nil {
+			return
+		}
+		keys[key] = b
+	})
+
+	return keys
+}
+
+// walk calls fn with the leaf values of m by key path separated by dots
+func walk(prefix []string, m map[string]interface{}, fn func(key string, val interface{})) {
+	for k, val := range m {
+		path := append(append([]string{}, prefix...), k)
+		if sub, ok := val.(map[string]interface{}); ok && len(sub) > 0 {
+			walk(path, sub, fn)
+			continue
+		}
+		fn(strings.Join(path, "."), val)
+	}
+}
+
+// secrets returns the keys of the values of the merged set which reference
+// secrets or are encrypted. The set holds the values before they're resolved
+func secrets(cs *source.ChangeSet) map[string]bool {
+	keys := make(map[string]bool)
+	if cs == nil {
+		return keys
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(cs.Data, &m); err != nil {
+		return keys
+	}
+
+	walk(nil, m, func(key string, val interface{}) {
+		if s, ok := val.(string); ok && reader.IsSecret(s) {
+			keys[key] = true
+		}
+	})
+
+	return keys
+}
+
+// mask the value of a key holding a secret
+func mask(b []byte, key string, secrets map[string]bool) []byte {
+	if b == nil || !secrets[key] {
+		return b
+	}
+	m, _ := json.Marshal(secret.DefaultMask)
+	return m
+}
+
+// diff the flattened values, sorted by key. The values of the keys holding
+// secrets in the from or to version are masked
+func diff(from, to map[string][]byte, fromSecrets, toSecrets map[string]bool) []*Change {
+	var changes []*Change
+
+	for k, v := range to {
+		if old, ok := from[k]; !ok || !bytes.Equal(old, v) {
+			changes = append(changes, &Change{Key: k, From: mask(old, k, fromSecrets), To: mask(v, k, toSecrets)})
+		}
+	}
+	for k, v := range from {
+		if _, ok := to[k]; !ok {
+			changes = append(changes, &Change{Key: k, From: mask(v, k, fromSecrets)})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// attribute the changed keys to the last set holding them, called with the lock held.
+// The keys of a set are read once, sets which didn't change are not read
+// again so their secrets aren't resolved on every reload
+func (m *loader) attribute(changes []*Change, src string) {
+	cache := make(map[string]map[string]bool, len(m.sets))
+	values := make([]map[string]bool, len(m.sets))
+	for i, set := range m.sets {
+		if set == nil {
+			continue
+		}
+		sum := set.Checksum
+		if len(sum) == 0 {
+			sum = set.Sum()
+		}
+		keys, ok := m.keys[sum]
+		if !ok {
+			keys = map[string]bool{}
+			if cs, err := m.opts.Reader.Merge(set); err == nil {
+				if v, err := m.opts.Reader.Values(cs); err == nil {
+					for k := range flatten(v) {
+						keys[k] = true
+					}
+				}
+			}
+		}
+		cache[sum] = keys
+		values[i] = keys
+	}
+	// only the current sets are kept
+	m.keys = cache
+
+	for _, c := range changes {
+		c.Source = src
+		if c.To == nil {
+			continue
+		}
+		for i := len(values) - 1; i >= 0; i-- {
+			if values[i][c.Key] {
+				c.Source = m.sets[i].Source
+				break
+			}
+		}
+	}
+}
+
+// record the snapshot in the history and notify the event watchers, called with the lock held
+func (m *loader) record(r *Record) {
+	m.history = append(m.history, r)
+	if size := m.opts.History; size > 0 && len(m.history) > size {
+		m.history = m.history[len(m.history)-size:]
+	}
+
+	// a watcher falling behind drops its oldest record, the latest is always delivered
+	for e := m.events.Front(); e != nil; e = e.Next() {
+		w := e.Value.(*events)
+		select {
+		case w.records <- r.clone():
+			continue
+		default:
+		}
+
+		select {
+		case old := <-w.records:
+			log.Warnf("config event watcher is full, dropping version %s", old.Snapshot.Version)
+		default:
+		}
+		w.records <- r.clone()
+	}
+}
+
+// find the record of the version, called with the lock held
+func (m *loader) find(version string) (int, *Record) {
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if m.history[i].Snapshot.Version == version {
+			return i, m.history[i]
+		}
+	}
+	return -1, nil
+}
+
+// History returns the kept records, newest first
+func (m *loader) History() []*Record {
+	m.RLock()
+	defer m.RUnlock()
+
+	records := make([]*Record, 0, len(m.history))
+	for i := len(m.history) - 1; i >= 0; i-- {
+		records = append(records, m.history[i].clone())
+	}
+	return records
+}
+
+// At returns the record live at the time
+func (m *loader) At(t time.Time) (*Record, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if !m.history[i].Timestamp.After(t) {
+			return m.history[i].clone(), nil
+		}
+	}
+	return nil, ErrVersionNotFound
+}
+
+// Diff returns the changes of the keys between two versions
+func (m *loader) Diff(from, to string) ([]*Change, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	i, a := m.find(from)
+	j, b := m.find(to)
+	if a == nil || b == nil {
+		return nil, ErrVersionNotFound
+	}
+
+	va, err := m.opts.Reader.Values(a.Snapshot.ChangeSet)
+	if err != nil {
+		return nil, err
+	}
+	vb, err := m.opts.Reader.Values(b.Snapshot.ChangeSet)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diff(flatten(va), flatten(vb), secrets(a.Snapshot.ChangeSet), secrets(b.Snapshot.ChangeSet))
+
+	// the source is the last change of the key up to the target version
+	lo, hi := i, j
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	for _, c := range changes {
+		for k := hi; k > lo && len(c.Source) == 0; k-- {
+			for _, rc := range m.history[k].Changes {
+				if rc.Key == c.Key {
+					c.Source = rc.Source
+					break
+				}
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// Rollback makes the config of the version live again, until a source changes
+func (m *loader) Rollback(version string) error {
+	m.Lock()
+
+	_, r := m.find(version)
+	if r == nil {
+		m.Unlock()
+		return ErrVersionNotFound
+	}
+
+	snap := r.Snapshot.Clone()
+	values, err := m.opts.Reader.Values(snap.ChangeSet)
+	if err != nil {
+		m.Unlock()
+		return err
+	}
+
+	changes := diff(flatten(m.values), flatten(values), secrets(m.snap.ChangeSet), secrets(snap.ChangeSet))
+	for _, c := range changes {
+		c.Source = SourceRollback
+	}
+
+	m.version++
+	snap.Version = strconv.FormatUint(m.version, 10)
+
+	m.values = values
+	m.snap = snap
+	m.record(&Record{
+		Snapshot:  snap,
+		Sources:   r.Sources,
+		Source:    SourceRollback,
+		Rollback:  version,
+		Changes:   changes,
+		Timestamp: time.Now(),
+	})
+
+	m.Unlock()
+
+	// update watchers
+	m.update()
+
+	return nil
+}
+
+// Events returns a watcher of the records as the config changes
+func (m *loader) Events() (EventWatcher, error) {
+	w := &events{
+		exit:    make(chan bool),
+		records: make(chan *Record, 64),
+	}
+
+	m.Lock()
+	e := m.events.PushBack(w)
+	m.Unlock()
+
+	go func() {
+		<-w.exit
+		m.Lock()
+		m.events.Remove(e)
+		m.Unlock()
+	}()
+
+	return w, nil
+}
@@ -0,0 +1,243 @@
+package loader
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/pkg/config/secret"
+	"github.com/stack-labs/stack/pkg/config/source"
+)
+
+type countProvider struct {
+	gets int
+}
+
+func (p *countProvider) Get(name string) (string, error) {
+	p.gets++
+	return "s3cret", nil
+}
+
+func (p *countProvider) Decrypt(ciphertext string) (string, error) {
+	return "", secret.ErrNotSupported
+}
+
+func (p *countProvider) String() string {
+	return "count"
+}
+
+type testSource struct {
+	name string
+	data string
+}
+
+func (s *testSource) Read() (*source.ChangeSet, error) {
+	cs := &source.ChangeSet{
+		Data:      []byte(s.data),
+		Format:    "json",
+		Source:    s.name,
+		Timestamp: time.Now(),
+	}
+	cs.Checksum = cs.Sum()
+	return cs, nil
+}
+
+func (s *testSource) Watch() (source.Watcher, error) {
+	return nil, source.ErrWatcherStopped
+}
+
+func (s *testSource) String() string {
+	return s.name
+}
+
+func TestHistory(t *testing.T) {
+	l := NewLoader(WithWatch(false), WithHistory(3))
+	file := &testSource{name: "file", data: `{"db":{"host":"a","port":1},"debug":false}`}
+	env := &testSource{name: "env", data: `{"db":{"port":2}}`}
+
+	if err := l.Load(file, env); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := l.Events()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer events.Stop()
+
+	history := l.History()
+	if len(history) != 1 || history[0].Source != "file,env" || len(history[0].Sources) != 2 {
+		t.Fatalf("unexpected history %+v", history)
+	}
+	first := history[0].Snapshot.Version
+	loaded := time.Now()
+
+	// a sync without changes keeps the version
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if h := l.History(); len(h) != 1 {
+		t.Fatalf("expected 1 version got %d", len(h))
+	}
+
+	file.data = `{"db":{"host":"b","port":1}}`
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := events.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Source != SourceSync || len(r.Changes) != 2 {
+		t.Fatalf("unexpected event %+v", r)
+	}
+	if c := r.Changes[0]; c.Key != "db.host" || c.Source != "file" || string(c.From) != `"a"` || string(c.To) != `"b"` {
+		t.Fatalf("unexpected change %+v", c)
+	}
+	if c := r.Changes[1]; c.Key != "debug" || c.To != nil {
+		t.Fatalf("expected debug removed got %+v", c)
+	}
+
+	changes, err := l.Diff(first, r.Snapshot.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Source != "file" {
+		t.Fatalf("unexpected diff %+v", changes)
+	}
+
+	if rec, err := l.At(loaded); err != nil || rec.Snapshot.Version != first {
+		t.Fatalf("expected version %s live got %+v %v", first, rec, err)
+	}
+	if _, err := l.At(loaded.Add(-time.Hour)); err != ErrVersionNotFound {
+		t.Fatalf("expected not found got %v", err)
+	}
+
+	if err := l.Rollback(first); err != nil {
+		t.Fatal(err)
+	}
+	r, _ = events.Next()
+	if r.Source != SourceRollback || r.Rollback != first {
+		t.Fatalf("unexpected event %+v", r)
+	}
+	snap, _ := l.Snapshot()
+	if v, _ := l.(*loader).Get("db", "host"); v.String("") != "a" || snap.Version != r.Snapshot.Version {
+		t.Fatalf("expected the first version live got %s", snap.ChangeSet.Data)
+	}
+
+	// the history is bounded
+	env.data = `{"db":{"port":3}}`
+	l.Sync()
+	if h := l.History(); len(h) != 3 || h[2].Snapshot.Version == first {
+		t.Fatalf("expected the first version dropped got %d versions", len(h))
+	}
+	if err := l.Rollback(first); err != ErrVersionNotFound {
+		t.Fatalf("expected not found got %v", err)
+	}
+}
+
+func TestEventsFull(t *testing.T) {
+	l := NewLoader(WithWatch(false))
+	file := &testSource{name: "file", data: `{"n":0}`}
+
+	if err := l.Load(file); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := l.Events()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// more changes than the watcher buffers
+	for i := 1; i <= 100; i++ {
+		file.data = fmt.Sprintf(`{"n":%d}`, i)
+		if err := l.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap, err := l.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last *Record
+	for {
+		select {
+		case r := <-w.(*events).records:
+			last = r
+			continue
+		default:
+		}
+		break
+	}
+
+	if last == nil || last.Snapshot.Version != snap.Version {
+		t.Fatalf("expected the latest version %s delivered got %+v", snap.Version, last)
+	}
+}
+
+func TestAttributeCache(t *testing.T) {
+	p := &countProvider{}
+	secret.Register(p)
+	defer secret.Deregister(p)
+
+	l := NewLoader(WithWatch(false))
+	file := &testSource{name: "file", data: `{"db":{"password":"${secret:db.password}"}}`}
+	env := &testSource{name: "env", data: `{"db":{"port":2}}`}
+
+	if err := l.Load(file, env); err != nil {
+		t.Fatal(err)
+	}
+	gets := p.gets
+
+	// the unchanged file set isn't read again to attribute the changes
+	env.data = `{"db":{"port":3}}`
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if n := p.gets - gets; n != 1 {
+		t.Fatalf("expected the secret resolved once for the merged snapshot got %d", n)
+	}
+
+	// the history is newest first
+	if c := l.History()[0].Changes; len(c) != 1 || c[0].Key != "db.port" || c[0].Source != "env" {
+		t.Fatalf("unexpected changes %+v", c)
+	}
+}
+
+func TestMask(t *testing.T) {
+	p := &countProvider{}
+	secret.Register(p)
+	defer secret.Deregister(p)
+
+	l := NewLoader(WithWatch(false))
+	file := &testSource{name: "file", data: `{"db":{"user":"a"}}`}
+	if err := l.Load(file); err != nil {
+		t.Fatal(err)
+	}
+	first := l.History()[0].Snapshot.Version
+
+	// only the keys referencing secrets are masked, not the values equal to a secret
+	file.data = `{"db":{"user":"s3cret","password":"${secret:db.password}"}}`
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	masked := `"` + secret.DefaultMask + `"`
+
+	r := l.History()[0]
+	if c := r.Changes; len(c) != 2 || string(c[0].To) != masked || string(c[1].To) != `"s3cret"` {
+		t.Fatalf("unexpected changes %+v", c)
+	}
+
+	changes, err := l.Diff(r.Snapshot.Version, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Key != "db.password" || string(changes[0].From) != masked {
+		t.Fatalf("unexpected diff %+v", changes)
+	}
+}
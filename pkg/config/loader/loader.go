@@ -5,6 +5,7 @@ import (
 	"container/list"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,6 +27,17 @@ type Loader interface {
 	Sync() error
 	// Watch for changes
 	Watch(...string) (Watcher, error)
+	// History of the snapshots, newest first
+	History() []*Record
+	// At returns the snapshot live at the time
+	At(time.Time) (*Record, error)
+	// Diff the keys of two versions
+	Diff(from, to string) ([]*Change, error)
+	// Rollback to a previous version
+	Rollback(version string) error
+	// Events of the config changes, a watcher falling
+	// behind skips its oldest records to the latest
+	Events() (EventWatcher, error)
 
 	Values(*source.ChangeSet) (reader.Values, error)
 	Reader() reader.Reader
@@ -44,8 +56,15 @@ type loader struct {
 	sets []*source.ChangeSet
 	// all the sources
 	sources []source.Source
+	// the keys of the sets by checksum, to attribute the changes
+	keys map[string]map[string]bool
+	// the kept snapshots, oldest first
+	history []*Record
+	// the last version
+	version uint64
 
 	watchers *list.List
+	events   *list.List
 }
 
 func NewLoader(opts ...Option) Loader {
@@ -55,6 +74,7 @@ func NewLoader(opts ...Option) Loader {
 		exit:     make(chan bool),
 		opts:     options,
 		watchers: list.New(),
+		events:   list.New(),
 	}
 }
 
@@ -84,46 +104,26 @@ func (m *loader) Snapshot() (*Snapshot, error) {
 
 // Sync loads all the sources, calls the parser and updates the config
 func (m *loader) Sync() error {
-	//nolint:prealloc
-	var sets []*source.ChangeSet
-
 	m.Lock()
 
 	// read the source
 	var gerr []string
 
-	for _, source := range m.sources {
+	for i, source := range m.sources {
 		ch, err := source.Read()
 		if err != nil {
 			gerr = append(gerr, err.Error())
 			continue
 		}
-		sets = append(sets, ch)
+		m.sets[i] = ch
 	}
 
-	// merge sets
-	set, err := m.opts.Reader.Merge(sets...)
-	if err != nil {
-		m.Unlock()
-		return err
-	}
+	m.Unlock()
 
-	// set values
-	values, err := m.opts.Reader.Values(set)
-	if err != nil {
-		m.Unlock()
+	// merge sets and update watchers
+	if err := m.reload(SourceSync); err != nil {
 		return err
 	}
-	m.values = values
-	m.snap = &Snapshot{
-		ChangeSet: set,
-		Version:   fmt.Sprintf("%d", time.Now().Unix()),
-	}
-
-	m.Unlock()
-
-	// update watchers
-	m.update()
 
 	if len(gerr) > 0 {
 		return fmt.Errorf("source loading errors: %s", strings.Join(gerr, "\n"))
@@ -181,6 +181,8 @@ func (m *loader) Get(path ...string) (reader.Value, error) {
 
 func (m *loader) Load(sources ...source.Source) error {
 	var gerrors []string
+	//nolint:prealloc
+	var names []string
 
 	for _, source := range sources {
 		set, err := source.Read()
@@ -192,6 +194,7 @@ func (m *loader) Load(sources ...source.Source) error {
 			// continue processing
 			continue
 		}
+		names = append(names, source.String())
 		m.Lock()
 		m.sources = append(m.sources, source)
 		m.sets = append(m.sets, set)
@@ -202,7 +205,7 @@ func (m *loader) Load(sources ...source.Source) error {
 		}
 	}
 
-	if err := m.reload(); err != nil {
+	if err := m.reload(strings.Join(names, ",")); err != nil {
 		gerrors = append(gerrors, err.Error())
 	}
 
@@ -226,7 +229,7 @@ func (m *loader) Watch(path ...string) (Watcher, error) {
 		path:    path,
 		value:   value,
 		reader:  m.opts.Reader,
-		updates: make(chan reader.Value, 1),
+		updates: make(chan update, 1),
 	}
 
 	e := m.watchers.PushBack(w)
@@ -244,6 +247,8 @@ func (m *loader) Watch(path ...string) (Watcher, error) {
 }
 
 func (m *loader) watch(idx int, s source.Source) {
+	name := s.String()
+
 	// watches a source for changes
 	watch := func(idx int, s source.Watcher) error {
 		for {
@@ -257,7 +262,7 @@ func (m *loader) watch(idx int, s source.Source) {
 			m.sets[idx] = cs
 			m.Unlock()
 
-			if err := m.reload(); err != nil {
+			if err := m.reload(name); err != nil {
 				return err
 			}
 		}
@@ -310,8 +315,8 @@ func (m *loader) loaded() bool {
 	return loaded
 }
 
-// reload reads the sets and creates new values
-func (m *loader) reload() error {
+// reload reads the sets and creates new values, src is the source of the change
+func (m *loader) reload(src string) error {
 	m.Lock()
 
 	// merge sets
@@ -322,11 +327,31 @@ func (m *loader) reload() error {
 	}
 
 	// set values
-	m.values, _ = m.opts.Reader.Values(set)
-	m.snap = &Snapshot{
-		ChangeSet: set,
-		Version:   fmt.Sprintf("%d", time.Now().Unix()),
+	values, _ := m.opts.Reader.Values(set)
+
+	var current *source.ChangeSet
+	if m.snap != nil {
+		current = m.snap.ChangeSet
 	}
+	changes := diff(flatten(m.values), flatten(values), secrets(current), secrets(set))
+
+	// only keep versions which changed the config
+	if m.snap == nil || len(changes) > 0 {
+		m.attribute(changes, src)
+		m.version++
+		m.snap = &Snapshot{
+			ChangeSet: set,
+			Version:   strconv.FormatUint(m.version, 10),
+		}
+		m.record(&Record{
+			Snapshot:  m.snap,
+			Sources:   sums(m.sets),
+			Source:    src,
+			Changes:   changes,
+			Timestamp: time.Now(),
+		})
+	}
+	m.values = values
 
 	m.Unlock()
 
@@ -345,9 +370,13 @@ func (m *loader) update() {
 	}
 	m.RUnlock()
 
+	m.RLock()
+	values, version := m.values, m.snap.Version
+	m.RUnlock()
+
 	for _, w := range watchers {
 		select {
-		case w.updates <- m.values.Get(w.path...):
+		case w.updates <- update{value: values.Get(w.path...), version: version}:
 		default:
 		}
 	}
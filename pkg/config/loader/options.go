@@ -11,6 +11,8 @@ import (
 type Options struct {
 	Reader reader.Reader
 	Watch  bool
+	// History is the number of snapshots kept
+	History int
 
 	// for alternative data
 	Context context.Context
@@ -25,17 +27,25 @@ func WithReader(r reader.Reader) Option {
 	}
 }
 
-// WithReader sets the config reader
+// WithWatch sets if the sources are watched
 func WithWatch(t bool) Option {
 	return func(o *Options) {
 		o.Watch = t
 	}
 }
 
+// WithHistory sets the number of snapshots kept
+func WithHistory(n int) Option {
+	return func(o *Options) {
+		o.History = n
+	}
+}
+
 func NewOptions(opts ...Option) Options {
 	options := Options{
-		Reader: json.NewReader(),
-		Watch:  true,
+		Reader:  json.NewReader(),
+		Watch:   true,
+		History: DefaultHistory,
 	}
 
 	for _, o := range opts {
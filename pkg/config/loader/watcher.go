@@ -3,7 +3,6 @@ package loader
 import (
 	"bytes"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/stack-labs/stack/pkg/config/reader"
@@ -25,7 +24,13 @@ type watcher struct {
 	path    []string
 	value   reader.Value
 	reader  reader.Reader
-	updates chan reader.Value
+	updates chan update
+}
+
+// update of the watched value
+type update struct {
+	value   reader.Value
+	version string
 }
 
 func (w *watcher) Next() (*Snapshot, error) {
@@ -33,7 +38,8 @@ func (w *watcher) Next() (*Snapshot, error) {
 		select {
 		case <-w.exit:
 			return nil, errors.New("watcher stopped")
-		case v := <-w.updates:
+		case u := <-w.updates:
+			v := u.value
 			if bytes.Equal(w.value.Bytes(), v.Bytes()) {
 				continue
 			}
@@ -49,7 +55,7 @@ func (w *watcher) Next() (*Snapshot, error) {
 
 			return &Snapshot{
 				ChangeSet: cs,
-				Version:   fmt.Sprintf("%d", time.Now().Unix()),
+				Version:   u.version,
 			}, nil
 		}
 	}
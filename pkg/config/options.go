@@ -2,12 +2,16 @@ package config
 
 import (
 	"context"
+
+	"github.com/stack-labs/stack/pkg/config/loader"
 )
 
 type Options struct {
 	Storage    bool
 	StorageDir string
 	Watch      bool
+	// History is the number of versions kept
+	History int
 	// for alternative data
 	Context context.Context
 }
@@ -18,6 +22,7 @@ func NewOptions(opts ...Option) Options {
 	options := Options{
 		Storage: false,
 		Watch:   true,
+		History: loader.DefaultHistory,
 	}
 
 	for _, o := range opts {
@@ -44,3 +49,10 @@ func StorageDir(d string) Option {
 		o.StorageDir = d
 	}
 }
+
+// History sets the number of versions kept
+func History(n int) Option {
+	return func(o *Options) {
+		o.History = n
+	}
+}
@@ -10,6 +10,7 @@ import (
 	simple "github.com/bitly/go-simplejson"
 	"github.com/stack-labs/stack/pkg/config/reader"
 	"github.com/stack-labs/stack/pkg/config/source"
+	"github.com/stack-labs/stack/util/log"
 )
 
 type jsonValues struct {
@@ -24,6 +25,10 @@ type jsonValue struct {
 func newValues(ch *source.ChangeSet) (reader.Values, error) {
 	sj := simple.New()
 	data, _ := reader.ReplaceEnvVars(ch.Data)
+	data, err := reader.ReplaceSecrets(data)
+	if err != nil {
+		log.Warnf("config %v", err)
+	}
 	if err := sj.UnmarshalJSON(data); err != nil {
 		sj.SetPath(nil, string(ch.Data))
 	}
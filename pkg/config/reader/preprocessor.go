@@ -1,8 +1,18 @@
 package reader
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"regexp"
+	"strings"
+
+	"github.com/stack-labs/stack/pkg/config/secret"
+)
+
+var (
+	secretRe    = regexp.MustCompile(`\$\{secret:([A-Za-z0-9_./\-]+)\}`)
+	encryptedRe = regexp.MustCompile(`ENC\(([A-Za-z0-9+/=_\-]+)\)`)
 )
 
 func ReplaceEnvVars(raw []byte) ([]byte, error) {
@@ -21,3 +31,46 @@ func replaceEnvVars(element string) string {
 	el := os.Getenv(v)
 	return el
 }
+
+// IsSecret reports whether the value references a secret or is encrypted
+func IsSecret(s string) bool {
+	return secretRe.MatchString(s) || encryptedRe.MatchString(s)
+}
+
+// ReplaceSecrets resolves the ${secret:name} references and decrypts the
+// ENC(...) values of the JSON data with the registered secret providers.
+// Values which can't be resolved are left as is and returned in the error
+func ReplaceSecrets(raw []byte) ([]byte, error) {
+	if !secretRe.Match(raw) && !encryptedRe.Match(raw) {
+		return raw, nil
+	}
+
+	var errs []string
+
+	// the secrets are escaped as they're in JSON strings
+	replace := func(re *regexp.Regexp, data []byte, resolve func(string) (string, error)) []byte {
+		return re.ReplaceAllFunc(data, func(element []byte) []byte {
+			arg := string(re.FindSubmatch(element)[1])
+			s, err := resolve(arg)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", element, err))
+				return element
+			}
+			b, err := json.Marshal(s)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", element, err))
+				return element
+			}
+			return b[1 : len(b)-1]
+		})
+	}
+
+	data := replace(secretRe, raw, secret.Get)
+	data = replace(encryptedRe, data, secret.Decrypt)
+
+	if len(errs) > 0 {
+		return data, fmt.Errorf("unresolved secrets: %s", strings.Join(errs, "; "))
+	}
+
+	return data, nil
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/stack-labs/stack/pkg/config/secret"
 )
 
 func TestReplaceEnvVars(t *testing.T) {
@@ -71,3 +73,46 @@ func TestReplaceEnvVars(t *testing.T) {
 		}
 	}
 }
+
+type testProvider struct {
+	secrets map[string]string
+}
+
+func (p *testProvider) Get(name string) (string, error) {
+	s, ok := p.secrets[name]
+	if !ok {
+		return "", secret.ErrNotFound
+	}
+	return s, nil
+}
+
+func (p *testProvider) Decrypt(ciphertext string) (string, error) {
+	return p.secrets["enc:"+ciphertext], nil
+}
+
+func (p *testProvider) String() string {
+	return "test"
+}
+
+func TestReplaceSecrets(t *testing.T) {
+	p := &testProvider{secrets: map[string]string{"db.password": `p"ss`, "enc:YWJj": "abc"}}
+	secret.Register(p)
+	defer secret.Deregister(p)
+
+	res, err := ReplaceSecrets([]byte(`{"db": {"password": "${secret:db.password}", "user": "ENC(YWJj)", "host": "${HOST}"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"db": {"password": "p\"ss", "user": "abc", "host": "${HOST}"}}`; string(res) != expected {
+		t.Fatalf("Expected %s got %s", expected, res)
+	}
+
+	res, err = ReplaceSecrets([]byte(`{"key": "${secret:missing}"}`))
+	if err == nil || string(res) != `{"key": "${secret:missing}"}` {
+		t.Fatalf("expected the missing secret left got %s %v", res, err)
+	}
+
+	if !IsSecret("${secret:db.password}") || !IsSecret("ENC(YWJj)") || IsSecret("${HOST}") {
+		t.Fatal("unexpected secret references")
+	}
+}
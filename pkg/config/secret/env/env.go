@@ -0,0 +1,56 @@
+// Package env provides the secrets of the environment variables
+package env
+
+import (
+	"os"
+	"strings"
+
+	"github.com/stack-labs/stack/pkg/config/secret"
+)
+
+var (
+	// DefaultPrefix of the environment variables of the secrets
+	DefaultPrefix = "STACK_SECRET_"
+)
+
+type env struct {
+	prefix string
+}
+
+// Option of the provider
+type Option func(e *env)
+
+// Prefix sets the prefix of the environment variables
+func Prefix(p string) Option {
+	return func(e *env) {
+		e.prefix = p
+	}
+}
+
+// Get returns the environment variable of the name, eg. ${secret:db.password}
+// is STACK_SECRET_DB_PASSWORD
+func (e *env) Get(name string) (string, error) {
+	key := e.prefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_", "/", "_").Replace(name))
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return "", secret.ErrNotFound
+	}
+	return v, nil
+}
+
+func (e *env) Decrypt(ciphertext string) (string, error) {
+	return "", secret.ErrNotSupported
+}
+
+func (e *env) String() string {
+	return "env"
+}
+
+// NewProvider returns a provider of the secrets kept in environment variables
+func NewProvider(opts ...Option) secret.Provider {
+	e := &env{prefix: DefaultPrefix}
+	for _, o := range opts {
+		o(e)
+	}
+	return e
+}
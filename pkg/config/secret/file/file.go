@@ -0,0 +1,212 @@
+// Package file provides the secrets of a local file encrypted with AES-GCM
+package file
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/pkg/config/secret"
+)
+
+var (
+	// DefaultKeyEnv is the environment variable of the base64 key used without a key option
+	DefaultKeyEnv = "STACK_SECRET_KEY"
+
+	// ErrNoKey is returned by a provider without a key
+	ErrNoKey = errors.New("secret key is blank")
+)
+
+type file struct {
+	path string
+	key  []byte
+	err  error
+
+	sync.Mutex
+	secrets map[string]string
+	modTime time.Time
+}
+
+// Option of the provider
+type Option func(f *file)
+
+// Path sets the file of the secrets, written by Seal
+func Path(p string) Option {
+	return func(f *file) {
+		f.path = p
+	}
+}
+
+// Key sets the AES key, 16, 24 or 32 bytes
+func Key(k []byte) Option {
+	return func(f *file) {
+		f.key = k
+	}
+}
+
+// KeyFile sets the file of the base64 AES key
+func KeyFile(p string) Option {
+	return func(f *file) {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			f.err = err
+			return
+		}
+		f.key, f.err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, ErrNoKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt the plaintext with the key, the result is the ciphertext of an ENC(...) value
+func Encrypt(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), nil)), nil
+}
+
+// Decrypt the ciphertext written by Encrypt
+func Decrypt(key []byte, ciphertext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ciphertext))
+	if err != nil {
+		return "", err
+	}
+	if len(b) < gcm.NonceSize() {
+		return "", errors.New("ciphertext is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// Seal encrypts the secrets by name into the content of a secrets file
+func Seal(key []byte, secrets map[string]string) ([]byte, error) {
+	b, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := Encrypt(key, string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+// load the secrets file, again if it changed
+func (f *file) load() (map[string]string, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+	if f.secrets != nil && fi.ModTime().Equal(f.modTime) {
+		return f.secrets, nil
+	}
+
+	b, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := Decrypt(f.key, string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]string)
+	if err := json.Unmarshal([]byte(s), &secrets); err != nil {
+		return nil, err
+	}
+
+	f.secrets = secrets
+	f.modTime = fi.ModTime()
+
+	return secrets, nil
+}
+
+func (f *file) Get(name string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	if len(f.path) == 0 {
+		return "", secret.ErrNotFound
+	}
+
+	secrets, err := f.load()
+	if err != nil {
+		return "", err
+	}
+
+	s, ok := secrets[name]
+	if !ok {
+		return "", secret.ErrNotFound
+	}
+	return s, nil
+}
+
+func (f *file) Decrypt(ciphertext string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return Decrypt(f.key, ciphertext)
+}
+
+func (f *file) String() string {
+	return "file"
+}
+
+// NewProvider returns a provider decrypting the ENC(...) values and the
+// secrets of the file with an AES-GCM key
+func NewProvider(opts ...Option) secret.Provider {
+	f := new(file)
+	for _, o := range opts {
+		o(f)
+	}
+
+	if len(f.key) == 0 && f.err == nil {
+		if k := os.Getenv(DefaultKeyEnv); len(k) > 0 {
+			f.key, f.err = base64.StdEncoding.DecodeString(k)
+		}
+	}
+
+	return f
+}
@@ -0,0 +1,54 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stack-labs/stack/pkg/config/secret"
+)
+
+func TestProvider(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	ct, err := Encrypt(key, "private key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sealed, err := Seal(key, map[string]string{"etcd.password": "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "secrets.enc")
+	if err := ioutil.WriteFile(path, sealed, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewProvider(Path(path), Key(key))
+
+	if s, err := p.Decrypt(ct); err != nil || s != "private key" {
+		t.Fatalf("unexpected plaintext %s %v", s, err)
+	}
+	if s, err := p.Get("etcd.password"); err != nil || s != "s3cret" {
+		t.Fatalf("unexpected secret %s %v", s, err)
+	}
+	if _, err := p.Get("missing"); err != secret.ErrNotFound {
+		t.Fatalf("expected not found got %v", err)
+	}
+
+	other := NewProvider(Key([]byte("fedcba9876543210fedcba9876543210")))
+	if _, err := other.Decrypt(ct); err == nil {
+		t.Fatal("expected the other key failing")
+	}
+	if _, err := NewProvider().Decrypt(ct); err != ErrNoKey {
+		t.Fatalf("expected no key got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+// Package secret resolves the secrets referenced by config values
+package secret
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrNotFound is returned for secrets no provider has
+	ErrNotFound = errors.New("secret not found")
+	// ErrNotSupported is returned by providers which can't decrypt values
+	ErrNotSupported = errors.New("not supported by the provider")
+
+	// DefaultMask replaces the values of the keys holding secrets in debug output
+	DefaultMask = "******"
+
+	mu        sync.RWMutex
+	providers []Provider
+)
+
+// Provider resolves the ${secret:name} references and ENC(...) values of the config
+type Provider interface {
+	// Get returns the secret of the name, ErrNotFound if the provider has none
+	Get(name string) (string, error)
+	// Decrypt returns the plaintext of an ENC(...) value
+	Decrypt(ciphertext string) (string, error)
+	String() string
+}
+
+// Register a provider, the providers are asked in the order they're registered
+func Register(p Provider) {
+	mu.Lock()
+	providers = append(providers, p)
+	mu.Unlock()
+}
+
+// Deregister a provider
+func Deregister(p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i, rp := range providers {
+		if rp == p {
+			providers = append(providers[:i], providers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Get the secret of the name from the registered providers
+func Get(name string) (string, error) {
+	mu.RLock()
+	ps := providers
+	mu.RUnlock()
+
+	var last error = ErrNotFound
+	for _, p := range ps {
+		s, err := p.Get(name)
+		if err == ErrNotFound || err == ErrNotSupported {
+			continue
+		} else if err != nil {
+			// another provider may have the secret
+			last = err
+			continue
+		}
+		return s, nil
+	}
+
+	return "", last
+}
+
+// Decrypt the ciphertext with the registered providers
+func Decrypt(ciphertext string) (string, error) {
+	mu.RLock()
+	ps := providers
+	mu.RUnlock()
+
+	var last error = ErrNotSupported
+	for _, p := range ps {
+		s, err := p.Decrypt(ciphertext)
+		if err == ErrNotSupported {
+			continue
+		} else if err != nil {
+			// another provider may have the key
+			last = err
+			continue
+		}
+		return s, nil
+	}
+
+	return "", last
+}
@@ -0,0 +1,55 @@
+package secret
+
+import (
+	"errors"
+	"testing"
+)
+
+type testProvider struct {
+	secrets map[string]string
+	err     error
+}
+
+func (t *testProvider) Get(name string) (string, error) {
+	if t.err != nil {
+		return "", t.err
+	}
+	s, ok := t.secrets[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return s, nil
+}
+
+func (t *testProvider) Decrypt(ciphertext string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (t *testProvider) String() string {
+	return "test"
+}
+
+func TestGetNextProvider(t *testing.T) {
+	errBroken := errors.New("secrets file is broken")
+
+	broken := &testProvider{err: errBroken}
+	Register(broken)
+	defer Deregister(broken)
+
+	// the error of the failing provider is kept until another has the secret
+	if _, err := Get("db.password"); err != errBroken {
+		t.Fatalf("expected %v got %v", errBroken, err)
+	}
+
+	p := &testProvider{secrets: map[string]string{"db.password": "s3cret"}}
+	Register(p)
+	defer Deregister(p)
+
+	s, err := Get("db.password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "s3cret" {
+		t.Fatalf("expected s3cret got %s", s)
+	}
+}
@@ -0,0 +1,403 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/stack-labs/stack/pkg/config/service/proto"
+	"github.com/stack-labs/stack/pkg/config/source"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+var (
+	// DefaultNamespace of the requests without one
+	DefaultNamespace = "default"
+	// DefaultTable of the config in the store
+	DefaultTable = "config"
+	// DefaultHistory is the number of versions returned when the request has no limit
+	DefaultHistory int64 = 100
+	// DefaultPoll is how often watchers check for versions written by other servers
+	DefaultPoll = time.Second * 5
+)
+
+// Config serves versioned config documents kept in the store. Every write
+// adds a version, a rollback writes the config of an old version again.
+type Config struct {
+	Store store.Store
+
+	sync.Mutex
+	watchers map[string][]chan bool
+}
+
+// document is a version of the config in the store
+type document struct {
+	Version   int64  `json:"version"`
+	Data      []byte `json:"data"`
+	Checksum  string `json:"checksum"`
+	Format    string `json:"format"`
+	Source    string `json:"source"`
+	Timestamp int64  `json:"timestamp"`
+	Author    string `json:"author,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+	Rollback  int64  `json:"rollback,omitempty"`
+}
+
+func (d *document) changeSet() *pb.ChangeSet {
+	return &pb.ChangeSet{
+		Data:      d.Data,
+		Checksum:  d.Checksum,
+		Format:    d.Format,
+		Source:    d.Source,
+		Timestamp: d.Timestamp,
+	}
+}
+
+// id validates the namespace and path of a request
+func id(namespace, p string) (string, string, error) {
+	if len(p) == 0 {
+		return "", "", errors.BadRequest("stack.rpc.config", "path is blank")
+	}
+	if len(namespace) == 0 {
+		namespace = DefaultNamespace
+	}
+	if strings.Contains(namespace, "/") {
+		return "", "", errors.BadRequest("stack.rpc.config", "invalid namespace %s", namespace)
+	}
+	return namespace, path.Clean("/" + p), nil
+}
+
+func headKey(namespace, p string) string {
+	return "head/" + namespace + p
+}
+
+func versionPrefix(namespace, p string) string {
+	return "version/" + namespace + p + "/"
+}
+
+func versionKey(namespace, p string, version int64) string {
+	return fmt.Sprintf("%s%020d", versionPrefix(namespace, p), version)
+}
+
+// swap writes the record if the key has the old value. Stores without
+// compare and swap are only safe behind a single server.
+func (c *Config) swap(key string, value, old []byte) error {
+	r := &store.Record{Key: key, Value: value}
+	if cas, ok := c.Store.(store.CAS); ok {
+		return cas.CompareAndSwap(r, old, store.WriteTo("", DefaultTable))
+	}
+	return c.Store.WriteWith(r, store.WriteTo("", DefaultTable))
+}
+
+// head returns the latest version of the config, zero if there is none
+func (c *Config) head(namespace, p string) (int64, []byte, error) {
+	recs, err := c.Store.ReadWith(headKey(namespace, p), store.ReadFrom("", DefaultTable))
+	if err == store.ErrNotFound {
+		return 0, nil, nil
+	} else if err != nil {
+		return 0, nil, err
+	}
+
+	version, err := strconv.ParseInt(string(recs[0].Value), 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return version, recs[0].Value, nil
+}
+
+func (c *Config) read(namespace, p string, version int64) (*document, error) {
+	recs, err := c.Store.ReadWith(versionKey(namespace, p, version), store.ReadFrom("", DefaultTable))
+	if err == store.ErrNotFound {
+		return nil, errors.NotFound("stack.rpc.config", "version %d of %s not found", version, p)
+	} else if err != nil {
+		return nil, errors.InternalServerError("stack.rpc.config", err.Error())
+	}
+
+	doc := new(document)
+	if err := json.Unmarshal(recs[0].Value, doc); err != nil {
+		return nil, errors.InternalServerError("stack.rpc.config", err.Error())
+	}
+
+	return doc, nil
+}
+
+// write the document as the next version of the config
+func (c *Config) write(namespace, p string, doc *document) (int64, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	for {
+		head, old, err := c.head(namespace, p)
+		if err != nil {
+			return 0, errors.InternalServerError("stack.rpc.config", err.Error())
+		}
+
+		doc.Version = head + 1
+
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return 0, errors.InternalServerError("stack.rpc.config", err.Error())
+		}
+
+		// another server wrote the version first, finish its write in
+		// case it died before moving the head
+		err = c.swap(versionKey(namespace, p, doc.Version), b, nil)
+		if err == store.ErrConflict {
+			c.swap(headKey(namespace, p), []byte(strconv.FormatInt(doc.Version, 10)), old)
+			continue
+		} else if err != nil {
+			return 0, errors.InternalServerError("stack.rpc.config", err.Error())
+		}
+
+		err = c.swap(headKey(namespace, p), []byte(strconv.FormatInt(doc.Version, 10)), old)
+		if err == store.ErrConflict {
+			continue
+		} else if err != nil {
+			return 0, errors.InternalServerError("stack.rpc.config", err.Error())
+		}
+
+		break
+	}
+
+	c.notify(namespace, p)
+
+	return doc.Version, nil
+}
+
+// watch returns a channel signalled on writes of the config
+func (c *Config) watch(namespace, p string) (chan bool, func()) {
+	key := namespace + p
+	ch := make(chan bool, 1)
+
+	c.Lock()
+	if c.watchers == nil {
+		c.watchers = make(map[string][]chan bool)
+	}
+	c.watchers[key] = append(c.watchers[key], ch)
+	c.Unlock()
+
+	return ch, func() {
+		c.Lock()
+		defer c.Unlock()
+
+		watchers := c.watchers[key]
+		for i, w := range watchers {
+			if w == ch {
+				c.watchers[key] = append(watchers[:i], watchers[i+1:]...)
+				break
+			}
+		}
+		if len(c.watchers[key]) == 0 {
+			delete(c.watchers, key)
+		}
+	}
+}
+
+// notify the watchers of the config, called with the lock held
+func (c *Config) notify(namespace, p string) {
+	for _, ch := range c.watchers[namespace+p] {
+		select {
+		case ch <- true:
+		default:
+		}
+	}
+}
+
+func (c *Config) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadResponse) error {
+	namespace, p, err := id(req.Namespace, req.Path)
+	if err != nil {
+		return err
+	}
+
+	version := req.Version
+	if version <= 0 {
+		if version, _, err = c.head(namespace, p); err != nil {
+			return errors.InternalServerError("stack.rpc.config", err.Error())
+		}
+		if version == 0 {
+			return errors.NotFound("stack.rpc.config", "config %s not found", p)
+		}
+	}
+
+	doc, err := c.read(namespace, p, version)
+	if err != nil {
+		return err
+	}
+
+	rsp.ChangeSet = doc.changeSet()
+	rsp.Version = doc.Version
+
+	return nil
+}
+
+func (c *Config) Watch(ctx context.Context, req *pb.WatchRequest, stream pb.Source_WatchStream) error {
+	namespace, p, err := id(req.Namespace, req.Path)
+	if err != nil {
+		return err
+	}
+
+	ch, stop := c.watch(namespace, p)
+	defer stop()
+
+	// only versions written after the watch started are sent
+	last, _, err := c.head(namespace, p)
+	if err != nil {
+		return errors.InternalServerError("stack.rpc.config", err.Error())
+	}
+
+	t := time.NewTicker(DefaultPoll)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ch:
+		case <-t.C:
+		}
+
+		head, _, err := c.head(namespace, p)
+		if err != nil {
+			return errors.InternalServerError("stack.rpc.config", err.Error())
+		}
+		if head <= last {
+			continue
+		}
+
+		doc, err := c.read(namespace, p, head)
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(&pb.WatchResponse{
+			ChangeSet: doc.changeSet(),
+			Version:   doc.Version,
+		}); err != nil {
+			return err
+		}
+
+		last = head
+	}
+}
+
+func (c *Config) Write(ctx context.Context, req *pb.WriteRequest, rsp *pb.WriteResponse) error {
+	namespace, p, err := id(req.Namespace, req.Path)
+	if err != nil {
+		return err
+	}
+	if req.ChangeSet == nil {
+		return errors.BadRequest("stack.rpc.config", "change set is blank")
+	}
+
+	cs := &source.ChangeSet{
+		Data:     req.ChangeSet.Data,
+		Checksum: req.ChangeSet.Checksum,
+		Format:   req.ChangeSet.Format,
+		Source:   req.ChangeSet.Source,
+	}
+	if len(cs.Checksum) == 0 {
+		cs.Checksum = cs.Sum()
+	}
+	if len(cs.Format) == 0 {
+		cs.Format = "json"
+	}
+	if len(cs.Source) == 0 {
+		cs.Source = "stack"
+	}
+
+	rsp.Version, err = c.write(namespace, p, &document{
+		Data:      cs.Data,
+		Checksum:  cs.Checksum,
+		Format:    cs.Format,
+		Source:    cs.Source,
+		Timestamp: time.Now().Unix(),
+		Author:    req.Author,
+		Comment:   req.Comment,
+	})
+
+	return err
+}
+
+func (c *Config) Rollback(ctx context.Context, req *pb.RollbackRequest, rsp *pb.RollbackResponse) error {
+	namespace, p, err := id(req.Namespace, req.Path)
+	if err != nil {
+		return err
+	}
+	if req.Version <= 0 {
+		return errors.BadRequest("stack.rpc.config", "version is blank")
+	}
+
+	old, err := c.read(namespace, p, req.Version)
+	if err != nil {
+		return err
+	}
+
+	rsp.Version, err = c.write(namespace, p, &document{
+		Data:      old.Data,
+		Checksum:  old.Checksum,
+		Format:    old.Format,
+		Source:    old.Source,
+		Timestamp: time.Now().Unix(),
+		Author:    req.Author,
+		Comment:   req.Comment,
+		Rollback:  old.Version,
+	})
+
+	return err
+}
+
+func (c *Config) History(ctx context.Context, req *pb.HistoryRequest, rsp *pb.HistoryResponse) error {
+	namespace, p, err := id(req.Namespace, req.Path)
+	if err != nil {
+		return err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultHistory
+	}
+
+	prefix := versionPrefix(namespace, p)
+
+	recs, err := c.Store.ReadWith(prefix, store.ReadFrom("", DefaultTable), store.ReadPrefix())
+	if err != nil && err != store.ErrNotFound {
+		return errors.InternalServerError("stack.rpc.config", err.Error())
+	}
+
+	// drop the versions of paths under this one
+	var versions []*store.Record
+	for _, r := range recs {
+		if len(r.Key) == len(prefix)+20 {
+			versions = append(versions, r)
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Key > versions[j].Key
+	})
+
+	for i := 0; i < len(versions) && int64(i) < limit; i++ {
+		doc := new(document)
+		if err := json.Unmarshal(versions[i].Value, doc); err != nil {
+			return errors.InternalServerError("stack.rpc.config", err.Error())
+		}
+
+		rsp.Versions = append(rsp.Versions, &pb.Version{
+			Version:   doc.Version,
+			ChangeSet: doc.changeSet(),
+			Author:    doc.Author,
+			Comment:   doc.Comment,
+			Rollback:  doc.Rollback,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/stack-labs/stack/pkg/config/service/proto"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+type testStream struct {
+	pb.Source_WatchStream
+	ch chan *pb.WatchResponse
+}
+
+func (t *testStream) Send(rsp *pb.WatchResponse) error {
+	t.ch <- rsp
+	return nil
+}
+
+func write(t *testing.T, c *Config, namespace, path, data string) int64 {
+	rsp := new(pb.WriteResponse)
+	err := c.Write(context.Background(), &pb.WriteRequest{
+		Namespace: namespace,
+		Path:      path,
+		ChangeSet: &pb.ChangeSet{Data: []byte(data)},
+		Author:    "test",
+	}, rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rsp.Version
+}
+
+func read(t *testing.T, c *Config, namespace, path string, version int64) *pb.ReadResponse {
+	rsp := new(pb.ReadResponse)
+	if err := c.Read(context.Background(), &pb.ReadRequest{Namespace: namespace, Path: path, Version: version}, rsp); err != nil {
+		t.Fatal(err)
+	}
+	return rsp
+}
+
+func TestConfig(t *testing.T) {
+	c := &Config{Store: memory.NewStore()}
+	ctx := context.Background()
+
+	err := c.Read(ctx, &pb.ReadRequest{Path: "/stack"}, new(pb.ReadResponse))
+	if e := errors.Parse(err.Error()); e.Code != 404 {
+		t.Fatalf("expected not found got %v", err)
+	}
+
+	if v := write(t, c, "", "/stack", `{"a":1}`); v != 1 {
+		t.Fatalf("expected version 1 got %d", v)
+	}
+	if v := write(t, c, "", "stack", `{"a":2}`); v != 2 {
+		t.Fatalf("expected version 2 got %d", v)
+	}
+	write(t, c, "", "/stack/foo", `{"b":1}`)
+	write(t, c, "prod", "/stack", `{"a":"prod"}`)
+
+	rsp := read(t, c, "", "/stack", 0)
+	if rsp.Version != 2 || string(rsp.ChangeSet.Data) != `{"a":2}` || rsp.ChangeSet.Format != "json" || len(rsp.ChangeSet.Checksum) == 0 {
+		t.Fatalf("unexpected read %+v", rsp)
+	}
+	if rsp := read(t, c, "default", "/stack", 1); string(rsp.ChangeSet.Data) != `{"a":1}` {
+		t.Fatalf("unexpected read of version 1 %+v", rsp)
+	}
+	if rsp := read(t, c, "prod", "/stack", 0); rsp.Version != 1 || string(rsp.ChangeSet.Data) != `{"a":"prod"}` {
+		t.Fatalf("unexpected read of the prod namespace %+v", rsp)
+	}
+
+	rb := new(pb.RollbackResponse)
+	if err := c.Rollback(ctx, &pb.RollbackRequest{Path: "/stack", Version: 1, Comment: "bad config"}, rb); err != nil {
+		t.Fatal(err)
+	}
+	if rb.Version != 3 {
+		t.Fatalf("expected version 3 got %d", rb.Version)
+	}
+	if rsp := read(t, c, "", "/stack", 0); string(rsp.ChangeSet.Data) != `{"a":1}` {
+		t.Fatalf("expected the config of version 1 got %s", rsp.ChangeSet.Data)
+	}
+	if err := c.Rollback(ctx, &pb.RollbackRequest{Path: "/stack", Version: 9}, rb); err == nil {
+		t.Fatal("expected error rolling back to a missing version")
+	}
+
+	history := new(pb.HistoryResponse)
+	if err := c.History(ctx, &pb.HistoryRequest{Path: "/stack"}, history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Versions) != 3 {
+		t.Fatalf("expected 3 versions got %d", len(history.Versions))
+	}
+	if v := history.Versions[0]; v.Version != 3 || v.Rollback != 1 || v.Comment != "bad config" {
+		t.Fatalf("unexpected version %+v", v)
+	}
+	if v := history.Versions[2]; v.Version != 1 || v.Author != "test" {
+		t.Fatalf("unexpected version %+v", v)
+	}
+
+	history = new(pb.HistoryResponse)
+	c.History(ctx, &pb.HistoryRequest{Path: "/stack", Limit: 1}, history)
+	if len(history.Versions) != 1 {
+		t.Fatalf("expected 1 version got %d", len(history.Versions))
+	}
+
+	if err := c.Write(ctx, &pb.WriteRequest{Namespace: "a/b", Path: "/stack", ChangeSet: &pb.ChangeSet{}}, new(pb.WriteResponse)); err == nil {
+		t.Fatal("expected invalid namespace error")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	c := &Config{Store: memory.NewStore()}
+	write(t, c, "", "/stack", `{"a":1}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &testStream{ch: make(chan *pb.WatchResponse, 1)}
+
+	done := make(chan error)
+	go func() {
+		done <- c.Watch(ctx, &pb.WatchRequest{Path: "/stack"}, stream)
+	}()
+
+	// let the watch start
+	time.Sleep(time.Millisecond * 50)
+
+	write(t, c, "other", "/stack", `{"a":"other"}`)
+	write(t, c, "", "/stack", `{"a":2}`)
+
+	select {
+	case rsp := <-stream.ch:
+		if rsp.Version != 2 || string(rsp.ChangeSet.Data) != `{"a":2}` {
+			t.Fatalf("unexpected change %+v", rsp)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a change")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if len(c.watchers) != 0 {
+		t.Fatal("expected the watcher removed")
+	}
+}
+
+func TestUnfinishedWrite(t *testing.T) {
+	s := memory.NewStore()
+	c := &Config{Store: s}
+	write(t, c, "", "/stack", `{"a":1}`)
+
+	// a server died after writing version 2 but before moving the head
+	s.WriteWith(&store.Record{
+		Key:   versionKey("default", "/stack", 2),
+		Value: []byte(`{"version":2,"data":"e30="}`),
+	}, store.WriteTo("", DefaultTable))
+
+	if v := write(t, c, "", "/stack", `{"a":3}`); v != 3 {
+		t.Fatalf("expected version 3 got %d", v)
+	}
+	if rsp := read(t, c, "", "/stack", 2); string(rsp.ChangeSet.Data) != "{}" {
+		t.Fatalf("unexpected version 2 %s", rsp.ChangeSet.Data)
+	}
+}
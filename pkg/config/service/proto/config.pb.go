@@ -0,0 +1,734 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: config.proto
+
+package stack_rpc_config
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ChangeSet struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Checksum             string   `protobuf:"bytes,2,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Format               string   `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	Source               string   `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Timestamp            int64    `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChangeSet) Reset()         { *m = ChangeSet{} }
+func (m *ChangeSet) String() string { return proto.CompactTextString(m) }
+func (*ChangeSet) ProtoMessage()    {}
+func (*ChangeSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{0}
+}
+
+func (m *ChangeSet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeSet.Unmarshal(m, b)
+}
+func (m *ChangeSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeSet.Marshal(b, m, deterministic)
+}
+func (m *ChangeSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeSet.Merge(m, src)
+}
+func (m *ChangeSet) XXX_Size() int {
+	return xxx_messageInfo_ChangeSet.Size(m)
+}
+func (m *ChangeSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeSet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeSet proto.InternalMessageInfo
+
+func (m *ChangeSet) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *ChangeSet) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
+}
+
+func (m *ChangeSet) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *ChangeSet) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *ChangeSet) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type Version struct {
+	// version of the config, starting at 1
+	Version   int64      `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	ChangeSet *ChangeSet `protobuf:"bytes,2,opt,name=change_set,json=changeSet,proto3" json:"change_set,omitempty"`
+	Author    string     `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Comment   string     `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	// the version rolled back to, if any
+	Rollback             int64    `protobuf:"varint,5,opt,name=rollback,proto3" json:"rollback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Version) Reset()         { *m = Version{} }
+func (m *Version) String() string { return proto.CompactTextString(m) }
+func (*Version) ProtoMessage()    {}
+func (*Version) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{1}
+}
+
+func (m *Version) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Version.Unmarshal(m, b)
+}
+func (m *Version) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Version.Marshal(b, m, deterministic)
+}
+func (m *Version) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Version.Merge(m, src)
+}
+func (m *Version) XXX_Size() int {
+	return xxx_messageInfo_Version.Size(m)
+}
+func (m *Version) XXX_DiscardUnknown() {
+	xxx_messageInfo_Version.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Version proto.InternalMessageInfo
+
+func (m *Version) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Version) GetChangeSet() *ChangeSet {
+	if m != nil {
+		return m.ChangeSet
+	}
+	return nil
+}
+
+func (m *Version) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *Version) GetComment() string {
+	if m != nil {
+		return m.Comment
+	}
+	return ""
+}
+
+func (m *Version) GetRollback() int64 {
+	if m != nil {
+		return m.Rollback
+	}
+	return 0
+}
+
+type ReadRequest struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// namespace of the config, e.g. the environment
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// version to read, the latest if not set
+	Version              int64    `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
+func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadRequest) ProtoMessage()    {}
+func (*ReadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{2}
+}
+
+func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadRequest.Unmarshal(m, b)
+}
+func (m *ReadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadRequest.Merge(m, src)
+}
+func (m *ReadRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadRequest.Size(m)
+}
+func (m *ReadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadRequest proto.InternalMessageInfo
+
+func (m *ReadRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ReadRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ReadRequest) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type ReadResponse struct {
+	ChangeSet            *ChangeSet `protobuf:"bytes,1,opt,name=change_set,json=changeSet,proto3" json:"change_set,omitempty"`
+	Version              int64      `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
+func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadResponse) ProtoMessage()    {}
+func (*ReadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{3}
+}
+
+func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadResponse.Unmarshal(m, b)
+}
+func (m *ReadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadResponse.Merge(m, src)
+}
+func (m *ReadResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadResponse.Size(m)
+}
+func (m *ReadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadResponse proto.InternalMessageInfo
+
+func (m *ReadResponse) GetChangeSet() *ChangeSet {
+	if m != nil {
+		return m.ChangeSet
+	}
+	return nil
+}
+
+func (m *ReadResponse) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type WatchRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{4}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type WatchResponse struct {
+	ChangeSet            *ChangeSet `protobuf:"bytes,1,opt,name=change_set,json=changeSet,proto3" json:"change_set,omitempty"`
+	Version              int64      `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
+func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchResponse) ProtoMessage()    {}
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{5}
+}
+
+func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchResponse.Unmarshal(m, b)
+}
+func (m *WatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchResponse.Merge(m, src)
+}
+func (m *WatchResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchResponse.Size(m)
+}
+func (m *WatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchResponse proto.InternalMessageInfo
+
+func (m *WatchResponse) GetChangeSet() *ChangeSet {
+	if m != nil {
+		return m.ChangeSet
+	}
+	return nil
+}
+
+func (m *WatchResponse) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type WriteRequest struct {
+	Path                 string     `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Namespace            string     `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	ChangeSet            *ChangeSet `protobuf:"bytes,3,opt,name=change_set,json=changeSet,proto3" json:"change_set,omitempty"`
+	Author               string     `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	Comment              string     `protobuf:"bytes,5,opt,name=comment,proto3" json:"comment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
+func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
+func (*WriteRequest) ProtoMessage()    {}
+func (*WriteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{6}
+}
+
+func (m *WriteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteRequest.Unmarshal(m, b)
+}
+func (m *WriteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteRequest.Marshal(b, m, deterministic)
+}
+func (m *WriteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteRequest.Merge(m, src)
+}
+func (m *WriteRequest) XXX_Size() int {
+	return xxx_messageInfo_WriteRequest.Size(m)
+}
+func (m *WriteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteRequest proto.InternalMessageInfo
+
+func (m *WriteRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *WriteRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *WriteRequest) GetChangeSet() *ChangeSet {
+	if m != nil {
+		return m.ChangeSet
+	}
+	return nil
+}
+
+func (m *WriteRequest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *WriteRequest) GetComment() string {
+	if m != nil {
+		return m.Comment
+	}
+	return ""
+}
+
+type WriteResponse struct {
+	Version              int64    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteResponse) Reset()         { *m = WriteResponse{} }
+func (m *WriteResponse) String() string { return proto.CompactTextString(m) }
+func (*WriteResponse) ProtoMessage()    {}
+func (*WriteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{7}
+}
+
+func (m *WriteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteResponse.Unmarshal(m, b)
+}
+func (m *WriteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteResponse.Marshal(b, m, deterministic)
+}
+func (m *WriteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteResponse.Merge(m, src)
+}
+func (m *WriteResponse) XXX_Size() int {
+	return xxx_messageInfo_WriteResponse.Size(m)
+}
+func (m *WriteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteResponse proto.InternalMessageInfo
+
+func (m *WriteResponse) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type RollbackRequest struct {
+	Path      string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// version to roll back to
+	Version              int64    `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Author               string   `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	Comment              string   `protobuf:"bytes,5,opt,name=comment,proto3" json:"comment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RollbackRequest) Reset()         { *m = RollbackRequest{} }
+func (m *RollbackRequest) String() string { return proto.CompactTextString(m) }
+func (*RollbackRequest) ProtoMessage()    {}
+func (*RollbackRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{8}
+}
+
+func (m *RollbackRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollbackRequest.Unmarshal(m, b)
+}
+func (m *RollbackRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollbackRequest.Marshal(b, m, deterministic)
+}
+func (m *RollbackRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollbackRequest.Merge(m, src)
+}
+func (m *RollbackRequest) XXX_Size() int {
+	return xxx_messageInfo_RollbackRequest.Size(m)
+}
+func (m *RollbackRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollbackRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollbackRequest proto.InternalMessageInfo
+
+func (m *RollbackRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *RollbackRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *RollbackRequest) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *RollbackRequest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *RollbackRequest) GetComment() string {
+	if m != nil {
+		return m.Comment
+	}
+	return ""
+}
+
+type RollbackResponse struct {
+	// the new version with the config of the old
+	Version              int64    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RollbackResponse) Reset()         { *m = RollbackResponse{} }
+func (m *RollbackResponse) String() string { return proto.CompactTextString(m) }
+func (*RollbackResponse) ProtoMessage()    {}
+func (*RollbackResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{9}
+}
+
+func (m *RollbackResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollbackResponse.Unmarshal(m, b)
+}
+func (m *RollbackResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollbackResponse.Marshal(b, m, deterministic)
+}
+func (m *RollbackResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollbackResponse.Merge(m, src)
+}
+func (m *RollbackResponse) XXX_Size() int {
+	return xxx_messageInfo_RollbackResponse.Size(m)
+}
+func (m *RollbackResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollbackResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollbackResponse proto.InternalMessageInfo
+
+func (m *RollbackResponse) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type HistoryRequest struct {
+	Path      string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// number of versions, most recent first
+	Limit                int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryRequest) Reset()         { *m = HistoryRequest{} }
+func (m *HistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*HistoryRequest) ProtoMessage()    {}
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{10}
+}
+
+func (m *HistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryRequest.Unmarshal(m, b)
+}
+func (m *HistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *HistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryRequest.Merge(m, src)
+}
+func (m *HistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_HistoryRequest.Size(m)
+}
+func (m *HistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryRequest proto.InternalMessageInfo
+
+func (m *HistoryRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *HistoryRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *HistoryRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type HistoryResponse struct {
+	Versions             []*Version `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *HistoryResponse) Reset()         { *m = HistoryResponse{} }
+func (m *HistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*HistoryResponse) ProtoMessage()    {}
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{11}
+}
+
+func (m *HistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryResponse.Unmarshal(m, b)
+}
+func (m *HistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *HistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryResponse.Merge(m, src)
+}
+func (m *HistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_HistoryResponse.Size(m)
+}
+func (m *HistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryResponse proto.InternalMessageInfo
+
+func (m *HistoryResponse) GetVersions() []*Version {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ChangeSet)(nil), "stack.rpc.config.ChangeSet")
+	proto.RegisterType((*Version)(nil), "stack.rpc.config.Version")
+	proto.RegisterType((*ReadRequest)(nil), "stack.rpc.config.ReadRequest")
+	proto.RegisterType((*ReadResponse)(nil), "stack.rpc.config.ReadResponse")
+	proto.RegisterType((*WatchRequest)(nil), "stack.rpc.config.WatchRequest")
+	proto.RegisterType((*WatchResponse)(nil), "stack.rpc.config.WatchResponse")
+	proto.RegisterType((*WriteRequest)(nil), "stack.rpc.config.WriteRequest")
+	proto.RegisterType((*WriteResponse)(nil), "stack.rpc.config.WriteResponse")
+	proto.RegisterType((*RollbackRequest)(nil), "stack.rpc.config.RollbackRequest")
+	proto.RegisterType((*RollbackResponse)(nil), "stack.rpc.config.RollbackResponse")
+	proto.RegisterType((*HistoryRequest)(nil), "stack.rpc.config.HistoryRequest")
+	proto.RegisterType((*HistoryResponse)(nil), "stack.rpc.config.HistoryResponse")
+}
+
+func init() { proto.RegisterFile("config.proto", fileDescriptor_3eaf2c85e69e9ea4) }
+
+var fileDescriptor_3eaf2c85e69e9ea4 = []byte{
+	// 526 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x95, 0xcd, 0x6e, 0xd4, 0x3c,
+	0x14, 0x86, 0x3f, 0x37, 0xf3, 0x7b, 0x66, 0xfa, 0xb5, 0xb2, 0x50, 0x15, 0xc2, 0x4f, 0x53, 0xaf,
+	0x06, 0x09, 0x45, 0x68, 0x10, 0x1b, 0x56, 0x48, 0x2c, 0xa8, 0x10, 0x0b, 0xe4, 0x91, 0x28, 0xac,
+	0x90, 0xeb, 0x71, 0x9b, 0x68, 0x26, 0x71, 0x88, 0x3d, 0x48, 0x5c, 0x02, 0x2b, 0x24, 0xee, 0x03,
+	0xae, 0x11, 0xc5, 0x71, 0x32, 0x49, 0x67, 0x52, 0x44, 0xa4, 0xee, 0x7c, 0x8e, 0x9d, 0xe3, 0xe7,
+	0x3d, 0x7e, 0xed, 0xc0, 0x94, 0xcb, 0xe4, 0x2a, 0xba, 0x0e, 0xd2, 0x4c, 0x6a, 0x89, 0x8f, 0x95,
+	0x66, 0x7c, 0x15, 0x64, 0x29, 0x0f, 0x8a, 0x3c, 0xf9, 0x8e, 0x60, 0xfc, 0x3a, 0x64, 0xc9, 0xb5,
+	0x58, 0x08, 0x8d, 0x31, 0xf4, 0x96, 0x4c, 0x33, 0x17, 0xf9, 0x68, 0x36, 0xa5, 0x66, 0x8c, 0x3d,
+	0x18, 0xf1, 0x50, 0xf0, 0x95, 0xda, 0xc4, 0xee, 0x81, 0x8f, 0x66, 0x63, 0x5a, 0xc5, 0xf8, 0x04,
+	0x06, 0x57, 0x32, 0x8b, 0x99, 0x76, 0x1d, 0x33, 0x63, 0xa3, 0x3c, 0xaf, 0xe4, 0x26, 0xe3, 0xc2,
+	0xed, 0x15, 0xf9, 0x22, 0xc2, 0x0f, 0x61, 0xac, 0xa3, 0x58, 0x28, 0xcd, 0xe2, 0xd4, 0xed, 0xfb,
+	0x68, 0xe6, 0xd0, 0x6d, 0x82, 0xfc, 0x42, 0x30, 0xfc, 0x20, 0x32, 0x15, 0xc9, 0x04, 0xbb, 0x30,
+	0xfc, 0x5a, 0x0c, 0x0d, 0x8c, 0x43, 0xcb, 0x10, 0xbf, 0x04, 0xe0, 0x06, 0xf8, 0xb3, 0x12, 0xda,
+	0x10, 0x4d, 0xe6, 0x0f, 0x82, 0x9b, 0xc2, 0x82, 0x4a, 0x14, 0x1d, 0xf3, 0x4a, 0xdf, 0x09, 0x0c,
+	0xd8, 0x46, 0x87, 0x32, 0x2b, 0x79, 0x8b, 0x28, 0xdf, 0x8d, 0xcb, 0x38, 0x16, 0x89, 0xb6, 0xc0,
+	0x65, 0x98, 0xab, 0xcf, 0xe4, 0x7a, 0x7d, 0xc9, 0xf8, 0xca, 0x02, 0x57, 0x31, 0xf9, 0x04, 0x13,
+	0x2a, 0xd8, 0x92, 0x8a, 0x2f, 0x1b, 0xa1, 0x4c, 0xf3, 0x52, 0xa6, 0x43, 0xc3, 0x3b, 0xa6, 0x66,
+	0x9c, 0x0b, 0x4e, 0x58, 0x2c, 0x54, 0xca, 0xb8, 0xb0, 0xdd, 0xdb, 0x26, 0xea, 0x22, 0x9d, 0x86,
+	0x48, 0xb2, 0x84, 0x69, 0x51, 0x5a, 0xa5, 0x32, 0x51, 0xe2, 0x86, 0x68, 0xf4, 0x4f, 0xa2, 0x6b,
+	0xbb, 0x1c, 0x34, 0x77, 0x79, 0x05, 0xd3, 0x0b, 0xa6, 0x79, 0xd8, 0x59, 0x01, 0x11, 0x70, 0x68,
+	0x2b, 0xdc, 0x29, 0xe8, 0x6f, 0x04, 0xd3, 0x8b, 0x2c, 0xd2, 0xa2, 0x7b, 0xaf, 0x9b, 0x60, 0x4e,
+	0x47, 0xdb, 0xf4, 0xda, 0x6c, 0xd3, 0x6f, 0xd8, 0x86, 0x3c, 0x81, 0x43, 0xcb, 0x6b, 0xfb, 0xd2,
+	0xea, 0x67, 0xf2, 0x03, 0xc1, 0x11, 0xb5, 0x96, 0xba, 0x03, 0x2b, 0x75, 0x80, 0x7f, 0x0a, 0xc7,
+	0x5b, 0xa0, 0xbf, 0xf2, 0x7f, 0x84, 0xff, 0xcf, 0x23, 0xa5, 0x65, 0xf6, 0xad, 0x3b, 0xfd, 0x3d,
+	0xe8, 0xaf, 0xa3, 0x38, 0xd2, 0x96, 0xbd, 0x08, 0xc8, 0x39, 0x1c, 0x55, 0x95, 0x2d, 0xc6, 0x0b,
+	0x18, 0xd9, 0x7d, 0x95, 0x8b, 0x7c, 0x67, 0x36, 0x99, 0xdf, 0xdf, 0x3d, 0x43, 0xfb, 0x86, 0xd0,
+	0x6a, 0xe9, 0xfc, 0xa7, 0x03, 0x83, 0x45, 0xf1, 0x04, 0xbd, 0x81, 0x5e, 0x7e, 0xb3, 0xf0, 0xa3,
+	0xdd, 0xef, 0x6a, 0x97, 0xd9, 0x7b, 0xdc, 0x36, 0x5d, 0x80, 0x90, 0xff, 0xf0, 0x3b, 0xe8, 0x1b,
+	0xeb, 0xe3, 0x3d, 0x4b, 0xeb, 0xb7, 0xca, 0x3b, 0x6d, 0x9d, 0x2f, 0x6b, 0x3d, 0x43, 0xf8, 0x2d,
+	0xf4, 0x8d, 0x61, 0xf6, 0x56, 0xab, 0x39, 0xdf, 0x3b, 0x6d, 0x9d, 0xaf, 0xc8, 0x16, 0x30, 0x2a,
+	0xcf, 0x0f, 0x9f, 0xed, 0xd1, 0xd1, 0x34, 0x9b, 0x47, 0x6e, 0x5b, 0x52, 0x15, 0x7d, 0x0f, 0x43,
+	0x7b, 0x18, 0xd8, 0xdf, 0xfd, 0xa0, 0xe9, 0x00, 0xef, 0xec, 0x96, 0x15, 0x65, 0xc5, 0xcb, 0x81,
+	0xf9, 0x27, 0x3d, 0xff, 0x33, 0x00, 0xf0, 0x73, 0x96, 0x3d, 0xa3, 0x06, 0x00, 0x00,
+}
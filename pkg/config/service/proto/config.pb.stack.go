@@ -0,0 +1,236 @@
+// Code generated by protoc-gen-stack. DO NOT EDIT.
+// source: config.proto
+
+package stack_rpc_config
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+import (
+	context "context"
+	api "github.com/stack-labs/stack/api"
+	client "github.com/stack-labs/stack/client"
+	server "github.com/stack-labs/stack/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Source service
+
+func NewSourceEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Source service
+
+type SourceService interface {
+	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Source_WatchService, error)
+	Write(ctx context.Context, in *WriteRequest, opts ...client.CallOption) (*WriteResponse, error)
+	Rollback(ctx context.Context, in *RollbackRequest, opts ...client.CallOption) (*RollbackResponse, error)
+	History(ctx context.Context, in *HistoryRequest, opts ...client.CallOption) (*HistoryResponse, error)
+}
+
+type sourceService struct {
+	c    client.Client
+	name string
+}
+
+func NewSourceService(name string, c client.Client) SourceService {
+	return &sourceService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *sourceService) Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error) {
+	req := c.c.NewRequest(c.name, "Source.Read", in)
+	out := new(ReadResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sourceService) Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Source_WatchService, error) {
+	req := c.c.NewRequest(c.name, "Source.Watch", &WatchRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &sourceServiceWatch{stream}, nil
+}
+
+type Source_WatchService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*WatchResponse, error)
+}
+
+type sourceServiceWatch struct {
+	stream client.Stream
+}
+
+func (x *sourceServiceWatch) Close() error {
+	return x.stream.Close()
+}
+
+func (x *sourceServiceWatch) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *sourceServiceWatch) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *sourceServiceWatch) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *sourceServiceWatch) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sourceService) Write(ctx context.Context, in *WriteRequest, opts ...client.CallOption) (*WriteResponse, error) {
+	req := c.c.NewRequest(c.name, "Source.Write", in)
+	out := new(WriteResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sourceService) Rollback(ctx context.Context, in *RollbackRequest, opts ...client.CallOption) (*RollbackResponse, error) {
+	req := c.c.NewRequest(c.name, "Source.Rollback", in)
+	out := new(RollbackResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sourceService) History(ctx context.Context, in *HistoryRequest, opts ...client.CallOption) (*HistoryResponse, error) {
+	req := c.c.NewRequest(c.name, "Source.History", in)
+	out := new(HistoryResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Source service
+
+type SourceHandler interface {
+	Read(context.Context, *ReadRequest, *ReadResponse) error
+	Watch(context.Context, *WatchRequest, Source_WatchStream) error
+	Write(context.Context, *WriteRequest, *WriteResponse) error
+	Rollback(context.Context, *RollbackRequest, *RollbackResponse) error
+	History(context.Context, *HistoryRequest, *HistoryResponse) error
+}
+
+func RegisterSourceHandler(s server.Server, hdlr SourceHandler, opts ...server.HandlerOption) error {
+	type source interface {
+		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
+		Watch(ctx context.Context, stream server.Stream) error
+		Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error
+		Rollback(ctx context.Context, in *RollbackRequest, out *RollbackResponse) error
+		History(ctx context.Context, in *HistoryRequest, out *HistoryResponse) error
+	}
+	type Source struct {
+		source
+	}
+	h := &sourceHandler{hdlr}
+	return s.Handle(s.NewHandler(&Source{h}, opts...))
+}
+
+type sourceHandler struct {
+	SourceHandler
+}
+
+func (h *sourceHandler) Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error {
+	return h.SourceHandler.Read(ctx, in, out)
+}
+
+func (h *sourceHandler) Watch(ctx context.Context, stream server.Stream) error {
+	m := new(WatchRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.SourceHandler.Watch(ctx, m, &sourceWatchStream{stream})
+}
+
+type Source_WatchStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*WatchResponse) error
+}
+
+type sourceWatchStream struct {
+	stream server.Stream
+}
+
+func (x *sourceWatchStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *sourceWatchStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *sourceWatchStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *sourceWatchStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *sourceWatchStream) Send(m *WatchResponse) error {
+	return x.stream.Send(m)
+}
+
+func (h *sourceHandler) Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error {
+	return h.SourceHandler.Write(ctx, in, out)
+}
+
+func (h *sourceHandler) Rollback(ctx context.Context, in *RollbackRequest, out *RollbackResponse) error {
+	return h.SourceHandler.Rollback(ctx, in, out)
+}
+
+func (h *sourceHandler) History(ctx context.Context, in *HistoryRequest, out *HistoryResponse) error {
+	return h.SourceHandler.History(ctx, in, out)
+}
@@ -9,6 +9,7 @@ import (
 
 type serviceNameKey struct{}
 type pathKey struct{}
+type namespaceKey struct{}
 type clientKey struct{}
 
 func ServiceName(a string) source.Option {
@@ -30,6 +31,16 @@ func Path(p string) source.Option {
 	}
 }
 
+// Namespace sets the namespace of the config, e.g. the environment
+func Namespace(n string) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, namespaceKey{}, n)
+	}
+}
+
 func Client(c client.Client) source.Option {
 	return func(o *source.Options) {
 		if o.Context == nil {
@@ -92,7 +92,9 @@ func (m *ChangeSet) GetTimestamp() int64 {
 }
 
 type ReadRequest struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// namespace of the config, e.g. the environment
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -130,6 +132,13 @@ func (m *ReadRequest) GetPath() string {
 	return ""
 }
 
+func (m *ReadRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
 type ReadResponse struct {
 	ChangeSet            *ChangeSet `protobuf:"bytes,1,opt,name=change_set,json=changeSet,proto3" json:"change_set,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -171,6 +180,7 @@ func (m *ReadResponse) GetChangeSet() *ChangeSet {
 
 type WatchRequest struct {
 	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -208,6 +218,13 @@ func (m *WatchRequest) GetPath() string {
 	return ""
 }
 
+func (m *WatchRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
 type WatchResponse struct {
 	ChangeSet            *ChangeSet `protobuf:"bytes,1,opt,name=change_set,json=changeSet,proto3" json:"change_set,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -258,22 +275,22 @@ func init() {
 func init() { proto.RegisterFile("mucp.proto", fileDescriptor_ba1de113e2ebd750) }
 
 var fileDescriptor_ba1de113e2ebd750 = []byte{
-	// 260 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0x31, 0x4f, 0xc3, 0x30,
-	0x14, 0x84, 0x6b, 0x9a, 0x46, 0xe4, 0x35, 0x61, 0xf0, 0x80, 0xac, 0x88, 0x21, 0x58, 0x42, 0x0a,
-	0x0c, 0x16, 0x2a, 0x13, 0xac, 0xfc, 0x03, 0x77, 0x60, 0x60, 0x40, 0xc6, 0x7d, 0x10, 0x84, 0x5c,
-	0x9b, 0xd8, 0xf9, 0x11, 0xfc, 0x6b, 0xd4, 0x97, 0x52, 0xc2, 0x82, 0xc4, 0x76, 0x77, 0x39, 0xbd,
-	0x7c, 0x27, 0x03, 0xb8, 0xc1, 0x06, 0x15, 0x7a, 0x9f, 0xbc, 0xfc, 0x64, 0x50, 0xdc, 0x77, 0x66,
-	0xfb, 0x8a, 0x6b, 0x4c, 0x9c, 0x43, 0xb6, 0x31, 0xc9, 0x08, 0xd6, 0xb0, 0xb6, 0xd4, 0xa4, 0x79,
-	0x0d, 0xc7, 0xb6, 0x43, 0xfb, 0x1e, 0x07, 0x27, 0x8e, 0x1a, 0xd6, 0x16, 0xfa, 0xe0, 0xf9, 0x29,
-	0xe4, 0x2f, 0xbe, 0x77, 0x26, 0x89, 0x39, 0x7d, 0xd9, 0xbb, 0x5d, 0x1e, 0xfd, 0xd0, 0x5b, 0x14,
-	0xd9, 0x98, 0x8f, 0x8e, 0x9f, 0x41, 0x91, 0xde, 0x1c, 0xc6, 0x64, 0x5c, 0x10, 0x8b, 0x86, 0xb5,
-	0x73, 0xfd, 0x13, 0xc8, 0x73, 0x58, 0x6a, 0x34, 0x1b, 0x8d, 0x1f, 0x03, 0x46, 0x82, 0x09, 0x26,
-	0x75, 0x04, 0x53, 0x68, 0xd2, 0xf2, 0x16, 0xca, 0xb1, 0x12, 0x83, 0xdf, 0x46, 0xe4, 0x97, 0x00,
-	0x96, 0xe8, 0x9f, 0x22, 0x26, 0x6a, 0x2e, 0x57, 0xa0, 0x0e, 0x83, 0x74, 0x61, 0xbf, 0xa5, 0x94,
-	0x50, 0x3e, 0x98, 0x64, 0xbb, 0xbf, 0xce, 0xdf, 0x41, 0xb5, 0xef, 0xfc, 0xfb, 0xfe, 0xea, 0x11,
-	0xf2, 0xf5, 0xb8, 0xf2, 0x02, 0xb2, 0x1d, 0x24, 0x2f, 0xd5, 0x64, 0x4e, 0x5d, 0xa9, 0x29, 0xb9,
-	0x9c, 0xf1, 0x2b, 0x58, 0xd0, 0xcf, 0x78, 0xa5, 0xa6, 0x60, 0xf5, 0x89, 0xfa, 0xc5, 0x20, 0x67,
-	0xd7, 0xec, 0x39, 0xa7, 0xd7, 0xba, 0xf9, 0x0a, 0x00, 0x00, 0xff, 0xff, 0x0f, 0x39, 0x58, 0x17,
-	0xbb, 0x01, 0x00, 0x00,
+	// 269 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x51, 0x3d, 0x4f, 0xc3, 0x30,
+	0x10, 0xad, 0x69, 0x1a, 0x91, 0x6b, 0xc2, 0xe0, 0x01, 0x45, 0x11, 0x43, 0x64, 0x09, 0x29, 0x30,
+	0x58, 0xa8, 0x4c, 0xb0, 0x80, 0xc4, 0x3f, 0x70, 0x07, 0x06, 0x06, 0x74, 0xb8, 0x07, 0x41, 0xc8,
+	0x89, 0x89, 0x9d, 0x1f, 0xc1, 0xbf, 0x46, 0x71, 0x42, 0x1b, 0x46, 0xd8, 0xde, 0x7b, 0x96, 0xde,
+	0xc7, 0x19, 0xc0, 0xf4, 0xda, 0x4a, 0xdb, 0xb5, 0xbe, 0x15, 0x5f, 0x0c, 0x92, 0x87, 0x1a, 0x9b,
+	0x37, 0xda, 0x92, 0xe7, 0x1c, 0xa2, 0x1d, 0x7a, 0xcc, 0x59, 0xc9, 0xaa, 0x54, 0x05, 0xcc, 0x0b,
+	0x38, 0xd6, 0x35, 0xe9, 0x0f, 0xd7, 0x9b, 0xfc, 0xa8, 0x64, 0x55, 0xa2, 0xf6, 0x9c, 0x9f, 0x42,
+	0xfc, 0xda, 0x76, 0x06, 0x7d, 0xbe, 0x0c, 0x2f, 0x13, 0x1b, 0x74, 0xd7, 0xf6, 0x9d, 0xa6, 0x3c,
+	0x1a, 0xf5, 0x91, 0xf1, 0x33, 0x48, 0xfc, 0xbb, 0x21, 0xe7, 0xd1, 0xd8, 0x7c, 0x55, 0xb2, 0x6a,
+	0xa9, 0x0e, 0x82, 0xb8, 0x83, 0xb5, 0x22, 0xdc, 0x29, 0xfa, 0xec, 0xc9, 0x85, 0x32, 0x16, 0x7d,
+	0x1d, 0xca, 0x24, 0x2a, 0xe0, 0xc1, 0xa0, 0x41, 0x43, 0xce, 0xa2, 0xa6, 0xa9, 0xcd, 0x41, 0x10,
+	0x37, 0x90, 0x8e, 0x06, 0xce, 0xb6, 0x8d, 0x23, 0x7e, 0x01, 0xa0, 0xc3, 0xb6, 0x67, 0x47, 0x3e,
+	0xf8, 0xac, 0x37, 0x20, 0xf7, 0x73, 0x55, 0xa2, 0x7f, 0xa0, 0xb8, 0x87, 0xf4, 0x11, 0xbd, 0xae,
+	0xff, 0x1f, 0x7e, 0x0b, 0xd9, 0xe4, 0xf0, 0xe7, 0xf4, 0xcd, 0x13, 0xc4, 0xdb, 0xf1, 0x42, 0xe7,
+	0x10, 0x0d, 0x13, 0x78, 0x2a, 0x67, 0xa7, 0x28, 0x32, 0x39, 0xdf, 0x25, 0x16, 0xfc, 0x12, 0x56,
+	0x21, 0x8c, 0x67, 0x72, 0x5e, 0xbb, 0x38, 0x91, 0xbf, 0x3a, 0x88, 0xc5, 0x15, 0x7b, 0x89, 0xc3,
+	0x4f, 0x5f, 0x7f, 0x0f, 0x00, 0x37, 0x48, 0x96, 0xb6, 0xf7, 0x01, 0x00, 0x00,
 }
@@ -17,12 +17,13 @@ var (
 type mucpSource struct {
 	serviceName string
 	path        string
+	namespace   string
 	opts        source.Options
 	client      proto.SourceService
 }
 
 func (m *mucpSource) Read() (set *source.ChangeSet, err error) {
-	req, err := m.client.Read(context.Background(), &proto.ReadRequest{Path: m.path})
+	req, err := m.client.Read(context.Background(), &proto.ReadRequest{Path: m.path, Namespace: m.namespace})
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +32,7 @@ func (m *mucpSource) Read() (set *source.ChangeSet, err error) {
 }
 
 func (m *mucpSource) Watch() (w source.Watcher, err error) {
-	stream, err := m.client.Watch(context.Background(), &proto.WatchRequest{Path: m.path})
+	stream, err := m.client.Watch(context.Background(), &proto.WatchRequest{Path: m.path, Namespace: m.namespace})
 	if err != nil {
 		log.Error("watch err: ", err)
 		return
@@ -56,6 +57,7 @@ func NewSource(opts ...source.Option) source.Source {
 
 	addr := DefaultServiceName
 	path := DefaultPath
+	var namespace string
 	var cli client.Client
 
 	if options.Context != nil {
@@ -67,6 +69,10 @@ func NewSource(opts ...source.Option) source.Source {
 		if ok {
 			path = p
 		}
+		n, ok := options.Context.Value(namespaceKey{}).(string)
+		if ok {
+			namespace = n
+		}
 
 		c, ok := options.Context.Value(clientKey{}).(client.Client)
 		if ok {
@@ -81,6 +87,7 @@ func NewSource(opts ...source.Option) source.Source {
 	s := &mucpSource{
 		serviceName: addr,
 		path:        path,
+		namespace:   namespace,
 		opts:        options,
 		client:      proto.NewSourceService(addr, cli),
 	}
@@ -32,22 +32,25 @@ func (l *logrusLogPlugin) Name() string {
 
 func (l *logrusLogPlugin) Options() []logger.Option {
 	var opts []logger.Option
-	lc := options.Stack.Logger.Logrus
-	opts = append(opts, SplitLevel(lc.SplitLevel))
-	opts = append(opts, ReportCaller(lc.ReportCaller))
-	opts = append(opts, WithoutKey(lc.WithoutKey))
-	opts = append(opts, WithoutQuote(lc.WithoutQuote))
-
-	if len(lc.TimestampFormat) > 0 {
-		opts = append(opts, TimestampFormat(lc.TimestampFormat))
-	}
-
-	switch lc.Formatter {
-	case "text":
-		opts = append(opts, TextFormatter(new(logrus.TextFormatter)))
-	case "json":
-		opts = append(opts, JSONFormatter(new(logrus.JSONFormatter)))
-	}
+
+	config.Read(&options, func() {
+		lc := options.Stack.Logger.Logrus
+		opts = append(opts, SplitLevel(lc.SplitLevel))
+		opts = append(opts, ReportCaller(lc.ReportCaller))
+		opts = append(opts, WithoutKey(lc.WithoutKey))
+		opts = append(opts, WithoutQuote(lc.WithoutQuote))
+
+		if len(lc.TimestampFormat) > 0 {
+			opts = append(opts, TimestampFormat(lc.TimestampFormat))
+		}
+
+		switch lc.Formatter {
+		case "text":
+			opts = append(opts, TextFormatter(new(logrus.TextFormatter)))
+		case "json":
+			opts = append(opts, JSONFormatter(new(logrus.JSONFormatter)))
+		}
+	})
 
 	return opts
 }
@@ -1,6 +1,7 @@
 package etcd
 
 import (
+	"github.com/stack-labs/stack/config"
 	"github.com/stack-labs/stack/registry"
 )
 
@@ -25,11 +26,14 @@ func (c *etcdRegistryPlugin) Name() string {
 
 func (c *etcdRegistryPlugin) Options() []registry.Option {
 	var opts []registry.Option
-	ec := options.Stack.Registry.Etcd
 
-	if len(ec.AuthCreds.Username) > 0 {
-		opts = append(opts, Auth(ec.AuthCreds.Username, ec.AuthCreds.Password))
-	}
+	config.Read(&options, func() {
+		ec := options.Stack.Registry.Etcd
+
+		if len(ec.AuthCreds.Username) > 0 {
+			opts = append(opts, Auth(ec.AuthCreds.Username, ec.AuthCreds.Password))
+		}
+	})
 
 	return opts
 }
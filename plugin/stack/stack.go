@@ -5,6 +5,7 @@ import (
 	"github.com/stack-labs/stack/plugin"
 )
 
+// options are rebound as the config changes, read them under cfg.Read
 var options struct {
 	Stack struct {
 		Service struct {
@@ -35,6 +36,7 @@ func init() {
 	plugin.SelectorPlugins["dns"] = &dnsSelectorPlugin{}
 	plugin.SelectorPlugins["cache"] = &cacheSelectorPlugin{}
 	plugin.SelectorPlugins["static"] = &staticSelectorPlugin{}
+	plugin.BrokerPlugins["file"] = &fileBrokerPlugin{}
 	plugin.BrokerPlugins["memory"] = &memoryBrokerPlugin{}
 	plugin.BrokerPlugins["http"] = &httpBrokerPlugin{}
 	plugin.BrokerPlugins["service"] = &serviceBrokerPlugin{}
@@ -28,6 +28,8 @@ func stackError(err *errors.Error) codes.Code {
 		return codes.PermissionDenied
 	case http.StatusUnauthorized:
 		return codes.Unauthenticated
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
 	case http.StatusPreconditionFailed:
 		return codes.FailedPrecondition
 	case http.StatusNotImplemented:
@@ -632,7 +632,7 @@ func (g *grpcServer) Register() error {
 	g.registered = true
 
 	for sb := range g.subscribers {
-		handler := g.createSubHandler(sb, g.opts)
+		handler := server.RetryHandler(config.Broker, sb, g.createSubHandler(sb, g.opts))
 		var opts []broker.SubscribeOption
 		if queue := sb.Options().Queue; len(queue) > 0 {
 			opts = append(opts, broker.Queue(queue))
@@ -750,9 +750,14 @@ func (g *grpcServer) Start() error {
 
 	log.Logf("Broker [%s] Connected to %s", bname, baddr)
 
-	// announce self to the world
-	if err := g.Register(); err != nil {
-		log.Log("Server register error: ", err)
+	// use RegisterCheck func before register
+	if err := config.RegisterCheck(config.Context); err != nil {
+		log.Logf("Server %s-%s register check error: %s", config.Name, config.Id, err)
+	} else {
+		// announce self to the world
+		if err := g.Register(); err != nil {
+			log.Log("Server register error: ", err)
+		}
 	}
 
 	// stack: go ts.Accept(s.accept)
@@ -779,8 +784,19 @@ func (g *grpcServer) Start() error {
 			select {
 			// register self on interval
 			case <-t.C:
-				if err := g.Register(); err != nil {
-					log.Log("Server register error: ", err)
+				g.RLock()
+				registered := g.registered
+				g.RUnlock()
+				if err := config.RegisterCheck(config.Context); err != nil && registered {
+					log.Logf("Server %s-%s register check error: %s, deregister it", config.Name, config.Id, err)
+					// deregister self in case of error
+					if err := g.Deregister(); err != nil {
+						log.Logf("Server %s-%s deregister error: %s", config.Name, config.Id, err)
+					}
+				} else if err == nil {
+					if err := g.Register(); err != nil {
+						log.Log("Server register error: ", err)
+					}
 				}
 			// wait for exit
 			case ch = <-g.exit:
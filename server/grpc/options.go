@@ -107,5 +107,9 @@ func newOptions(opt ...server.Option) server.Options {
 		opts.Version = server.DefaultVersion
 	}
 
+	if opts.RegisterCheck == nil {
+		opts.RegisterCheck = server.DefaultRegisterCheck
+	}
+
 	return opts
 }
@@ -1,6 +1,9 @@
 package server
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type HandlerOption func(*HandlerOptions)
 
@@ -18,6 +21,16 @@ type SubscriberOptions struct {
 	Queue    string
 	Internal bool
 	Context  context.Context
+	// MaxAttempts at handling a message before it's dead lettered,
+	// one or less doesn't retry
+	MaxAttempts int
+	// Backoff between attempts, ExponentialBackoff if not set
+	Backoff BackoffFunc
+	// RetryTimeout caps the time spent retrying a message within the
+	// delivery of the broker, DefaultRetryTimeout if not set
+	RetryTimeout time.Duration
+	// DeadLetter topic failed messages are published to
+	DeadLetter string
 }
 
 // EndpointMetadata is a Handler option that allows metadata to be added to
@@ -78,3 +91,34 @@ func SubscriberContext(ctx context.Context) SubscriberOption {
 		o.Context = ctx
 	}
 }
+
+// SubscriberMaxAttempts sets the attempts at handling a message
+// before giving up on it
+func SubscriberMaxAttempts(n int) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.MaxAttempts = n
+	}
+}
+
+// SubscriberBackoff sets the backoff between attempts at handling a message
+func SubscriberBackoff(fn BackoffFunc) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.Backoff = fn
+	}
+}
+
+// SubscriberRetryTimeout caps the time spent retrying a message, it must be
+// below the time the broker waits for a delivery to be handled
+func SubscriberRetryTimeout(d time.Duration) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.RetryTimeout = d
+	}
+}
+
+// SubscriberDeadLetter sets the topic messages are published to
+// once all the attempts at handling them failed
+func SubscriberDeadLetter(topic string) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.DeadLetter = topic
+	}
+}
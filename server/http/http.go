@@ -176,7 +176,7 @@ func (h *httpServer) Register() error {
 	h.registered = true
 
 	for sb := range h.subscribers {
-		handler := h.createSubHandler(sb, opts)
+		handler := server.RetryHandler(opts.Broker, sb, h.createSubHandler(sb, opts))
 		var subOpts []broker.SubscribeOption
 		if queue := sb.Options().Queue; len(queue) > 0 {
 			subOpts = append(subOpts, broker.Queue(queue))
@@ -251,12 +251,14 @@ func (h *httpServer) Start() error {
 		return err
 	}
 
-	// register
-	if err = h.Register(); err != nil {
+	// use RegisterCheck func before register
+	if err = opts.RegisterCheck(opts.Context); err != nil {
+		log.Errorf("Server %s-%s register check error: %s", opts.Name, opts.Id, err)
+	} else if err = h.Register(); err != nil {
 		return err
 	}
 
-	go http.Serve(ln, handler)
+	go http.Serve(ln, wrapHandler(opts, handler))
 
 	go func() {
 		t := new(time.Ticker)
@@ -275,8 +277,19 @@ func (h *httpServer) Start() error {
 			select {
 			// register self on interval
 			case <-t.C:
-				if err := h.Register(); err != nil {
-					log.Error("Server register error: ", err)
+				h.Lock()
+				registered := h.registered
+				h.Unlock()
+				if err := opts.RegisterCheck(opts.Context); err != nil && registered {
+					log.Errorf("Server %s-%s register check error: %s, deregister it", opts.Name, opts.Id, err)
+					// deregister self in case of error
+					if err := h.Deregister(); err != nil {
+						log.Errorf("Server %s-%s deregister error: %s", opts.Name, opts.Id, err)
+					}
+				} else if err == nil {
+					if err := h.Register(); err != nil {
+						log.Error("Server register error: ", err)
+					}
 				}
 			// wait for exit
 			case ch = <-h.exit:
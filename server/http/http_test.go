@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	hb "github.com/stack-labs/stack/broker/http"
 	"github.com/stack-labs/stack/registry/memory"
 	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
 )
 
 func TestHTTPServer(t *testing.T) {
@@ -71,3 +73,100 @@ func TestHTTPServer(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestHTTPServerWrapper(t *testing.T) {
+	reg := memory.NewRegistry()
+
+	// reject anything without a token
+	wrapper := func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if len(req.Header()["Authorization"]) == 0 {
+				return errors.Unauthorized(req.Service(), "missing token for %s", req.Endpoint())
+			}
+			return h(ctx, req, rsp)
+		}
+	}
+
+	srv := NewServer(server.Broker(hb.NewBroker()), server.Registry(reg), WrapHTTPHandler(wrapper))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`hello world`))
+	})
+
+	if err := srv.Handle(srv.NewHandler(mux)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	addr := srv.Options().Address
+
+	rsp, err := http.Get(fmt.Sprintf("http://%s/foo", addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected status %d got %d", http.StatusUnauthorized, rsp.StatusCode)
+	}
+
+	req, _ := http.NewRequest("GET", fmt.Sprintf("http://%s/foo", addr), nil)
+	req.Header.Set("Authorization", "Bearer foo")
+
+	rsp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+
+	b, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := string(b); s != "hello world" {
+		t.Fatalf("Expected response %s, got %s", "hello world", s)
+	}
+}
+
+func TestHTTPServerWrapperOptIn(t *testing.T) {
+	reg := memory.NewRegistry()
+
+	// the rpc handler wrappers are not applied to plain http handlers
+	wrapper := func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			return errors.Unauthorized(req.Service(), "missing token for %s", req.Endpoint())
+		}
+	}
+
+	srv := NewServer(server.Broker(hb.NewBroker()), server.Registry(reg), server.WrapHandler(wrapper))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`hello world`))
+	})
+
+	if err := srv.Handle(srv.NewHandler(mux)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	rsp, err := http.Get(fmt.Sprintf("http://%s/foo", srv.Options().Address))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d got %d", http.StatusOK, rsp.StatusCode)
+	}
+}
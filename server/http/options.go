@@ -34,5 +34,33 @@ func newOptions(opt ...server.Option) server.Options {
 		opts.Version = server.DefaultVersion
 	}
 
+	if opts.RegisterCheck == nil {
+		opts.RegisterCheck = server.DefaultRegisterCheck
+	}
+
 	return opts
 }
+
+type httpWrappersKey struct{}
+
+// WrapHTTPHandler adds a handler wrapper applied to the plain http.Handlers
+// served by the http server. The wrappers of server.WrapHandler are not
+// applied to them, a wrapper such as the auth one has to be added with both.
+func WrapHTTPHandler(w server.HandlerWrapper) server.Option {
+	return func(o *server.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		wrappers := append(httpWrappers(*o), w)
+		o.Context = context.WithValue(o.Context, httpWrappersKey{}, wrappers)
+	}
+}
+
+func httpWrappers(o server.Options) []server.HandlerWrapper {
+	if o.Context == nil {
+		return nil
+	}
+	w, _ := o.Context.Value(httpWrappersKey{}).([]server.HandlerWrapper)
+	// copy so options applied to other servers don't share the slice
+	return append([]server.HandlerWrapper(nil), w...)
+}
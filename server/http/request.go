@@ -0,0 +1,118 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/stack-labs/stack/codec"
+	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+// httpRequest exposes a http request as a server.Request
+// so handler wrappers can be applied to it
+type httpRequest struct {
+	service string
+	r       *http.Request
+	header  map[string]string
+}
+
+func newRequest(service string, r *http.Request) *httpRequest {
+	header := make(map[string]string, len(r.Header))
+	for k := range r.Header {
+		header[k] = r.Header.Get(k)
+	}
+
+	return &httpRequest{
+		service: service,
+		r:       r,
+		header:  header,
+	}
+}
+
+func (r *httpRequest) Service() string {
+	return r.service
+}
+
+func (r *httpRequest) Method() string {
+	return r.r.Method
+}
+
+func (r *httpRequest) Endpoint() string {
+	return r.r.URL.Path
+}
+
+func (r *httpRequest) ContentType() string {
+	return r.r.Header.Get("Content-Type")
+}
+
+func (r *httpRequest) Header() map[string]string {
+	return r.header
+}
+
+func (r *httpRequest) Body() interface{} {
+	return r.r.Body
+}
+
+func (r *httpRequest) Read() ([]byte, error) {
+	if r.r.Body == nil {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadAll(r.r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.r.Body.Close()
+
+	// leave the body in place for the handler
+	r.r.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
+
+func (r *httpRequest) Codec() codec.Reader {
+	return nil
+}
+
+func (r *httpRequest) Stream() bool {
+	return false
+}
+
+// wrapHandler applies the WrapHTTPHandler wrappers to a http.Handler. The
+// request headers are passed to the wrappers as metadata and an error
+// returned by a wrapper is written as the response.
+func wrapHandler(opts server.Options, hd http.Handler) http.Handler {
+	wrappers := httpWrappers(opts)
+	if len(wrappers) == 0 {
+		return hd
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := newRequest(opts.Name, r)
+
+		handler := func(ctx context.Context, req server.Request, rsp interface{}) error {
+			hd.ServeHTTP(rsp.(http.ResponseWriter), r.WithContext(ctx))
+			return nil
+		}
+
+		for i := len(wrappers); i > 0; i-- {
+			handler = wrappers[i-1](handler)
+		}
+
+		ctx := metadata.NewContext(r.Context(), metadata.Copy(req.header))
+		if err := handler(ctx, req, w); err != nil {
+			e := errors.Parse(err.Error())
+			code := int(e.Code)
+			if code == 0 {
+				code = http.StatusInternalServerError
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			w.Write([]byte(e.Error()))
+		}
+	})
+}
@@ -644,7 +644,9 @@ func (s *rpcServer) Register() error {
 			opts = append(opts, broker.DisableAutoAck())
 		}
 
-		sub, err := config.Broker.Subscribe(sb.Topic(), s.HandleEvent, opts...)
+		handler := server.RetryHandler(config.Broker, sb, s.HandleEvent)
+
+		sub, err := config.Broker.Subscribe(sb.Topic(), handler, opts...)
 		if err != nil {
 			return err
 		}
@@ -831,7 +833,7 @@ func (s *rpcServer) Start() error {
 					if err := s.Deregister(); err != nil {
 						log.Logf("Server %s-%s deregister error: %s", config.Name, config.Id, err)
 					}
-				} else {
+				} else if err == nil {
 					if err := s.Register(); err != nil {
 						log.Logf("Server %s-%s register error: %s", config.Name, config.Id, err)
 					}
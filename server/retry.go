@@ -0,0 +1,168 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/util/log"
+)
+
+const (
+	// DeadLetterTopicHeader is the topic a dead lettered message was published to
+	DeadLetterTopicHeader = "Stack-Dead-Letter-Topic"
+	// DeadLetterQueueHeader is the queue of the subscriber that failed
+	DeadLetterQueueHeader = "Stack-Dead-Letter-Queue"
+	// DeadLetterErrorHeader is the error of the last attempt
+	DeadLetterErrorHeader = "Stack-Dead-Letter-Error"
+	// DeadLetterAttemptsHeader is the number of attempts made
+	DeadLetterAttemptsHeader = "Stack-Dead-Letter-Attempts"
+	// DeadLetterTimeHeader is the time the message was dead lettered in RFC3339
+	DeadLetterTimeHeader = "Stack-Dead-Letter-Time"
+)
+
+var (
+	// DefaultRetryTimeout caps the time spent retrying a message, below the 30
+	// seconds the http broker publisher and the file broker ack wait for
+	DefaultRetryTimeout = 20 * time.Second
+
+	// ErrNotDeadLetter is returned replaying a message that wasn't dead lettered
+	ErrNotDeadLetter = errors.New("not a dead lettered message")
+
+	deadLetterHeaders = []string{
+		DeadLetterTopicHeader,
+		DeadLetterQueueHeader,
+		DeadLetterErrorHeader,
+		DeadLetterAttemptsHeader,
+		DeadLetterTimeHeader,
+	}
+)
+
+// BackoffFunc returns the time to wait after the failed attempt
+// at handling a message, attempts start at 1
+type BackoffFunc func(attempts int) time.Duration
+
+// ExponentialBackoff is a function x^e multiplied by a factor of 0.1 second.
+// Result is limited to 2 minute.
+func ExponentialBackoff(attempts int) time.Duration {
+	if attempts > 13 {
+		return 2 * time.Minute
+	}
+	return time.Duration(math.Pow(float64(attempts), math.E)) * time.Millisecond * 100
+}
+
+// RetryHandler wraps the broker handler of a subscriber with its retry policy.
+// A message is handled up to MaxAttempts times, waiting Backoff between attempts,
+// then published to the DeadLetter topic with the failure in its headers.
+// Retries block the delivery of the broker, they're given up once the next
+// backoff would go past the RetryTimeout.
+// Once dead lettered a message is acked, otherwise the error is returned
+// to the broker as before.
+func RetryHandler(b broker.Broker, sb Subscriber, h broker.Handler) broker.Handler {
+	opts := sb.Options()
+
+	if opts.MaxAttempts <= 1 && len(opts.DeadLetter) == 0 {
+		return h
+	}
+
+	backoff := opts.Backoff
+	if backoff == nil {
+		backoff = ExponentialBackoff
+	}
+
+	timeout := opts.RetryTimeout
+	if timeout <= 0 {
+		timeout = DefaultRetryTimeout
+	}
+
+	return func(e broker.Event) error {
+		// keep the original as handlers may change the headers
+		msg := &broker.Message{
+			Header: make(map[string]string),
+			Body:   e.Message().Body,
+		}
+		for k, v := range e.Message().Header {
+			msg.Header[k] = v
+		}
+
+		var err error
+		var attempts int
+
+		deadline := time.Now().Add(timeout)
+
+		for {
+			attempts++
+			if err = h(e); err == nil {
+				return nil
+			}
+			if attempts >= opts.MaxAttempts {
+				break
+			}
+
+			wait := backoff(attempts)
+			if time.Now().Add(wait).After(deadline) {
+				break
+			}
+
+			if opts.Context == nil {
+				time.Sleep(wait)
+				continue
+			}
+
+			t := time.NewTimer(wait)
+			select {
+			case <-t.C:
+			case <-opts.Context.Done():
+				t.Stop()
+				return err
+			}
+		}
+
+		if len(opts.DeadLetter) == 0 {
+			return err
+		}
+
+		msg.Header[DeadLetterTopicHeader] = e.Topic()
+		msg.Header[DeadLetterQueueHeader] = opts.Queue
+		msg.Header[DeadLetterErrorHeader] = err.Error()
+		msg.Header[DeadLetterAttemptsHeader] = strconv.Itoa(attempts)
+		msg.Header[DeadLetterTimeHeader] = time.Now().UTC().Format(time.RFC3339)
+
+		if perr := b.Publish(opts.DeadLetter, msg); perr != nil {
+			log.Logf("[server] failed to dead letter message of %s to %s: %v", e.Topic(), opts.DeadLetter, perr)
+			return err
+		}
+
+		log.Logf("[server] dead lettered message of %s to %s after %d attempts: %v", e.Topic(), opts.DeadLetter, attempts, err)
+
+		if !opts.AutoAck {
+			return e.Ack()
+		}
+
+		return nil
+	}
+}
+
+// Replay returns the topic a dead lettered message was published to
+// and the original message without the dead letter headers
+func Replay(msg *broker.Message) (string, *broker.Message, error) {
+	topic := msg.Header[DeadLetterTopicHeader]
+	if len(topic) == 0 {
+		return "", nil, ErrNotDeadLetter
+	}
+
+	m := &broker.Message{
+		Header: make(map[string]string),
+		Body:   msg.Body,
+	}
+	for k, v := range msg.Header {
+		m.Header[k] = v
+	}
+	for _, k := range deadLetterHeaders {
+		delete(m.Header, k)
+	}
+
+	return topic, m, nil
+}
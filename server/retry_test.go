@@ -0,0 +1,170 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/broker/memory"
+	"github.com/stack-labs/stack/registry"
+)
+
+type testSubscriber struct {
+	topic string
+	opts  SubscriberOptions
+}
+
+func (t *testSubscriber) Topic() string                   { return t.topic }
+func (t *testSubscriber) Subscriber() interface{}         { return nil }
+func (t *testSubscriber) Endpoints() []*registry.Endpoint { return nil }
+func (t *testSubscriber) Options() SubscriberOptions      { return t.opts }
+
+func TestRetryHandler(t *testing.T) {
+	b := memory.NewBroker()
+	if err := b.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	sb := &testSubscriber{
+		topic: "test",
+		opts: NewSubscriberOptions(
+			SubscriberQueue("q"),
+			SubscriberMaxAttempts(3),
+			SubscriberBackoff(func(int) time.Duration { return time.Millisecond }),
+			SubscriberDeadLetter("test.dlq"),
+		),
+	}
+
+	var attempts int
+	fail := func(e broker.Event) error {
+		attempts++
+		e.Message().Header["Changed"] = "true"
+		return errors.New("boom")
+	}
+
+	dead := make(chan *broker.Message, 1)
+	if _, err := b.Subscribe("test.dlq", func(e broker.Event) error {
+		dead <- e.Message()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Subscribe(sb.Topic(), RetryHandler(b, sb, fail)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Publish("test", &broker.Message{
+		Header: map[string]string{"Id": "1"},
+		Body:   []byte("hello"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var msg *broker.Message
+	select {
+	case msg = <-dead:
+	case <-time.After(time.Second):
+		t.Fatal("message not dead lettered")
+	}
+
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts got %d", attempts)
+	}
+
+	expected := map[string]string{
+		"Id":                     "1",
+		DeadLetterTopicHeader:    "test",
+		DeadLetterQueueHeader:    "q",
+		DeadLetterErrorHeader:    "boom",
+		DeadLetterAttemptsHeader: "3",
+	}
+	for k, v := range expected {
+		if msg.Header[k] != v {
+			t.Fatalf("expected header %s %q got %q", k, v, msg.Header[k])
+		}
+	}
+	if _, ok := msg.Header["Changed"]; ok {
+		t.Fatal("expected the original headers to be dead lettered")
+	}
+	if _, err := time.Parse(time.RFC3339, msg.Header[DeadLetterTimeHeader]); err != nil {
+		t.Fatalf("unexpected time header: %v", err)
+	}
+
+	topic, orig, err := Replay(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topic != "test" || string(orig.Body) != "hello" {
+		t.Fatalf("unexpected replay of %s: %s", topic, orig.Body)
+	}
+	if len(orig.Header) != 1 || orig.Header["Id"] != "1" {
+		t.Fatalf("unexpected replay headers %v", orig.Header)
+	}
+
+	if _, _, err := Replay(orig); err != ErrNotDeadLetter {
+		t.Fatalf("expected %v got %v", ErrNotDeadLetter, err)
+	}
+}
+
+func TestRetryHandlerRecovers(t *testing.T) {
+	sb := &testSubscriber{
+		topic: "test",
+		opts: NewSubscriberOptions(
+			SubscriberMaxAttempts(3),
+			SubscriberBackoff(func(int) time.Duration { return 0 }),
+		),
+	}
+
+	var attempts int
+	h := RetryHandler(nil, sb, func(e broker.Event) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	b := memory.NewBroker()
+	b.Connect()
+	b.Subscribe("test", h)
+
+	if err := b.Publish("test", &broker.Message{Header: map[string]string{}}); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts got %d", attempts)
+	}
+}
+
+func TestRetryHandlerTimeout(t *testing.T) {
+	sb := &testSubscriber{
+		topic: "test",
+		opts: NewSubscriberOptions(
+			SubscriberMaxAttempts(10),
+			SubscriberBackoff(func(int) time.Duration { return time.Millisecond * 30 }),
+			SubscriberRetryTimeout(time.Millisecond*50),
+		),
+	}
+
+	var attempts int
+	h := RetryHandler(nil, sb, func(e broker.Event) error {
+		attempts++
+		return errors.New("boom")
+	})
+
+	b := memory.NewBroker()
+	b.Connect()
+	b.Subscribe("test", h)
+
+	start := time.Now()
+	if err := b.Publish("test", &broker.Message{Header: map[string]string{}}); err == nil {
+		t.Fatal("expected the error of the last attempt")
+	}
+	if attempts != 2 {
+		t.Fatalf("expected the retries to stop at the timeout after 2 attempts got %d", attempts)
+	}
+	if d := time.Since(start); d > time.Millisecond*50 {
+		t.Fatalf("expected the retries to stop within the timeout took %v", d)
+	}
+}
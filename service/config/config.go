@@ -1,7 +1,10 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"strings"
 	"time"
 
@@ -10,6 +13,11 @@ import (
 	cl "github.com/stack-labs/stack/client"
 	sel "github.com/stack-labs/stack/client/selector"
 	cfg "github.com/stack-labs/stack/config"
+	"github.com/stack-labs/stack/debug/metrics"
+	"github.com/stack-labs/stack/debug/trace"
+	"github.com/stack-labs/stack/debug/trace/memory"
+	"github.com/stack-labs/stack/debug/trace/otlp"
+	"github.com/stack-labs/stack/debug/trace/zipkin"
 	lg "github.com/stack-labs/stack/logger"
 	"github.com/stack-labs/stack/plugin"
 	reg "github.com/stack-labs/stack/registry"
@@ -18,6 +26,8 @@ import (
 	sw "github.com/stack-labs/stack/service/web"
 	tra "github.com/stack-labs/stack/transport"
 	"github.com/stack-labs/stack/util/log"
+	"github.com/stack-labs/stack/util/ratelimit"
+	"github.com/stack-labs/stack/util/wrapper"
 )
 
 var (
@@ -30,8 +40,8 @@ func init() {
 }
 
 type Config struct {
-	HierarchyMerge bool `json:"hierarchyMerge" sc:"hierarchy-merge"`
-	Storage        bool `json:"storage" sc:"storage"`
+	HierarchyMerge bool `json:"hierarchyMerge" sc:"hierarchy-merge" usage:"merge the values of the sources key by key instead of replacing them"`
+	Storage        bool `json:"storage" sc:"storage" usage:"keep a copy of the config to start from when the sources are unavailable"`
 }
 
 func (c *Config) Options() []cfg.Option {
@@ -44,8 +54,8 @@ func (c *Config) Options() []cfg.Option {
 }
 
 type Broker struct {
-	Address string `json:"address" sc:"address"`
-	Name    string `json:"name" sc:"name"`
+	Address string `json:"address" sc:"address" usage:"comma separated addresses of the broker, the dir of the file broker"`
+	Name    string `json:"name" sc:"name" usage:"broker plugin, eg. http, memory, file, service"`
 }
 
 func (b *Broker) Options() []br.Option {
@@ -65,18 +75,18 @@ func (b *Broker) Options() []br.Option {
 }
 
 type pool struct {
-	Size int `json:"size" sc:"size"`
-	TTL  int `json:"ttl" sc:"ttl"`
+	Size int `json:"size" sc:"size" usage:"connections kept per address" validate:"min=0"`
+	TTL  int `json:"ttl" sc:"ttl" usage:"seconds a pooled connection is kept" validate:"min=0"`
 }
 
 type clientRequest struct {
-	Retries int    `json:"retries" sc:"retries"`
-	Timeout string `json:"timeout" sc:"timeout"`
+	Retries int    `json:"retries" sc:"retries" usage:"times a failed request is retried"`
+	Timeout string `json:"timeout" sc:"timeout" usage:"timeout of a request, eg. 5s" validate:"duration,min=0s"`
 }
 
 type Client struct {
-	Name     string        `json:"name" sc:"name"`
-	Protocol string        `json:"protocol" sc:"protocol"`
+	Name     string        `json:"name" sc:"name" usage:"client plugin"`
+	Protocol string        `json:"protocol" sc:"protocol" usage:"protocol of the client, eg. mucp, grpc"`
 	Pool     pool          `json:"pool" sc:"pool"`
 	Request  clientRequest `json:"request" sc:"request"`
 }
@@ -119,8 +129,8 @@ func (c *Client) Options() []cl.Option {
 }
 
 type Registry struct {
-	Address string `json:"address" sc:"address"`
-	Name    string `json:"name" sc:"name"`
+	Address string `json:"address" sc:"address" usage:"comma separated addresses of the registry"`
+	Name    string `json:"name" sc:"name" usage:"registry plugin, eg. mdns, etcd, consul"`
 }
 
 func (r *Registry) Options() []reg.Option {
@@ -155,20 +165,20 @@ func (m metadata) Value(k string) string {
 }
 
 type Server struct {
-	Address     string         `json:"address" sc:"address"`
-	Advertise   string         `json:"advertise" sc:"advertise"`
-	ID          string         `json:"id" sc:"id"`
-	Metadata    metadata       `json:"metadata" sc:"metadata"`
-	Name        string         `json:"name" sc:"name"`
-	Protocol    string         `json:"protocol" sc:"protocol"`
-	Version     string         `json:"version" sc:"version"`
+	Address     string         `json:"address" sc:"address" usage:"address the server listens on, eg. :8080"`
+	Advertise   string         `json:"advertise" sc:"advertise" usage:"address registered instead of the listening one"`
+	ID          string         `json:"id" sc:"id" usage:"id of the server, random if not set"`
+	Metadata    metadata       `json:"metadata" sc:"metadata" usage:"key=value pairs registered with the server"`
+	Name        string         `json:"name" sc:"name" usage:"name the server registers with"`
+	Protocol    string         `json:"protocol" sc:"protocol" usage:"protocol of the server, eg. mucp, grpc"`
+	Version     string         `json:"version" sc:"version" usage:"version the server registers with"`
 	Registry    serverRegistry `json:"Registry" sc:"Registry"`
-	EnableDebug bool           `json:"enableDebug" sc:"enable-debug"`
+	EnableDebug bool           `json:"enableDebug" sc:"enable-debug" usage:"serve the debug handler"`
 }
 
 type serverRegistry struct {
-	TTL      int `json:"ttl" sc:"ttl"`
-	Interval int `json:"interval" sc:"interval"`
+	TTL      int `json:"ttl" sc:"ttl" usage:"seconds the registration lives" validate:"min=0"`
+	Interval int `json:"interval" sc:"interval" usage:"seconds between registrations" validate:"min=0"`
 }
 
 func (s *Server) Options() []ser.Option {
@@ -226,7 +236,8 @@ func (s *Server) Options() []ser.Option {
 }
 
 type Selector struct {
-	Name string `json:"name" sc:"name"`
+	Name     string `json:"name" sc:"name" usage:"selector plugin, eg. cache"`
+	Strategy string `json:"strategy" sc:"strategy" usage:"strategy picking a node of a service" validate:"enum=random|roundrobin|weighted|weighted_roundrobin|leastconn|p2c"`
 }
 
 func (s *Selector) Options() []sel.Option {
@@ -240,12 +251,32 @@ func (s *Selector) Options() []sel.Option {
 		selOptions = append(selOptions, plugin.SelectorPlugins[s.Name].Options()...)
 	}
 
+	switch s.Strategy {
+	case "":
+	case "random":
+		selOptions = append(selOptions, sel.SetStrategy(sel.Random()))
+	case "roundrobin":
+		selOptions = append(selOptions, sel.SetStrategy(sel.RoundRobin()))
+	case "weighted":
+		selOptions = append(selOptions, sel.SetStrategy(sel.WeightedRandom()))
+	case "weighted_roundrobin":
+		selOptions = append(selOptions, sel.SetStrategy(sel.WeightedRoundRobin()))
+	case "leastconn":
+		t := sel.NewTracker()
+		selOptions = append(selOptions, sel.SetStrategy(sel.LeastConn(t)), sel.Track(t))
+	case "p2c":
+		st := sel.NewStats()
+		selOptions = append(selOptions, sel.SetStrategy(sel.P2C(st)), sel.Collect(st))
+	default:
+		log.Warnf("seems you declared a selector strategy:[%s] which stack can't find out.", s.Strategy)
+	}
+
 	return selOptions
 }
 
 type Transport struct {
-	Name    string `json:"name" sc:"name"`
-	Address string `json:"address" sc:"address"`
+	Name    string `json:"name" sc:"name" usage:"transport plugin, eg. http, grpc"`
+	Address string `json:"address" sc:"address" usage:"comma separated addresses of the transport"`
 }
 
 func (t *Transport) Options() []tra.Option {
@@ -267,30 +298,30 @@ func (t *Transport) Options() []tra.Option {
 }
 
 type Logger struct {
-	Name  string `json:"name" sc:"name"`
-	Level string `json:"level" sc:"level"`
+	Name  string `json:"name" sc:"name" usage:"logger plugin, eg. console, logrus"`
+	Level string `json:"level" sc:"level" usage:"lowest level logged" validate:"enum=trace|debug|info|warn|error|fatal"`
 	// todo support map settings
 	// Fields          map[string]string `json:"fields" sc:"fields"`
-	CallerSkipCount int            `json:"caller-skip-count" sc:"caller-skip-count"`
+	CallerSkipCount int            `json:"caller-skip-count" sc:"caller-skip-count" usage:"stack frames skipped reporting the caller" validate:"min=0"`
 	Persistence     logPersistence `json:"persistence" sc:"persistence"`
 }
 
 type logPersistence struct {
-	Enable    bool   `json:"enable" sc:"enable"`
-	Dir       string `json:"dir" sc:"dir"`
-	BackupDir string `json:"backupDir" sc:"back-dir"`
+	Enable    bool   `json:"enable" sc:"enable" usage:"write the logs to files"`
+	Dir       string `json:"dir" sc:"dir" usage:"dir of the log files"`
+	BackupDir string `json:"backupDir" sc:"back-dir" usage:"dir of the rotated log files"`
 	// log file max size in megabytes
-	MaxFileSize int `json:"maxFileSize" sc:"max-file-size"`
+	MaxFileSize int `json:"maxFileSize" sc:"max-file-size" usage:"megabytes a log file is rotated at" validate:"min=0"`
 	// backup dir max size in megabytes
-	MaxBackupSize int `json:"maxBackupSize" sc:"max-backup-size"`
+	MaxBackupSize int `json:"maxBackupSize" sc:"max-backup-size" usage:"megabytes of rotated files kept" validate:"min=0"`
 	// backup files keep max days
-	MaxBackupKeepDays int `json:"maxBackupKeepDays" sc:"max-backup-keep-days"`
+	MaxBackupKeepDays int `json:"maxBackupKeepDays" sc:"max-backup-keep-days" usage:"days rotated files are kept" validate:"min=0"`
 	// default pattern is ${serviceName}_${level}.log
 	// todo available patterns map
-	FileNamePattern string `json:"fileNamePattern" sc:"file-name-pattern"`
+	FileNamePattern string `json:"fileNamePattern" sc:"file-name-pattern" usage:"name of the log files, ${serviceName}_${level}.log if not set"`
 	// default pattern is ${serviceName}_${level}_${yyyyMMdd_HH}_${idx}.zip
 	// todo available patterns map
-	BackupFileNamePattern string `json:"backupFileNamePattern" sc:"backup-file-name-pattern"`
+	BackupFileNamePattern string `json:"backupFileNamePattern" sc:"backup-file-name-pattern" usage:"name of the rotated files, ${serviceName}_${level}_${yyyyMMdd_HH}_${idx}.zip if not set"`
 }
 
 func (l *logPersistence) Options() *lg.PersistenceOptions {
@@ -339,17 +370,19 @@ func (l *Logger) Options() []lg.Option {
 }
 
 type Auth struct {
-	Name            string          `json:"name" sc:"name"`
-	Enable          bool            `json:"enable" sc:"enable"`
-	Namespace       string          `json:"namespace" sc:"namespace"`
+	Name            string          `json:"name" sc:"name" usage:"auth plugin"`
+	Enable          bool            `json:"enable" sc:"enable" usage:"verify the bearer tokens of inbound requests against the rules"`
+	Namespace       string          `json:"namespace" sc:"namespace" usage:"namespace of the accounts"`
 	AuthCredentials authCredentials `json:"authCredentials" sc:"authCredentials"`
-	PublicKey       string          `json:"publicKey" sc:"public-key"`
-	PrivateKey      string          `json:"privateKey" sc:"private-key"`
+	PublicKey       string          `json:"publicKey" sc:"public-key" usage:"key verifying the tokens"`
+	PrivateKey      string          `json:"privateKey" sc:"private-key" usage:"key signing the tokens"`
+	// Rules of the form access:scope:name:endpoint, the first has the highest priority
+	Rules []string `json:"rules" sc:"rules" usage:"rules of the form access:scope:name:endpoint, access is grant or deny" validate:"regexp=^(grant|deny):[^:]*:[^:]*:"`
 }
 
 type authCredentials struct {
-	ID     string `json:"id" sc:"id"`
-	Secret string `json:"secret" sc:"secret"`
+	ID     string `json:"id" sc:"id" usage:"id of the account of the service"`
+	Secret string `json:"secret" sc:"secret" usage:"secret of the account of the service"`
 }
 
 func (a *Auth) Options() []au.Option {
@@ -374,12 +407,177 @@ func (a *Auth) Options() []au.Option {
 	return opts
 }
 
+// Grant the configured rules, with priority in their order
+func (a *Auth) Grant(rules au.Rules) error {
+	for i, r := range a.Rules {
+		rule, err := au.ParseRule(r)
+		if err != nil {
+			return err
+		}
+		rule.Priority = int32(len(a.Rules) - i)
+		if err := rules.Grant(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wrapHandler adds handler wrappers to the service, unlike ss.WrapHandler
+// it keeps the wrappers already set
+func wrapHandler(w ...ser.HandlerWrapper) ss.Option {
+	return func(o *ss.Options) {
+		o.HandlerWrapper = append(o.HandlerWrapper, w...)
+	}
+}
+
+// wrapSubscriber adds subscriber wrappers to the service
+func wrapSubscriber(w ...ser.SubscriberWrapper) ss.Option {
+	return func(o *ss.Options) {
+		o.SubscriberWrapper = append(o.SubscriberWrapper, w...)
+	}
+}
+
+// wrapCall adds call wrappers to the client of the service
+func wrapCall(w ...cl.CallWrapper) ss.Option {
+	return func(o *ss.Options) {
+		o.CallWrapper = append(o.CallWrapper, w...)
+	}
+}
+
+// wrapClient adds client wrappers to the service
+func wrapClient(w ...cl.Wrapper) ss.Option {
+	return func(o *ss.Options) {
+		o.ClientWrapper = append(o.ClientWrapper, w...)
+	}
+}
+
+type Ratelimit struct {
+	Enable bool `json:"enable" sc:"enable" usage:"limit the requests and calls with the token buckets under stack.ratelimit"`
+	// the wrappers watch the limits themselves, the fields document and validate them
+	Server ratelimit.Limits `json:"server" sc:"server" usage:"limits of the requests served, by service or service/endpoint name"`
+	Client ratelimit.Limits `json:"client" sc:"client" usage:"limits of the calls made, by service or service/endpoint name"`
+}
+
+// Options returns the wrappers enforcing the limits under stack.ratelimit
+func (r *Ratelimit) Options(c cfg.Config) []ss.Option {
+	if !r.Enable {
+		return nil
+	}
+
+	return []ss.Option{
+		wrapHandler(ratelimit.NewHandlerWrapper(ratelimit.WithConfig(c, ratelimit.DefaultPath))),
+		wrapCall(ratelimit.NewCallWrapper(ratelimit.WithConfig(c, ratelimit.DefaultPath))),
+	}
+}
+
+type Trace struct {
+	Enable bool `json:"enable" sc:"enable" usage:"trace the handlers and calls of the service"`
+	// Exporter the spans are shipped to; zipkin or otlp.
+	// Spans are only kept in memory without one
+	Exporter string `json:"exporter" sc:"exporter" usage:"exporter the spans are shipped to, kept in memory if not set" validate:"enum=zipkin|otlp"`
+	Address  string `json:"address" sc:"address" usage:"address of the exporter"`
+}
+
+// Options returns the wrappers tracing the handlers and calls of the service.
+// The tracer replaces the trace.DefaultTracer read by the debug handler.
+func (t *Trace) Options(name string) []ss.Option {
+	if !t.Enable {
+		return nil
+	}
+
+	var opts []trace.Option
+
+	switch t.Exporter {
+	case "":
+	case "zipkin":
+		zOpts := []zipkin.Option{zipkin.Service(name)}
+		if len(t.Address) > 0 {
+			zOpts = append(zOpts, zipkin.Address(t.Address))
+		}
+		opts = append(opts, trace.Export(zipkin.NewExporter(zOpts...)))
+	case "otlp":
+		oOpts := []otlp.Option{otlp.Service(name)}
+		if len(t.Address) > 0 {
+			oOpts = append(oOpts, otlp.Address(t.Address))
+		}
+		opts = append(opts, trace.Export(otlp.NewExporter(oOpts...)))
+	default:
+		log.Warnf("seems you declared a trace exporter:[%s] which stack can't find out.", t.Exporter)
+	}
+
+	tracer := memory.NewTracer(opts...)
+	trace.DefaultTracer = tracer
+
+	return []ss.Option{
+		wrapHandler(wrapper.TraceHandler(tracer)),
+		wrapClient(func(c cl.Client) cl.Client {
+			return wrapper.TraceCall(name, tracer, c)
+		}),
+		ss.AfterStop(tracer.(*memory.Tracer).Close),
+	}
+}
+
+type Metrics struct {
+	Enable bool `json:"enable" sc:"enable" usage:"record the requests, calls and messages of the service"`
+	// Path the metrics are served on by the web service
+	Path string `json:"path" sc:"path" usage:"path the metrics are served on" default:"/metrics" validate:"regexp=^/"`
+	// Address of a dedicated listener serving the metrics
+	Address string `json:"address" sc:"address" usage:"address of a dedicated listener serving the metrics, eg. :9100"`
+}
+
+// Options returns the wrappers recording the requests, calls and messages of
+// the service in metrics.DefaultRegistry and the handlers exposing them
+func (m *Metrics) Options() []ss.Option {
+	if !m.Enable {
+		return nil
+	}
+
+	path := m.Path
+	if len(path) == 0 {
+		path = "/metrics"
+	}
+
+	handler := metrics.Handler(metrics.DefaultRegistry)
+
+	opts := []ss.Option{
+		wrapHandler(metrics.NewHandlerWrapper()),
+		wrapCall(metrics.NewCallWrapper()),
+		wrapSubscriber(metrics.NewSubscriberWrapper()),
+		sw.HandleFuncs(sw.HandlerFunc{Route: path, Func: handler.ServeHTTP}),
+	}
+
+	if len(m.Address) == 0 {
+		return opts
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	srv := &http.Server{Addr: m.Address, Handler: mux}
+
+	return append(opts,
+		ss.AfterStart(func() error {
+			ln, err := net.Listen("tcp", m.Address)
+			if err != nil {
+				return err
+			}
+			log.Infof("metrics listening on %s%s", ln.Addr(), path)
+			go srv.Serve(ln)
+			return nil
+		}),
+		ss.BeforeStop(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+			return srv.Shutdown(ctx)
+		}),
+	)
+}
+
 type Web struct {
-	Enable   bool   `json:"enable" sc:"enable"`
-	RootPath string `json:"rootPath" sc:"root-path"`
+	Enable   bool   `json:"enable" sc:"enable" usage:"serve the web handlers of the service"`
+	RootPath string `json:"rootPath" sc:"root-path" usage:"path the web handlers are served under"`
 	Static   struct {
-		Route string `json:"route" sc:"route"`
-		Dir   string `json:"dir" sc:"dir"`
+		Route string `json:"route" sc:"route" usage:"route of the static files"`
+		Dir   string `json:"dir" sc:"dir" usage:"dir of the static files"`
 	} `json:"static" sc:"static"`
 }
 
@@ -395,10 +593,10 @@ func (s serviceOpts) opts() ss.Options {
 }
 
 type Service struct {
-	ID      string `json:"id" sc:"id"`
-	Name    string `json:"name" sc:"name"`
-	Address string `json:"address" sc:"address"`
-	RPC     string `json:"rpc" sc:"rpc"`
+	ID      string `json:"id" sc:"id" usage:"id of the service, random if not set"`
+	Name    string `json:"name" sc:"name" usage:"name of the service"`
+	Address string `json:"address" sc:"address" usage:"address of the service"`
+	RPC     string `json:"rpc" sc:"rpc" usage:"rpc framework of the service, eg. stack"`
 	Web     Web    `json:"web" sc:"web"`
 }
 
@@ -428,18 +626,21 @@ func (s *Service) Options() serviceOpts {
 
 type StackConfig struct {
 	Stack struct {
-		Includes  string    `json:"includes" sc:"includes"`
+		Includes  string    `json:"includes" sc:"includes" usage:"comma separated config files next to stack.yml loaded with it"`
 		Config    Config    `json:"config" sc:"config"`
 		Registry  Registry  `json:"registry" sc:"registry"`
 		Broker    Broker    `json:"broker" sc:"broker"`
 		Client    Client    `json:"client" sc:"client"`
-		Profile   string    `json:"profile" sc:"profile"`
-		Runtime   string    `json:"runtime" sc:"runtime"`
+		Profile   string    `json:"profile" sc:"profile" usage:"profiler of the service"`
+		Runtime   string    `json:"runtime" sc:"runtime" usage:"runtime of the service"`
 		Server    Server    `json:"server" sc:"server"`
 		Selector  Selector  `json:"selector" sc:"selector"`
 		Transport Transport `json:"transport" sc:"transport"`
 		Logger    Logger    `json:"logger" sc:"logger"`
 		Auth      Auth      `json:"auth" sc:"auth"`
+		Ratelimit Ratelimit `json:"ratelimit" sc:"ratelimit"`
+		Trace     Trace     `json:"trace" sc:"trace"`
+		Metrics   Metrics   `json:"metrics" sc:"metrics"`
 		Service   Service   `json:"service" sc:"service"`
 	} `json:"stack" sc:"stack"`
 }
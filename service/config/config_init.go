@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	au "github.com/stack-labs/stack/auth"
 	cfg "github.com/stack-labs/stack/config"
 	"github.com/stack-labs/stack/pkg/config/source"
 	cliSource "github.com/stack-labs/stack/pkg/config/source/cli"
@@ -102,7 +103,12 @@ func LoadConfig(sOpts *service.Options) (err error) {
 }
 
 func SetOptions(sOpts *service.Options) (err error) {
-	conf := stackConfig.Stack
+	// a copy of the options of the same version of the config
+	var sc StackConfig
+	cfg.Read(&stackConfig, func() {
+		sc = stackConfig
+	})
+	conf := sc.Stack
 
 	// serviceOptions
 	for _, option := range conf.Service.Options() {
@@ -119,5 +125,26 @@ func SetOptions(sOpts *service.Options) (err error) {
 	sOpts.LoggerOptions = append(sOpts.LoggerOptions, conf.Logger.Options()...)
 	sOpts.AuthOptions = append(sOpts.AuthOptions, conf.Auth.Options()...)
 
+	if len(conf.Auth.Rules) > 0 {
+		if sOpts.Rules == nil || sOpts.Rules == au.NoopRules {
+			sOpts.Rules = au.NewRules()
+		}
+		if err = conf.Auth.Grant(sOpts.Rules); err != nil {
+			return fmt.Errorf("auth rules error: %s", err)
+		}
+	}
+
+	for _, option := range conf.Metrics.Options() {
+		option(sOpts)
+	}
+
+	for _, option := range conf.Trace.Options(conf.Service.Name) {
+		option(sOpts)
+	}
+
+	for _, option := range conf.Ratelimit.Options(sOpts.Config) {
+		option(sOpts)
+	}
+
 	return
 }
@@ -1,18 +1,24 @@
 package config
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/stack-labs/stack/client"
 	"github.com/stack-labs/stack/cmd"
 	cfg "github.com/stack-labs/stack/config"
 	"github.com/stack-labs/stack/pkg/config/source"
 	cliSource "github.com/stack-labs/stack/pkg/config/source/cli"
 	"github.com/stack-labs/stack/pkg/config/source/file"
 	"github.com/stack-labs/stack/pkg/config/source/memory"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/service"
 )
 
 var (
@@ -337,3 +343,76 @@ func touchFile(t *testing.T, fileName string, data []byte) (f *os.File, fullPath
 
 	return file, filePath, nil
 }
+
+func TestRatelimitKeepsWrappers(t *testing.T) {
+	var called []string
+	handler := func(name string) server.HandlerWrapper {
+		return func(h server.HandlerFunc) server.HandlerFunc {
+			return func(ctx context.Context, req server.Request, rsp interface{}) error {
+				called = append(called, name)
+				return h(ctx, req, rsp)
+			}
+		}
+	}
+	call := func(cf client.CallFunc) client.CallFunc { return cf }
+
+	var opts service.Options
+	for _, o := range []service.Option{
+		service.WrapHandler(handler("user")),
+		service.WrapCall(call),
+	} {
+		o(&opts)
+	}
+
+	// the config enabled wrappers are set after the options of the user
+	c := cfg.NewConfig()
+	if err := c.Init(cfg.Source(memory.NewSource(memory.WithJSON([]byte(`{}`))))); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	r := &Ratelimit{Enable: true}
+	for _, o := range r.Options(c) {
+		o(&opts)
+	}
+
+	if len(opts.HandlerWrapper) != 2 || len(opts.CallWrapper) != 2 {
+		t.Fatalf("Expected the user and rate limit wrappers got %d handler and %d call wrappers", len(opts.HandlerWrapper), len(opts.CallWrapper))
+	}
+
+	h := opts.HandlerWrapper[0](func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})
+	if err := h(context.Background(), nil, nil); err != nil || len(called) != 1 || called[0] != "user" {
+		t.Fatalf("Expected the user wrapper to be kept got %v %v", called, err)
+	}
+}
+
+func TestStackConfigValidation(t *testing.T) {
+	c := cfg.NewConfig()
+	err := c.Init(cfg.Source(memory.NewSource(memory.WithJSON([]byte(`{"stack":{"selector":{"strategy":"fastest"},"logger":{"level":"loud"},"ratelimit":{"server":{"greeter":{"rate":"fast"}}}}}`)))))
+	defer c.Close()
+	if err == nil {
+		t.Fatal("Expected the invalid strategy and level to be rejected")
+	}
+	for _, key := range []string{"stack.selector.strategy", "stack.logger.level", "stack.ratelimit.server"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Fatalf("Expected %s in the error got %v", key, err)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := cfg.Sample(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"# strategy picking a node of a service",
+		"# string, one of random|roundrobin|weighted|weighted_roundrobin|leastconn|p2c",
+		"path: /metrics",
+		"# limits of the calls made, by service or service/endpoint name",
+	} {
+		if !strings.Contains(b.String(), s) {
+			t.Fatalf("Expected %q in the sample config got\n%s", s, b.String())
+		}
+	}
+}
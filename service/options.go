@@ -46,6 +46,7 @@ type Options struct {
 	Config    config.Config
 	Logger    logger.Logger
 	Auth      auth.Auth
+	Rules     auth.Rules
 	Profile   profile.Profile
 
 	// Before and After funcs
@@ -300,6 +301,13 @@ func Auth(au auth.Auth) Option {
 	}
 }
 
+// Rules used to verify inbound requests when auth is enabled
+func Rules(r auth.Rules) Option {
+	return func(o *Options) {
+		o.Rules = r
+	}
+}
+
 // RegisterTTL specifies the TTL to use when registering the service
 func RegisterTTL(t time.Duration) Option {
 	return func(o *Options) {
@@ -60,6 +60,7 @@ func newOptions(opts ...service.Option) service.Options {
 		service.Logger(plugin.LoggerPlugins["console"].New()),
 		service.Config(cfg.DefaultConfig),
 		service.Auth(auth.NoopAuth),
+		service.Rules(auth.NoopRules),
 		service.HandleSignal(true),
 	}
 
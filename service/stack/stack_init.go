@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/stack-labs/stack/auth"
 	br "github.com/stack-labs/stack/broker"
 	cl "github.com/stack-labs/stack/client"
 	sel "github.com/stack-labs/stack/client/selector"
+	"github.com/stack-labs/stack/debug/health"
 	"github.com/stack-labs/stack/plugin"
 	ser "github.com/stack-labs/stack/server"
+	serverH "github.com/stack-labs/stack/server/http"
 	"github.com/stack-labs/stack/service"
 	"github.com/stack-labs/stack/util/log"
 	"github.com/stack-labs/stack/util/wrapper"
@@ -140,6 +143,38 @@ func (s *stackService) initComponents() error {
 	s.opts.SelectorOptions = append(s.opts.SelectorOptions, sel.Registry(s.opts.Registry))
 	s.opts.BrokerOptions = append(s.opts.BrokerOptions, br.Registry(s.opts.Registry))
 
+	// check the components and stay deregistered while not ready
+	health.Register("registry", health.Registry(s.opts.Registry))
+	health.Register("broker", health.Broker(s.opts.Broker))
+
+	check := serverOpts.RegisterCheck
+	s.opts.ServerOptions = append(s.opts.ServerOptions, ser.RegisterCheck(func(ctx context.Context) error {
+		if check != nil {
+			if err := check(ctx); err != nil {
+				return err
+			}
+		}
+		return health.DefaultHealth.Ready(ctx).Err()
+	}))
+
+	if err := s.opts.Auth.Init(s.opts.AuthOptions...); err != nil {
+		return fmt.Errorf("Error configuring auth: %v ", err)
+	}
+
+	// enforce auth before any other handler wrapper runs
+	if s.opts.Auth.Options().Enable {
+		if s.opts.Rules == nil || s.opts.Rules == auth.NoopRules {
+			log.Warn("auth is enabled without rules, every request is allowed. Set stack.auth.rules or the Rules option")
+		}
+		authWrapper := wrapper.AuthHandler(
+			func() auth.Auth { return s.opts.Auth },
+			func() auth.Rules { return s.opts.Rules },
+			wrapper.HealthExempt(func() ser.Server { return s.opts.Server }),
+		)
+		// the plain http handlers are only wrapped on request
+		s.opts.ServerOptions = append(s.opts.ServerOptions, ser.WrapHandler(authWrapper), serverH.WrapHTTPHandler(authWrapper))
+	}
+
 	// set wrappers
 	for _, wrapper := range s.opts.HandlerWrapper {
 		s.opts.ServerOptions = append(s.opts.ServerOptions, ser.WrapHandler(wrapper))
@@ -154,10 +189,6 @@ func (s *stackService) initComponents() error {
 		s.opts.ServerOptions = append(s.opts.ServerOptions, ser.WrapSubscriber(wrapper))
 	}
 
-	if err := s.opts.Auth.Init(s.opts.AuthOptions...); err != nil {
-		return fmt.Errorf("Error configuring auth: %v ", err)
-	}
-
 	if err := s.opts.Logger.Init(s.opts.LoggerOptions...); err != nil {
 		return fmt.Errorf("Error configuring logger: %s ", err)
 	}
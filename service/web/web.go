@@ -5,6 +5,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/stack-labs/stack/debug/health"
 	"github.com/stack-labs/stack/server"
 	"github.com/stack-labs/stack/service"
 	"github.com/stack-labs/stack/util/log"
@@ -64,6 +65,11 @@ func setHandle(sOpts *service.Options) error {
 		mux = muxTmp
 	}
 
+	// serve the liveness and readiness of the service
+	hmux := http.NewServeMux()
+	hmux.Handle(health.DefaultPath+"/", health.Handler(health.DefaultHealth))
+	hmux.Handle("/", mux)
+
 	var handlerOpts []server.HandlerOption
 	if sOpts.Context.Value(handlerOptsKey{}) != nil {
 		if opts, ok := sOpts.Context.Value(handlerOptsKey{}).([]server.HandlerOption); ok {
@@ -71,5 +77,5 @@ func setHandle(sOpts *service.Options) error {
 		}
 	}
 
-	return sOpts.Server.Handle(sOpts.Server.NewHandler(mux, handlerOpts...))
+	return sOpts.Server.Handle(sOpts.Server.NewHandler(hmux, handlerOpts...))
 }
@@ -0,0 +1,375 @@
+// Package file is a persistent store backed by an append only log file
+package file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/util/log"
+	"github.com/stack-labs/stack/util/options"
+)
+
+var (
+	// DefaultDir is the directory the store files are kept in
+	DefaultDir = filepath.Join(os.TempDir(), "stack", "store")
+	// DefaultNamespace is the namespace used when none is specified
+	DefaultNamespace = "stack"
+	// DefaultCompactThreshold is the number of stale log entries
+	// which triggers a compaction of the log
+	DefaultCompactThreshold = 1024
+
+	// ErrCorrupt is returned when a log entry fails its checksum
+	ErrCorrupt = errors.New("corrupt log entry")
+	// ErrNamespace is returned for namespaces which aren't a file name,
+	// e.g. ../x, which would be outside the store directory
+	ErrNamespace = errors.New("invalid namespace")
+
+	// size of the length and checksum preceding each entry
+	headerSize = 8
+)
+
+type fileStore struct {
+	options.Options
+
+	dir       string
+	namespace string
+	prefix    string
+	fsync     bool
+	threshold int
+	// err of the store directory, returned by every operation
+	err error
+
+	sync.RWMutex
+	// logs by namespace
+	logs map[string]*fileLog
+}
+
+// log returns the log of the namespace, opening it if needed
+func (f *fileStore) log(namespace string) (*fileLog, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if len(namespace) == 0 {
+		namespace = f.namespace
+	}
+	if strings.ContainsAny(namespace, `/\`+"\x00") || strings.Contains(namespace, "..") {
+		return nil, ErrNamespace
+	}
+
+	f.RLock()
+	l, ok := f.logs[namespace]
+	f.RUnlock()
+	if ok {
+		return l, nil
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	if l, ok := f.logs[namespace]; ok {
+		return l, nil
+	}
+
+	l, err := openLog(filepath.Join(f.dir, namespace+".db"), f.fsync, f.threshold)
+	if err != nil {
+		return nil, err
+	}
+	f.logs[namespace] = l
+
+	return l, nil
+}
+
+func (f *fileStore) toRecord(r *record, now time.Time) *store.Record {
+	rec := &store.Record{
+		Key:   strings.TrimPrefix(r.Key, f.prefix),
+		Value: r.Value,
+	}
+	if r.Expires > 0 {
+		rec.Expiry = time.Duration(r.Expires - now.UnixNano())
+	}
+	return rec
+}
+
+// find returns the live records in a table matching fn sorted by key
+func (f *fileStore) find(l *fileLog, table string, fn func(key string) bool) []*store.Record {
+	now := time.Now()
+
+	//nolint:prealloc
+	var records []*store.Record
+
+	for i, r := range l.values {
+		if i.table != table || r.expired(now) || !strings.HasPrefix(i.key, f.prefix) {
+			continue
+		}
+		if !fn(strings.TrimPrefix(i.key, f.prefix)) {
+			continue
+		}
+		records = append(records, f.toRecord(r, now))
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+
+	return records
+}
+
+// page applies offset and limit to records
+func page(records []*store.Record, offset, limit uint) []*store.Record {
+	if offset >= uint(len(records)) {
+		return nil
+	}
+	records = records[offset:]
+	if limit > 0 && limit < uint(len(records)) {
+		records = records[:limit]
+	}
+	return records
+}
+
+func (f *fileStore) List(opts ...store.ListOption) ([]*store.Record, error) {
+	var options store.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	l, err := f.log(options.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	l.RLock()
+	defer l.RUnlock()
+
+	records := f.find(l, options.Table, func(key string) bool {
+		return strings.HasPrefix(key, options.Prefix) && strings.HasSuffix(key, options.Suffix)
+	})
+
+	return page(records, options.Offset, options.Limit), nil
+}
+
+func (f *fileStore) Read(keys ...string) ([]*store.Record, error) {
+	l, err := f.log("")
+	if err != nil {
+		return nil, err
+	}
+
+	l.RLock()
+	defer l.RUnlock()
+
+	now := time.Now()
+	records := make([]*store.Record, 0, len(keys))
+
+	for _, key := range keys {
+		r, ok := l.values[index{"", f.prefix + key}]
+		if !ok || r.expired(now) {
+			return nil, store.ErrNotFound
+		}
+		records = append(records, f.toRecord(r, now))
+	}
+
+	return records, nil
+}
+
+func (f *fileStore) ReadWith(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	l, err := f.log(options.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	l.RLock()
+	defer l.RUnlock()
+
+	if options.Prefix || options.Suffix {
+		records := f.find(l, options.Table, func(k string) bool {
+			if options.Prefix && !strings.HasPrefix(k, key) {
+				return false
+			}
+			if options.Suffix && !strings.HasSuffix(k, key) {
+				return false
+			}
+			return true
+		})
+		return page(records, options.Offset, options.Limit), nil
+	}
+
+	now := time.Now()
+
+	r, ok := l.values[index{options.Table, f.prefix + key}]
+	if !ok || r.expired(now) {
+		return nil, store.ErrNotFound
+	}
+
+	return []*store.Record{f.toRecord(r, now)}, nil
+}
+
+// write appends the records as one log entry
+func (f *fileStore) write(records []*store.Record, options store.WriteOptions) error {
+	l, err := f.log(options.Namespace)
+	if err != nil {
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	recs := make([]*record, 0, len(records))
+
+	for _, r := range records {
+		rec := &record{
+			Table: options.Table,
+			Key:   f.prefix + r.Key,
+			Value: r.Value,
+		}
+
+		expiry := r.Expiry
+		if options.TTL > time.Duration(0) {
+			expiry = options.TTL
+		}
+		if expiry > time.Duration(0) {
+			rec.Expires = now.Add(expiry).UnixNano()
+		}
+
+		recs = append(recs, rec)
+	}
+
+	return l.append(&entry{Op: opWrite, Records: recs})
+}
+
+func (f *fileStore) Write(records ...*store.Record) error {
+	return f.write(records, store.WriteOptions{})
+}
+
+func (f *fileStore) WriteWith(r *store.Record, opts ...store.WriteOption) error {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return f.write([]*store.Record{r}, options)
+}
+
+// delete appends the deletion of the existing keys as one log entry
+func (f *fileStore) delete(keys []string, options store.DeleteOptions) error {
+	l, err := f.log(options.Namespace)
+	if err != nil {
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	//nolint:prealloc
+	var recs []*record
+
+	for _, key := range keys {
+		rec := &record{
+			Table: options.Table,
+			Key:   f.prefix + key,
+		}
+		if _, ok := l.values[index{rec.Table, rec.Key}]; !ok {
+			continue
+		}
+		recs = append(recs, rec)
+	}
+
+	if len(recs) == 0 {
+		return nil
+	}
+
+	return l.append(&entry{Op: opDelete, Records: recs})
+}
+
+func (f *fileStore) Delete(keys ...string) error {
+	return f.delete(keys, store.DeleteOptions{})
+}
+
+func (f *fileStore) DeleteWith(key string, opts ...store.DeleteOption) error {
+	var options store.DeleteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return f.delete([]string{key}, options)
+}
+
+// Close closes the underlying log files
+func (f *fileStore) Close() error {
+	f.Lock()
+	defer f.Unlock()
+
+	var err error
+	for ns, l := range f.logs {
+		if cerr := l.Close(); cerr != nil {
+			err = cerr
+		}
+		delete(f.logs, ns)
+	}
+	return err
+}
+
+// NewStore returns a new file backed store.Store. Each namespace is
+// kept in its own log file within the directory and must only be
+// opened by one store at a time.
+func NewStore(opts ...options.Option) store.Store {
+	options := options.NewOptions(opts...)
+
+	dir := DefaultDir
+	if v, ok := options.Values().Get("store.file.dir"); ok {
+		dir = v.(string)
+	}
+
+	namespace := DefaultNamespace
+	if v, ok := options.Values().Get("store.namespace"); ok && len(v.(string)) > 0 {
+		namespace = v.(string)
+	}
+
+	var prefix string
+	if v, ok := options.Values().Get("store.prefix"); ok {
+		prefix = v.(string)
+	}
+
+	fsync := true
+	if v, ok := options.Values().Get("store.file.sync"); ok {
+		fsync = v.(bool)
+	}
+
+	threshold := DefaultCompactThreshold
+	if v, ok := options.Values().Get("store.file.compact"); ok {
+		threshold = v.(int)
+	}
+
+	f := &fileStore{
+		Options:   options,
+		dir:       dir,
+		namespace: namespace,
+		prefix:    prefix,
+		fsync:     fsync,
+		threshold: threshold,
+		logs:      make(map[string]*fileLog),
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Errorf("[store] failed to create %s: %v", dir, err)
+		f.err = err
+		return f
+	}
+
+	// report a log which can not be opened early, the operations return the error
+	if _, err := f.log(namespace); err != nil {
+		log.Errorf("[store] failed to load namespace %s: %v", namespace, err)
+	}
+
+	return f
+}
@@ -0,0 +1,302 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir))
+
+	if err := s.Write(
+		&store.Record{Key: "foo", Value: []byte("bar")},
+		&store.Record{Key: "baz", Value: []byte("qux")},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := s.Read("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recs[0].Value) != "bar" {
+		t.Fatalf("expected bar got %s", recs[0].Value)
+	}
+
+	if err := s.Delete("baz"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Read("baz"); err != store.ErrNotFound {
+		t.Fatalf("expected %v got %v", store.ErrNotFound, err)
+	}
+
+	// reopen and replay the log
+	s.(*fileStore).Close()
+	s = NewStore(Dir(dir))
+	defer s.(*fileStore).Close()
+
+	recs, err = s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Key != "foo" || string(recs[0].Value) != "bar" {
+		t.Fatalf("unexpected records after reopen %+v", recs)
+	}
+}
+
+func TestFileStoreExpiry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir))
+	defer s.(*fileStore).Close()
+
+	expire := 100 * time.Millisecond
+	if err := s.Write(&store.Record{Key: "foo", Expiry: expire}); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := s.Read("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recs[0].Expiry <= 0 || recs[0].Expiry > expire {
+		t.Fatalf("unexpected expiry %v", recs[0].Expiry)
+	}
+
+	time.Sleep(expire)
+
+	if _, err := s.Read("foo"); err != store.ErrNotFound {
+		t.Fatal("expire elapsed, but key still accessable")
+	}
+}
+
+func TestFileStoreNamespacePrefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := NewStore(Dir(dir), store.Namespace("a"), store.Prefix("x/"))
+	defer a.(*fileStore).Close()
+	b := NewStore(Dir(dir), store.Namespace("b"))
+	defer b.(*fileStore).Close()
+
+	if err := a.Write(&store.Record{Key: "foo", Value: []byte("a")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.Read("foo"); err != store.ErrNotFound {
+		t.Fatalf("expected namespaces to be isolated, got %v", err)
+	}
+
+	recs, err := a.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Key != "foo" {
+		t.Fatalf("expected prefix to be stripped, got %+v", recs)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "a.db")); err != nil {
+		t.Fatal(err)
+	}
+
+	// per call namespace and table
+	if err := a.WriteWith(&store.Record{Key: "bar"}, store.WriteTo("b", "t")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.ReadWith("bar", store.ReadFrom("b", "t")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.ReadWith("bar", store.ReadFrom("b", "")); err != store.ErrNotFound {
+		t.Fatalf("expected tables to be isolated, got %v", err)
+	}
+
+	// namespaces can't leave the store directory
+	for _, ns := range []string{"../x", "a/b", `a\b`, ".."} {
+		if err := a.WriteWith(&store.Record{Key: "bar"}, store.WriteTo(ns, "")); err != ErrNamespace {
+			t.Fatalf("expected %v for namespace %s, got %v", ErrNamespace, ns, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "x.db")); !os.IsNotExist(err) {
+		t.Fatalf("expected no file outside the store directory, got %v", err)
+	}
+}
+
+func TestFileStoreReadPrefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir))
+	defer s.(*fileStore).Close()
+
+	for _, key := range []string{"foo/a", "foo/b", "foo/c", "bar/a"} {
+		if err := s.Write(&store.Record{Key: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recs, err := s.ReadWith("foo/", store.ReadPrefix(), store.ReadLimit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || recs[0].Key != "foo/a" || recs[1].Key != "foo/b" {
+		t.Fatalf("unexpected records %+v", recs)
+	}
+
+	recs, err = s.List(store.ListSuffix("/a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("unexpected records %+v", recs)
+	}
+}
+
+func TestFileStoreTornWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir))
+	if err := s.Write(&store.Record{Key: "foo", Value: []byte("bar")}); err != nil {
+		t.Fatal(err)
+	}
+	s.(*fileStore).Close()
+
+	// simulate a crash half way through an append
+	path := filepath.Join(dir, DefaultNamespace+".db")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 0, 42, 1, 2})
+	f.Close()
+
+	s = NewStore(Dir(dir))
+	defer s.(*fileStore).Close()
+
+	if _, err := s.Read("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(&store.Record{Key: "baz", Value: []byte("qux")}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.(*fileStore).Close()
+	s = NewStore(Dir(dir))
+
+	if recs, err := s.List(); err != nil || len(recs) != 2 {
+		t.Fatalf("expected 2 records after truncation got %d: %v", len(recs), err)
+	}
+}
+
+func TestFileStoreTornBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir))
+	if err := s.Write(
+		&store.Record{Key: "foo", Value: []byte("bar")},
+		&store.Record{Key: "baz", Value: []byte("qux")},
+	); err != nil {
+		t.Fatal(err)
+	}
+	s.(*fileStore).Close()
+
+	// lose the end of the batch entry
+	path := filepath.Join(dir, DefaultNamespace+".db")
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, fi.Size()-2); err != nil {
+		t.Fatal(err)
+	}
+
+	s = NewStore(Dir(dir))
+	defer s.(*fileStore).Close()
+
+	if recs, err := s.List(); err != nil || len(recs) != 0 {
+		t.Fatalf("expected the batch to be dropped got %d records: %v", len(recs), err)
+	}
+}
+
+func TestFileStoreCompact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir), CompactThreshold(10))
+
+	for i := 0; i < 100; i++ {
+		if err := s.Write(&store.Record{Key: "foo", Value: []byte{byte(i)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l, err := s.(*fileStore).log("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stale := l.stale; stale > 10 {
+		t.Fatalf("expected log to be compacted, %d stale entries", stale)
+	}
+
+	s.(*fileStore).Close()
+	s = NewStore(Dir(dir))
+	defer s.(*fileStore).Close()
+
+	recs, err := s.Read("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recs[0].Value[0] != 99 {
+		t.Fatalf("expected 99 got %d", recs[0].Value[0])
+	}
+}
+
+func TestFileStoreDirError(t *testing.T) {
+	f, err := ioutil.TempFile("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	// the directory can't be created inside a file
+	s := NewStore(Dir(filepath.Join(f.Name(), "store")))
+
+	if err := s.Write(&store.Record{Key: "foo", Value: []byte("bar")}); err == nil {
+		t.Fatal("expected the directory error on write")
+	}
+	if _, err := s.List(); err == nil {
+		t.Fatal("expected the directory error on list")
+	}
+}
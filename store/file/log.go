@@ -0,0 +1,255 @@
+package file
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/util/log"
+)
+
+const (
+	opWrite  = "write"
+	opDelete = "delete"
+)
+
+// entry is a single log entry. Every Write or Delete is
+// appended as one entry so a batch is applied all or nothing.
+type entry struct {
+	Op      string    `json:"op"`
+	Records []*record `json:"records"`
+}
+
+type record struct {
+	Table string `json:"table,omitempty"`
+	Key   string `json:"key"`
+	Value []byte `json:"value,omitempty"`
+	// Expires is the unix nano time of expiry, zero never expires
+	Expires int64 `json:"expires,omitempty"`
+}
+
+// index locates a record within a log
+type index struct {
+	table string
+	key   string
+}
+
+// fileLog is the append only log of a single namespace
+type fileLog struct {
+	path      string
+	fsync     bool
+	threshold int
+
+	sync.RWMutex
+	file   *os.File
+	values map[index]*record
+	// number of log entries superseded by later ones
+	stale int
+}
+
+func (r *record) expired(now time.Time) bool {
+	return r.Expires > 0 && now.UnixNano() > r.Expires
+}
+
+// encode frames an entry as length, crc32 checksum and json payload
+func encode(e *entry) ([]byte, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, headerSize+len(b))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(b)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(b))
+	copy(buf[headerSize:], b)
+	return buf, nil
+}
+
+// decode reads the next entry from the log
+func decode(r io.Reader) (*entry, int, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, 0, err
+	}
+
+	size := binary.BigEndian.Uint32(header[0:4])
+	sum := binary.BigEndian.Uint32(header[4:8])
+
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, 0, err
+	}
+	if crc32.ChecksumIEEE(b) != sum {
+		return nil, 0, ErrCorrupt
+	}
+
+	e := new(entry)
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, 0, ErrCorrupt
+	}
+
+	return e, headerSize + len(b), nil
+}
+
+// openLog replays the log at path. A torn write at the tail of the log,
+// left by a crash mid append, is truncated away.
+func openLog(path string, fsync bool, threshold int) (*fileLog, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &fileLog{
+		path:      path,
+		fsync:     fsync,
+		threshold: threshold,
+		values:    make(map[index]*record),
+	}
+
+	reader := bufio.NewReader(file)
+	var offset int64
+
+	for {
+		e, n, err := decode(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Logf("[store] truncating %s at offset %d: %v", path, offset, err)
+			if err := file.Truncate(offset); err != nil {
+				file.Close()
+				return nil, err
+			}
+			break
+		}
+		l.apply(e)
+		offset += int64(n)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	l.file = file
+	return l, nil
+}
+
+// apply updates the in memory index with an entry
+func (l *fileLog) apply(e *entry) {
+	for _, r := range e.Records {
+		i := index{r.Table, r.Key}
+		if _, ok := l.values[i]; ok {
+			l.stale++
+		}
+		switch e.Op {
+		case opWrite:
+			l.values[i] = r
+		case opDelete:
+			delete(l.values, i)
+			l.stale++
+		}
+	}
+}
+
+// append writes an entry to the log and applies it
+func (l *fileLog) append(e *entry) error {
+	b, err := encode(e)
+	if err != nil {
+		return err
+	}
+
+	offset, err := l.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	if _, err := l.file.Write(b); err != nil {
+		// drop the partial entry so later appends are not lost
+		l.file.Truncate(offset)
+		l.file.Seek(offset, io.SeekStart)
+		return err
+	}
+
+	if l.fsync {
+		if err := l.file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	l.apply(e)
+
+	if l.stale > l.threshold && l.stale > len(l.values) {
+		if err := l.compact(); err != nil {
+			log.Logf("[store] compaction of %s failed: %v", l.path, err)
+		}
+	}
+
+	return nil
+}
+
+// compact rewrites the log with only the live records. The new log is
+// written to a temporary file and renamed over the old one.
+func (l *fileLog) compact() error {
+	now := time.Now()
+	tmp := l.path + ".compact"
+
+	file, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	e := &entry{Op: opWrite}
+	for i, r := range l.values {
+		if r.expired(now) {
+			delete(l.values, i)
+			continue
+		}
+		e.Records = append(e.Records, r)
+	}
+
+	if len(e.Records) > 0 {
+		b, err := encode(e)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		if _, err := file.Write(b); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := os.Rename(tmp, l.path); err != nil {
+		file.Close()
+		return err
+	}
+
+	// persist the rename
+	if d, err := os.Open(filepath.Dir(l.path)); err == nil {
+		d.Sync()
+		d.Close()
+	}
+
+	l.file.Close()
+	l.file = file
+	l.stale = 0
+
+	return nil
+}
+
+func (l *fileLog) Close() error {
+	l.Lock()
+	defer l.Unlock()
+	return l.file.Close()
+}
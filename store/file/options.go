@@ -0,0 +1,22 @@
+package file
+
+import (
+	"github.com/stack-labs/stack/util/options"
+)
+
+// Dir sets the directory the store files are kept in
+func Dir(d string) options.Option {
+	return options.WithValue("store.file.dir", d)
+}
+
+// Sync sets whether every write is synced to disk before returning,
+// defaults to true
+func Sync(b bool) options.Option {
+	return options.WithValue("store.file.sync", b)
+}
+
+// CompactThreshold sets the number of stale log entries after
+// which the log is compacted
+func CompactThreshold(n int) options.Option {
+	return options.WithValue("store.file.compact", n)
+}
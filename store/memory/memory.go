@@ -2,6 +2,9 @@
 package memory
 
 import (
+	"bytes"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,88 +15,269 @@ import (
 type memoryStore struct {
 	options.Options
 
+	namespace string
+	prefix    string
+
 	sync.RWMutex
-	values map[string]*memoryRecord
+	tables map[table]map[string]*memoryRecord
+	// records written to a table since its expired records were evicted
+	writes map[table]int
+}
+
+// table is a namespace and table pair records are kept in
+type table struct {
+	namespace string
+	name      string
 }
 
 type memoryRecord struct {
-	r *store.Record
-	c time.Time
+	key   string
+	value []byte
+	// zero never expires
+	expires time.Time
 }
 
-func (m *memoryStore) List() ([]*store.Record, error) {
-	m.RLock()
-	defer m.RUnlock()
+func (r *memoryRecord) expired(now time.Time) bool {
+	return !r.expires.IsZero() && now.After(r.expires)
+}
 
-	//nolint:prealloc
-	var values []*store.Record
+func (m *memoryStore) table(namespace, name string) table {
+	if len(namespace) == 0 {
+		namespace = m.namespace
+	}
+	return table{namespace: namespace, name: name}
+}
 
-	for _, v := range m.values {
-		// get expiry
-		d := v.r.Expiry
-		t := time.Since(v.c)
+// record returns a copy of the record with the remaining expiry
+func (m *memoryStore) record(r *memoryRecord, now time.Time) *store.Record {
+	rec := &store.Record{
+		Key:   strings.TrimPrefix(r.key, m.prefix),
+		Value: r.value,
+	}
+	if !r.expires.IsZero() {
+		rec.Expiry = r.expires.Sub(now)
+	}
+	return rec
+}
 
-		if d > time.Duration(0) {
-			// expired
-			if t > d {
-				continue
-			}
-			// update expiry
-			v.r.Expiry -= t
-			v.c = time.Now()
+// find returns the live records in a table matching fn sorted by key
+func (m *memoryStore) find(t table, fn func(key string) bool) []*store.Record {
+	now := time.Now()
+
+	//nolint:prealloc
+	var records []*store.Record
+
+	for k, v := range m.tables[t] {
+		if v.expired(now) || !fn(strings.TrimPrefix(k, m.prefix)) {
+			continue
 		}
+		records = append(records, m.record(v, now))
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+
+	return records
+}
 
-		values = append(values, v.r)
+// page applies offset and limit to records
+func page(records []*store.Record, offset, limit uint) []*store.Record {
+	if offset >= uint(len(records)) {
+		return nil
 	}
+	records = records[offset:]
+	if limit > 0 && limit < uint(len(records)) {
+		records = records[:limit]
+	}
+	return records
+}
+
+func (m *memoryStore) List(opts ...store.ListOption) ([]*store.Record, error) {
+	var options store.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	m.RLock()
+	defer m.RUnlock()
 
-	return values, nil
+	records := m.find(m.table(options.Namespace, options.Table), func(key string) bool {
+		return strings.HasPrefix(key, options.Prefix) && strings.HasSuffix(key, options.Suffix)
+	})
+
+	return page(records, options.Offset, options.Limit), nil
 }
 
 func (m *memoryStore) Read(keys ...string) ([]*store.Record, error) {
 	m.RLock()
 	defer m.RUnlock()
 
-	//nolint:prealloc
-	var records []*store.Record
+	now := time.Now()
+	t := m.table("", "")
+	records := make([]*store.Record, 0, len(keys))
 
 	for _, key := range keys {
-		v, ok := m.values[key]
-		if !ok {
+		v, ok := m.tables[t][m.prefix+key]
+		if !ok || v.expired(now) {
 			return nil, store.ErrNotFound
 		}
+		records = append(records, m.record(v, now))
+	}
+
+	return records, nil
+}
 
-		// get expiry
-		d := v.r.Expiry
-		t := time.Since(v.c)
+func (m *memoryStore) ReadWith(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	m.RLock()
+	defer m.RUnlock()
 
-		// expired
-		if d > time.Duration(0) {
-			if t > d {
-				return nil, store.ErrNotFound
+	t := m.table(options.Namespace, options.Table)
+
+	if options.Prefix || options.Suffix {
+		records := m.find(t, func(k string) bool {
+			if options.Prefix && !strings.HasPrefix(k, key) {
+				return false
+			}
+			if options.Suffix && !strings.HasSuffix(k, key) {
+				return false
 			}
-			// update expiry
-			v.r.Expiry -= t
-			v.c = time.Now()
+			return true
+		})
+		return page(records, options.Offset, options.Limit), nil
+	}
+
+	now := time.Now()
+
+	v, ok := m.tables[t][m.prefix+key]
+	if !ok || v.expired(now) {
+		return nil, store.ErrNotFound
+	}
+
+	return []*store.Record{m.record(v, now)}, nil
+}
+
+// write sets the records, called with the lock held
+func (m *memoryStore) write(records []*store.Record, options store.WriteOptions) {
+	t := m.table(options.Namespace, options.Table)
+	if _, ok := m.tables[t]; !ok {
+		m.tables[t] = make(map[string]*memoryRecord)
+	}
+
+	now := time.Now()
+
+	for _, r := range records {
+		rec := &memoryRecord{
+			key:   m.prefix + r.Key,
+			value: r.Value,
 		}
 
-		records = append(records, v.r)
+		expiry := r.Expiry
+		if options.TTL > time.Duration(0) {
+			expiry = options.TTL
+		}
+		if expiry > time.Duration(0) {
+			rec.expires = now.Add(expiry)
+		}
+
+		// set the record
+		m.tables[t][rec.key] = rec
+	}
+
+	// evict once as many records were written as the table holds, so the
+	// expired records never outnumber the live ones for long
+	m.writes[t] += len(records)
+	if m.writes[t] >= len(m.tables[t]) {
+		m.evict(t, now)
 	}
+}
 
-	return records, nil
+// evict deletes the expired records of a table, called with the lock held
+func (m *memoryStore) evict(t table, now time.Time) {
+	for k, v := range m.tables[t] {
+		if v.expired(now) {
+			delete(m.tables[t], k)
+		}
+	}
+	m.writes[t] = 0
 }
 
 func (m *memoryStore) Write(records ...*store.Record) error {
 	m.Lock()
 	defer m.Unlock()
 
-	for _, r := range records {
-		// set the record
-		m.values[r.Key] = &memoryRecord{
-			r: r,
-			c: time.Now(),
-		}
+	m.write(records, store.WriteOptions{})
+
+	return nil
+}
+
+func (m *memoryStore) WriteWith(r *store.Record, opts ...store.WriteOption) error {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.write([]*store.Record{r}, options)
+
+	return nil
+}
+
+// matches reports whether the live value of the key in t is old,
+// a nil old value matches a key that doesn't exist
+func (m *memoryStore) matches(t table, key string, old []byte, now time.Time) bool {
+	v, ok := m.tables[t][m.prefix+key]
+	if !ok || v.expired(now) {
+		return old == nil
+	}
+	return old != nil && bytes.Equal(v.value, old)
+}
+
+func (m *memoryStore) CompareAndSwap(r *store.Record, old []byte, opts ...store.WriteOption) error {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	now := time.Now()
+	t := m.table(options.Namespace, options.Table)
+
+	if !m.matches(t, r.Key, old, now) {
+		return store.ErrConflict
+	}
+
+	m.write([]*store.Record{r}, options)
+
+	return nil
+}
+
+func (m *memoryStore) CompareAndDelete(key string, old []byte, opts ...store.DeleteOption) error {
+	var options store.DeleteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	t := m.table(options.Namespace, options.Table)
+
+	if old == nil || !m.matches(t, key, old, time.Now()) {
+		return store.ErrConflict
 	}
 
+	delete(m.tables[t], m.prefix+key)
+
 	return nil
 }
 
@@ -101,11 +285,27 @@ func (m *memoryStore) Delete(keys ...string) error {
 	m.Lock()
 	defer m.Unlock()
 
+	t := m.table("", "")
 	for _, key := range keys {
 		// delete the value
-		delete(m.values, key)
+		delete(m.tables[t], m.prefix+key)
+	}
+
+	return nil
+}
+
+func (m *memoryStore) DeleteWith(key string, opts ...store.DeleteOption) error {
+	var options store.DeleteOptions
+	for _, o := range opts {
+		o(&options)
 	}
 
+	m.Lock()
+	defer m.Unlock()
+
+	// delete the value
+	delete(m.tables[m.table(options.Namespace, options.Table)], m.prefix+key)
+
 	return nil
 }
 
@@ -113,8 +313,19 @@ func (m *memoryStore) Delete(keys ...string) error {
 func NewStore(opts ...options.Option) store.Store {
 	options := options.NewOptions(opts...)
 
+	var namespace, prefix string
+	if v, ok := options.Values().Get("store.namespace"); ok {
+		namespace = v.(string)
+	}
+	if v, ok := options.Values().Get("store.prefix"); ok {
+		prefix = v.(string)
+	}
+
 	return &memoryStore{
-		Options: options,
-		values:  make(map[string]*memoryRecord),
+		Options:   options,
+		namespace: namespace,
+		prefix:    prefix,
+		tables:    make(map[table]map[string]*memoryRecord),
+		writes:    make(map[table]int),
 	}
 }
@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -35,3 +36,160 @@ func TestReadRecordExpire(t *testing.T) {
 		t.Fatal("expire elapsed, but key still accessable")
 	}
 }
+
+func TestReadPrefixSuffix(t *testing.T) {
+	s := NewStore()
+
+	for _, key := range []string{"foo/a", "foo/b", "foo/c", "bar/a"} {
+		if err := s.Write(&store.Record{Key: key, Value: []byte(key)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recs, err := s.ReadWith("foo/", store.ReadPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 3 || recs[0].Key != "foo/a" || recs[2].Key != "foo/c" {
+		t.Fatalf("unexpected prefix records %+v", recs)
+	}
+
+	recs, err = s.ReadWith("/a", store.ReadSuffix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || recs[0].Key != "bar/a" {
+		t.Fatalf("unexpected suffix records %+v", recs)
+	}
+
+	recs, err = s.ReadWith("foo/", store.ReadPrefix(), store.ReadOffset(1), store.ReadLimit(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Key != "foo/b" {
+		t.Fatalf("unexpected paged records %+v", recs)
+	}
+}
+
+func TestListOptions(t *testing.T) {
+	s := NewStore(store.Namespace("test"))
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := s.Write(&store.Record{Key: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.WriteWith(&store.Record{Key: "d"}, store.WriteTo("other", "table")); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := s.List(store.ListOffset(1), store.ListLimit(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || recs[0].Key != "b" {
+		t.Fatalf("unexpected paged records %+v", recs)
+	}
+
+	recs, err = s.List(store.ListFrom("other", "table"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Key != "d" {
+		t.Fatalf("unexpected table records %+v", recs)
+	}
+
+	if _, err := s.Read("d"); err != store.ErrNotFound {
+		t.Fatal("record written to another table is readable from the default table")
+	}
+
+	if err := s.DeleteWith("d", store.DeleteFrom("other", "table")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ReadWith("d", store.ReadFrom("other", "table")); err != store.ErrNotFound {
+		t.Fatal("record not deleted from table")
+	}
+}
+
+func TestWriteTTL(t *testing.T) {
+	s := NewStore()
+
+	if err := s.WriteWith(&store.Record{Key: "foo"}, store.WriteTTL(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := s.Read("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recs[0].Expiry <= 0 || recs[0].Expiry > time.Minute {
+		t.Fatalf("unexpected remaining ttl %v", recs[0].Expiry)
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	s := NewStore().(store.CAS)
+	st := s.(store.Store)
+
+	if err := s.CompareAndSwap(&store.Record{Key: "foo", Value: []byte("a")}, nil); err != nil {
+		t.Fatal(err)
+	}
+	// the key exists
+	if err := s.CompareAndSwap(&store.Record{Key: "foo", Value: []byte("b")}, nil); err != store.ErrConflict {
+		t.Fatalf("expected %v got %v", store.ErrConflict, err)
+	}
+	if err := s.CompareAndSwap(&store.Record{Key: "foo", Value: []byte("b")}, []byte("x")); err != store.ErrConflict {
+		t.Fatalf("expected %v got %v", store.ErrConflict, err)
+	}
+	if err := s.CompareAndSwap(&store.Record{Key: "foo", Value: []byte("b")}, []byte("a"), store.WriteTTL(time.Millisecond*10)); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := st.Read("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recs[0].Value) != "b" {
+		t.Fatalf("expected b got %s", recs[0].Value)
+	}
+
+	// expired keys don't exist
+	time.Sleep(time.Millisecond * 20)
+	if err := s.CompareAndSwap(&store.Record{Key: "foo", Value: []byte("c")}, []byte("b")); err != store.ErrConflict {
+		t.Fatalf("expected %v got %v", store.ErrConflict, err)
+	}
+	if err := s.CompareAndSwap(&store.Record{Key: "foo", Value: []byte("c")}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.CompareAndDelete("foo", []byte("b")); err != store.ErrConflict {
+		t.Fatalf("expected %v got %v", store.ErrConflict, err)
+	}
+	if err := s.CompareAndDelete("foo", []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Read("foo"); err != store.ErrNotFound {
+		t.Fatalf("expected %v got %v", store.ErrNotFound, err)
+	}
+}
+
+func TestEvictExpired(t *testing.T) {
+	s := NewStore().(*memoryStore)
+
+	for i := 0; i < 100; i++ {
+		if err := s.WriteWith(&store.Record{Key: fmt.Sprintf("key%d", i)}, store.WriteTTL(time.Millisecond), store.WriteTo("", "seen")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(time.Millisecond * 5)
+
+	// the expired records are evicted as the table is written to
+	for i := 0; i < 100; i++ {
+		if err := s.WriteWith(&store.Record{Key: "live"}, store.WriteTo("", "seen")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(s.tables[s.table("", "seen")]); n != 1 {
+		t.Fatalf("expected 1 record got %d", n)
+	}
+}
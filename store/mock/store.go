@@ -30,13 +30,40 @@ func (_m *Store) Delete(key ...string) error {
 	return r0
 }
 
-// List provides a mock function with given fields:
-func (_m *Store) List() ([]*store.Record, error) {
-	ret := _m.Called()
+// DeleteWith provides a mock function with given fields: key, opts
+func (_m *Store) DeleteWith(key string, opts ...store.DeleteOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, key)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, ...store.DeleteOption) error); ok {
+		r0 = rf(key, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// List provides a mock function with given fields: opts
+func (_m *Store) List(opts ...store.ListOption) ([]*store.Record, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []*store.Record
-	if rf, ok := ret.Get(0).(func() []*store.Record); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(...store.ListOption) []*store.Record); ok {
+		r0 = rf(opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*store.Record)
@@ -44,8 +71,8 @@ func (_m *Store) List() ([]*store.Record, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(...store.ListOption) error); ok {
+		r1 = rf(opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -82,6 +109,36 @@ func (_m *Store) Read(key ...string) ([]*store.Record, error) {
 	return r0, r1
 }
 
+// ReadWith provides a mock function with given fields: key, opts
+func (_m *Store) ReadWith(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, key)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*store.Record
+	if rf, ok := ret.Get(0).(func(string, ...store.ReadOption) []*store.Record); ok {
+		r0 = rf(key, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*store.Record)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, ...store.ReadOption) error); ok {
+		r1 = rf(key, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Write provides a mock function with given fields: rec
 func (_m *Store) Write(rec ...*store.Record) error {
 	_va := make([]interface{}, len(rec))
@@ -101,3 +158,24 @@ func (_m *Store) Write(rec ...*store.Record) error {
 
 	return r0
 }
+
+// WriteWith provides a mock function with given fields: r, opts
+func (_m *Store) WriteWith(r *store.Record, opts ...store.WriteOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, r)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*store.Record, ...store.WriteOption) error); ok {
+		r0 = rf(r, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
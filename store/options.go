@@ -1,6 +1,8 @@
 package store
 
 import (
+	"time"
+
 	"github.com/stack-labs/stack/util/options"
 )
 
@@ -19,3 +21,156 @@ func Prefix(p string) options.Option {
 func Namespace(n string) options.Option {
 	return options.WithValue("store.namespace", n)
 }
+
+// ReadOptions configures an individual Read operation
+type ReadOptions struct {
+	// Namespace and Table override the defaults of the store
+	Namespace string
+	Table     string
+	// Prefix returns all records that are prefixed with key
+	Prefix bool
+	// Suffix returns all records that have the suffix key
+	Suffix bool
+	// Limit limits the number of returned records
+	Limit uint
+	// Offset when combined with Limit supports pagination
+	Offset uint
+}
+
+// ReadOption sets values in ReadOptions
+type ReadOption func(r *ReadOptions)
+
+// ReadFrom the namespace and table
+func ReadFrom(namespace, table string) ReadOption {
+	return func(r *ReadOptions) {
+		r.Namespace = namespace
+		r.Table = table
+	}
+}
+
+// ReadPrefix returns all records that are prefixed with key
+func ReadPrefix() ReadOption {
+	return func(r *ReadOptions) {
+		r.Prefix = true
+	}
+}
+
+// ReadSuffix returns all records that have the suffix key
+func ReadSuffix() ReadOption {
+	return func(r *ReadOptions) {
+		r.Suffix = true
+	}
+}
+
+// ReadLimit limits the number of responses to l
+func ReadLimit(l uint) ReadOption {
+	return func(r *ReadOptions) {
+		r.Limit = l
+	}
+}
+
+// ReadOffset starts returning responses from o. Use in conjunction with Limit for pagination
+func ReadOffset(o uint) ReadOption {
+	return func(r *ReadOptions) {
+		r.Offset = o
+	}
+}
+
+// WriteOptions configures an individual Write operation
+type WriteOptions struct {
+	// Namespace and Table override the defaults of the store
+	Namespace string
+	Table     string
+	// TTL overrides the expiry of the record
+	TTL time.Duration
+}
+
+// WriteOption sets values in WriteOptions
+type WriteOption func(w *WriteOptions)
+
+// WriteTo the namespace and table
+func WriteTo(namespace, table string) WriteOption {
+	return func(w *WriteOptions) {
+		w.Namespace = namespace
+		w.Table = table
+	}
+}
+
+// WriteTTL is the time the record expires after
+func WriteTTL(d time.Duration) WriteOption {
+	return func(w *WriteOptions) {
+		w.TTL = d
+	}
+}
+
+// DeleteOptions configures an individual Delete operation
+type DeleteOptions struct {
+	// Namespace and Table override the defaults of the store
+	Namespace string
+	Table     string
+}
+
+// DeleteOption sets values in DeleteOptions
+type DeleteOption func(d *DeleteOptions)
+
+// DeleteFrom the namespace and table
+func DeleteFrom(namespace, table string) DeleteOption {
+	return func(d *DeleteOptions) {
+		d.Namespace = namespace
+		d.Table = table
+	}
+}
+
+// ListOptions configures an individual List operation
+type ListOptions struct {
+	// Namespace and Table override the defaults of the store
+	Namespace string
+	Table     string
+	// Prefix returns all records that are prefixed with the value
+	Prefix string
+	// Suffix returns all records that have the suffix value
+	Suffix string
+	// Limit limits the number of returned records
+	Limit uint
+	// Offset when combined with Limit supports pagination
+	Offset uint
+}
+
+// ListOption sets values in ListOptions
+type ListOption func(l *ListOptions)
+
+// ListFrom the namespace and table
+func ListFrom(namespace, table string) ListOption {
+	return func(l *ListOptions) {
+		l.Namespace = namespace
+		l.Table = table
+	}
+}
+
+// ListPrefix returns all records that are prefixed with p
+func ListPrefix(p string) ListOption {
+	return func(l *ListOptions) {
+		l.Prefix = p
+	}
+}
+
+// ListSuffix returns all records that have the suffix s
+func ListSuffix(s string) ListOption {
+	return func(l *ListOptions) {
+		l.Suffix = s
+	}
+}
+
+// ListLimit limits the number of returned records to l
+func ListLimit(l uint) ListOption {
+	return func(lo *ListOptions) {
+		lo.Limit = l
+	}
+}
+
+// ListOffset starts returning records from o. Use in conjunction with Limit for pagination
+func ListOffset(o uint) ListOption {
+	return func(l *ListOptions) {
+		l.Offset = o
+	}
+}
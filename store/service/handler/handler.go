@@ -14,42 +14,156 @@ type Store struct {
 	Store store.Store
 }
 
+func (s *Store) read(req *pb.ReadRequest) ([]*store.Record, error) {
+	o := req.Options
+	if o == nil {
+		return s.Store.Read(req.Keys...)
+	}
+
+	opts := []store.ReadOption{
+		store.ReadFrom(o.Namespace, o.Table),
+		store.ReadLimit(uint(o.Limit)),
+		store.ReadOffset(uint(o.Offset)),
+	}
+	if o.Prefix {
+		opts = append(opts, store.ReadPrefix())
+	}
+	if o.Suffix {
+		opts = append(opts, store.ReadSuffix())
+	}
+
+	var records []*store.Record
+	for _, key := range req.Keys {
+		vals, err := s.Store.ReadWith(key, opts...)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, vals...)
+	}
+	return records, nil
+}
+
 func (s *Store) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadResponse) error {
-	vals, err := s.Store.Read(req.Keys...)
-	if err != nil {
+	vals, err := s.read(req)
+	if err == store.ErrNotFound {
+		return errors.NotFound("stack.rpc.store", err.Error())
+	} else if err != nil {
 		return errors.InternalServerError("stack.rpc.store", err.Error())
 	}
+
 	for _, val := range vals {
-		rsp.Records = append(rsp.Records, &pb.Record{
-			Key:    val.Key,
-			Value:  val.Value,
-			Expiry: int64(val.Expiry.Seconds()),
-		})
+		rsp.Records = append(rsp.Records, pb.NewRecord(val))
 	}
 	return nil
 }
 
 func (s *Store) Write(ctx context.Context, req *pb.WriteRequest, rsp *pb.WriteResponse) error {
 	records := make([]*store.Record, 0, len(req.Records))
-
 	for _, record := range req.Records {
-		records = append(records, &store.Record{
-			Key:    record.Key,
-			Value:  record.Value,
-			Expiry: time.Duration(record.Expiry) * time.Second,
-		})
+		records = append(records, record.StoreRecord())
+	}
+
+	o := req.Options
+	if o == nil {
+		if err := s.Store.Write(records...); err != nil {
+			return errors.InternalServerError("stack.rpc.store", err.Error())
+		}
+		return nil
+	}
+
+	opts := []store.WriteOption{
+		store.WriteTo(o.Namespace, o.Table),
+		store.WriteTTL(time.Duration(o.Ttl) * time.Millisecond),
+	}
+
+	for _, record := range records {
+		if err := s.Store.WriteWith(record, opts...); err != nil {
+			return errors.InternalServerError("stack.rpc.store", err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *Store) Delete(ctx context.Context, req *pb.DeleteRequest, rsp *pb.DeleteResponse) error {
+	o := req.Options
+	if o == nil {
+		if err := s.Store.Delete(req.Keys...); err != nil {
+			return errors.InternalServerError("stack.rpc.store", err.Error())
+		}
+		return nil
+	}
+
+	for _, key := range req.Keys {
+		if err := s.Store.DeleteWith(key, store.DeleteFrom(o.Namespace, o.Table)); err != nil {
+			return errors.InternalServerError("stack.rpc.store", err.Error())
+		}
+	}
+	return nil
+}
+
+// cas returns the compare and swap of the store, unsupported if it has none
+func (s *Store) cas() (store.CAS, error) {
+	cas, ok := s.Store.(store.CAS)
+	if !ok {
+		return nil, errors.New("stack.rpc.store", "store doesn't support compare and swap", 501)
 	}
+	return cas, nil
+}
 
-	err := s.Store.Write(records...)
+func (s *Store) CompareAndSwap(ctx context.Context, req *pb.CompareAndSwapRequest, rsp *pb.CompareAndSwapResponse) error {
+	cas, err := s.cas()
 	if err != nil {
+		return err
+	}
+	if req.Record == nil {
+		return errors.BadRequest("stack.rpc.store", "record is blank")
+	}
+
+	// an empty value is still expected to exist
+	old := req.Old
+	if req.Absent {
+		old = nil
+	} else if old == nil {
+		old = []byte{}
+	}
+
+	var opts []store.WriteOption
+	if o := req.Options; o != nil {
+		opts = append(opts,
+			store.WriteTo(o.Namespace, o.Table),
+			store.WriteTTL(time.Duration(o.Ttl)*time.Millisecond),
+		)
+	}
+
+	err = cas.CompareAndSwap(req.Record.StoreRecord(), old, opts...)
+	if err == store.ErrConflict {
+		return errors.Conflict("stack.rpc.store", err.Error())
+	} else if err != nil {
 		return errors.InternalServerError("stack.rpc.store", err.Error())
 	}
 	return nil
 }
 
-func (s *Store) Delete(ctx context.Context, req *pb.DeleteRequest, rsp *pb.DeleteResponse) error {
-	err := s.Store.Delete(req.Keys...)
+func (s *Store) CompareAndDelete(ctx context.Context, req *pb.CompareAndDeleteRequest, rsp *pb.CompareAndDeleteResponse) error {
+	cas, err := s.cas()
 	if err != nil {
+		return err
+	}
+
+	old := req.Old
+	if old == nil {
+		old = []byte{}
+	}
+
+	var opts []store.DeleteOption
+	if o := req.Options; o != nil {
+		opts = append(opts, store.DeleteFrom(o.Namespace, o.Table))
+	}
+
+	err = cas.CompareAndDelete(req.Key, old, opts...)
+	if err == store.ErrConflict {
+		return errors.Conflict("stack.rpc.store", err.Error())
+	} else if err != nil {
 		return errors.InternalServerError("stack.rpc.store", err.Error())
 	}
 	return nil
@@ -62,7 +176,17 @@ func (s *Store) List(ctx context.Context, req *pb.ListRequest, stream pb.Store_L
 	if len(req.Key) > 0 {
 		vals, err = s.Store.Read(req.Key)
 	} else {
-		vals, err = s.Store.List()
+		var opts []store.ListOption
+		if o := req.Options; o != nil {
+			opts = append(opts,
+				store.ListFrom(o.Namespace, o.Table),
+				store.ListPrefix(o.Prefix),
+				store.ListSuffix(o.Suffix),
+				store.ListLimit(uint(o.Limit)),
+				store.ListOffset(uint(o.Offset)),
+			)
+		}
+		vals, err = s.Store.List(opts...)
 	}
 	if err != nil {
 		return errors.InternalServerError("stack.rpc.store", err.Error())
@@ -71,11 +195,7 @@ func (s *Store) List(ctx context.Context, req *pb.ListRequest, stream pb.Store_L
 
 	// TODO: batch sync
 	for _, val := range vals {
-		rsp.Records = append(rsp.Records, &pb.Record{
-			Key:    val.Key,
-			Value:  val.Value,
-			Expiry: int64(val.Expiry.Seconds()),
-		})
+		rsp.Records = append(rsp.Records, pb.NewRecord(val))
 	}
 
 	err = stream.Send(rsp)
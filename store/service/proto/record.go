@@ -0,0 +1,45 @@
+package stack_rpc_store
+
+import (
+	"time"
+
+	"github.com/stack-labs/stack/store"
+)
+
+// ceil returns d in units rounded up, so a time to live shorter than the
+// unit isn't sent as zero, which never expires
+func ceil(d, unit time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + unit - 1) / unit)
+}
+
+// Milliseconds returns the ttl d in milliseconds, rounded up
+func Milliseconds(d time.Duration) int64 {
+	return ceil(d, time.Millisecond)
+}
+
+// NewRecord returns the record of r. The expiry is set in milliseconds, and
+// in seconds for the services which don't know of milliseconds.
+func NewRecord(r *store.Record) *Record {
+	return &Record{
+		Key:      r.Key,
+		Value:    r.Value,
+		Expiry:   ceil(r.Expiry, time.Second),
+		ExpiryMs: ceil(r.Expiry, time.Millisecond),
+	}
+}
+
+// StoreRecord returns the store record of m
+func (m *Record) StoreRecord() *store.Record {
+	expiry := time.Duration(m.ExpiryMs) * time.Millisecond
+	if m.ExpiryMs == 0 {
+		expiry = time.Duration(m.Expiry) * time.Second
+	}
+	return &store.Record{
+		Key:    m.Key,
+		Value:  m.Value,
+		Expiry: expiry,
+	}
+}
@@ -25,8 +25,10 @@ type Record struct {
 	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	// value in the record
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	// timestamp in unix seconds
-	Expiry               int64    `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	// remaining time to live in seconds, rounded up
+	Expiry int64 `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	// remaining time to live in milliseconds, rounded up
+	ExpiryMs             int64    `protobuf:"varint,4,opt,name=expiry_ms,json=expiryMs,proto3" json:"expiry_ms,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -78,18 +80,105 @@ func (m *Record) GetExpiry() int64 {
 	return 0
 }
 
-type ReadRequest struct {
-	Keys                 []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+func (m *Record) GetExpiryMs() int64 {
+	if m != nil {
+		return m.ExpiryMs
+	}
+	return 0
+}
+
+type ReadOptions struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Table                string   `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Prefix               bool     `protobuf:"varint,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Suffix               bool     `protobuf:"varint,4,opt,name=suffix,proto3" json:"suffix,omitempty"`
+	Limit                uint64   `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               uint64   `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *ReadOptions) Reset()         { *m = ReadOptions{} }
+func (m *ReadOptions) String() string { return proto.CompactTextString(m) }
+func (*ReadOptions) ProtoMessage()    {}
+func (*ReadOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{1}
+}
+
+func (m *ReadOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadOptions.Unmarshal(m, b)
+}
+func (m *ReadOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadOptions.Marshal(b, m, deterministic)
+}
+func (m *ReadOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadOptions.Merge(m, src)
+}
+func (m *ReadOptions) XXX_Size() int {
+	return xxx_messageInfo_ReadOptions.Size(m)
+}
+func (m *ReadOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadOptions proto.InternalMessageInfo
+
+func (m *ReadOptions) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ReadOptions) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *ReadOptions) GetPrefix() bool {
+	if m != nil {
+		return m.Prefix
+	}
+	return false
+}
+
+func (m *ReadOptions) GetSuffix() bool {
+	if m != nil {
+		return m.Suffix
+	}
+	return false
+}
+
+func (m *ReadOptions) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ReadOptions) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type ReadRequest struct {
+	Keys                 []string     `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Options              *ReadOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
 func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
 func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
 func (*ReadRequest) ProtoMessage()    {}
 func (*ReadRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{1}
+	return fileDescriptor_98bbca36ef968dfc, []int{2}
 }
 
 func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
@@ -117,6 +206,13 @@ func (m *ReadRequest) GetKeys() []string {
 	return nil
 }
 
+func (m *ReadRequest) GetOptions() *ReadOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
 type ReadResponse struct {
 	Records              []*Record `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
@@ -128,7 +224,7 @@ func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
 func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse) ProtoMessage()    {}
 func (*ReadResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{2}
+	return fileDescriptor_98bbca36ef968dfc, []int{3}
 }
 
 func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
@@ -156,18 +252,75 @@ func (m *ReadResponse) GetRecords() []*Record {
 	return nil
 }
 
+type WriteOptions struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Table     string `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	// ttl in milliseconds overriding the record expiry
+	Ttl                  int64    `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteOptions) Reset()         { *m = WriteOptions{} }
+func (m *WriteOptions) String() string { return proto.CompactTextString(m) }
+func (*WriteOptions) ProtoMessage()    {}
+func (*WriteOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{4}
+}
+
+func (m *WriteOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteOptions.Unmarshal(m, b)
+}
+func (m *WriteOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteOptions.Marshal(b, m, deterministic)
+}
+func (m *WriteOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteOptions.Merge(m, src)
+}
+func (m *WriteOptions) XXX_Size() int {
+	return xxx_messageInfo_WriteOptions.Size(m)
+}
+func (m *WriteOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteOptions proto.InternalMessageInfo
+
+func (m *WriteOptions) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *WriteOptions) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *WriteOptions) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
 type WriteRequest struct {
-	Records              []*Record `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	Records              []*Record     `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	Options              *WriteOptions `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
 func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
 func (*WriteRequest) ProtoMessage()    {}
 func (*WriteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{3}
+	return fileDescriptor_98bbca36ef968dfc, []int{5}
 }
 
 func (m *WriteRequest) XXX_Unmarshal(b []byte) error {
@@ -195,6 +348,13 @@ func (m *WriteRequest) GetRecords() []*Record {
 	return nil
 }
 
+func (m *WriteRequest) GetOptions() *WriteOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
 type WriteResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -205,7 +365,7 @@ func (m *WriteResponse) Reset()         { *m = WriteResponse{} }
 func (m *WriteResponse) String() string { return proto.CompactTextString(m) }
 func (*WriteResponse) ProtoMessage()    {}
 func (*WriteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{4}
+	return fileDescriptor_98bbca36ef968dfc, []int{6}
 }
 
 func (m *WriteResponse) XXX_Unmarshal(b []byte) error {
@@ -226,18 +386,66 @@ func (m *WriteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_WriteResponse proto.InternalMessageInfo
 
-type DeleteRequest struct {
-	Keys                 []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+type DeleteOptions struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Table                string   `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *DeleteOptions) Reset()         { *m = DeleteOptions{} }
+func (m *DeleteOptions) String() string { return proto.CompactTextString(m) }
+func (*DeleteOptions) ProtoMessage()    {}
+func (*DeleteOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{7}
+}
+
+func (m *DeleteOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteOptions.Unmarshal(m, b)
+}
+func (m *DeleteOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteOptions.Marshal(b, m, deterministic)
+}
+func (m *DeleteOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteOptions.Merge(m, src)
+}
+func (m *DeleteOptions) XXX_Size() int {
+	return xxx_messageInfo_DeleteOptions.Size(m)
+}
+func (m *DeleteOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteOptions proto.InternalMessageInfo
+
+func (m *DeleteOptions) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *DeleteOptions) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+type DeleteRequest struct {
+	Keys                 []string       `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Options              *DeleteOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
 func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{5}
+	return fileDescriptor_98bbca36ef968dfc, []int{8}
 }
 
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
@@ -265,6 +473,13 @@ func (m *DeleteRequest) GetKeys() []string {
 	return nil
 }
 
+func (m *DeleteRequest) GetOptions() *DeleteOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
 type DeleteResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -275,7 +490,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{6}
+	return fileDescriptor_98bbca36ef968dfc, []int{9}
 }
 
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
@@ -296,19 +511,99 @@ func (m *DeleteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DeleteResponse proto.InternalMessageInfo
 
-type ListRequest struct {
-	// optional key
-	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+type ListOptions struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Table                string   `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Prefix               string   `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Suffix               string   `protobuf:"bytes,4,opt,name=suffix,proto3" json:"suffix,omitempty"`
+	Limit                uint64   `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               uint64   `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *ListOptions) Reset()         { *m = ListOptions{} }
+func (m *ListOptions) String() string { return proto.CompactTextString(m) }
+func (*ListOptions) ProtoMessage()    {}
+func (*ListOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{10}
+}
+
+func (m *ListOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOptions.Unmarshal(m, b)
+}
+func (m *ListOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOptions.Marshal(b, m, deterministic)
+}
+func (m *ListOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOptions.Merge(m, src)
+}
+func (m *ListOptions) XXX_Size() int {
+	return xxx_messageInfo_ListOptions.Size(m)
+}
+func (m *ListOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOptions proto.InternalMessageInfo
+
+func (m *ListOptions) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ListOptions) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *ListOptions) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListOptions) GetSuffix() string {
+	if m != nil {
+		return m.Suffix
+	}
+	return ""
+}
+
+func (m *ListOptions) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListOptions) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type ListRequest struct {
+	// optional key
+	Key                  string       `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Options              *ListOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
 func (m *ListRequest) Reset()         { *m = ListRequest{} }
 func (m *ListRequest) String() string { return proto.CompactTextString(m) }
 func (*ListRequest) ProtoMessage()    {}
 func (*ListRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{7}
+	return fileDescriptor_98bbca36ef968dfc, []int{11}
 }
 
 func (m *ListRequest) XXX_Unmarshal(b []byte) error {
@@ -336,6 +631,13 @@ func (m *ListRequest) GetKey() string {
 	return ""
 }
 
+func (m *ListRequest) GetOptions() *ListOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
 type ListResponse struct {
 	Records              []*Record `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
@@ -347,7 +649,7 @@ func (m *ListResponse) Reset()         { *m = ListResponse{} }
 func (m *ListResponse) String() string { return proto.CompactTextString(m) }
 func (*ListResponse) ProtoMessage()    {}
 func (*ListResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{8}
+	return fileDescriptor_98bbca36ef968dfc, []int{12}
 }
 
 func (m *ListResponse) XXX_Unmarshal(b []byte) error {
@@ -375,40 +677,250 @@ func (m *ListResponse) GetRecords() []*Record {
 	return nil
 }
 
+type CompareAndSwapRequest struct {
+	Record *Record `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// value the key is expected to have
+	Old []byte `protobuf:"bytes,2,opt,name=old,proto3" json:"old,omitempty"`
+	// the key is expected not to exist, old is ignored
+	Absent               bool          `protobuf:"varint,3,opt,name=absent,proto3" json:"absent,omitempty"`
+	Options              *WriteOptions `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *CompareAndSwapRequest) Reset()         { *m = CompareAndSwapRequest{} }
+func (m *CompareAndSwapRequest) String() string { return proto.CompactTextString(m) }
+func (*CompareAndSwapRequest) ProtoMessage()    {}
+func (*CompareAndSwapRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{13}
+}
+
+func (m *CompareAndSwapRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndSwapRequest.Unmarshal(m, b)
+}
+func (m *CompareAndSwapRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndSwapRequest.Marshal(b, m, deterministic)
+}
+func (m *CompareAndSwapRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndSwapRequest.Merge(m, src)
+}
+func (m *CompareAndSwapRequest) XXX_Size() int {
+	return xxx_messageInfo_CompareAndSwapRequest.Size(m)
+}
+func (m *CompareAndSwapRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndSwapRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndSwapRequest proto.InternalMessageInfo
+
+func (m *CompareAndSwapRequest) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+func (m *CompareAndSwapRequest) GetOld() []byte {
+	if m != nil {
+		return m.Old
+	}
+	return nil
+}
+
+func (m *CompareAndSwapRequest) GetAbsent() bool {
+	if m != nil {
+		return m.Absent
+	}
+	return false
+}
+
+func (m *CompareAndSwapRequest) GetOptions() *WriteOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type CompareAndSwapResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompareAndSwapResponse) Reset()         { *m = CompareAndSwapResponse{} }
+func (m *CompareAndSwapResponse) String() string { return proto.CompactTextString(m) }
+func (*CompareAndSwapResponse) ProtoMessage()    {}
+func (*CompareAndSwapResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{14}
+}
+
+func (m *CompareAndSwapResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndSwapResponse.Unmarshal(m, b)
+}
+func (m *CompareAndSwapResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndSwapResponse.Marshal(b, m, deterministic)
+}
+func (m *CompareAndSwapResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndSwapResponse.Merge(m, src)
+}
+func (m *CompareAndSwapResponse) XXX_Size() int {
+	return xxx_messageInfo_CompareAndSwapResponse.Size(m)
+}
+func (m *CompareAndSwapResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndSwapResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndSwapResponse proto.InternalMessageInfo
+
+type CompareAndDeleteRequest struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// value the key is expected to have
+	Old                  []byte         `protobuf:"bytes,2,opt,name=old,proto3" json:"old,omitempty"`
+	Options              *DeleteOptions `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CompareAndDeleteRequest) Reset()         { *m = CompareAndDeleteRequest{} }
+func (m *CompareAndDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*CompareAndDeleteRequest) ProtoMessage()    {}
+func (*CompareAndDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{15}
+}
+
+func (m *CompareAndDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndDeleteRequest.Unmarshal(m, b)
+}
+func (m *CompareAndDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *CompareAndDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndDeleteRequest.Merge(m, src)
+}
+func (m *CompareAndDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_CompareAndDeleteRequest.Size(m)
+}
+func (m *CompareAndDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndDeleteRequest proto.InternalMessageInfo
+
+func (m *CompareAndDeleteRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *CompareAndDeleteRequest) GetOld() []byte {
+	if m != nil {
+		return m.Old
+	}
+	return nil
+}
+
+func (m *CompareAndDeleteRequest) GetOptions() *DeleteOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type CompareAndDeleteResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompareAndDeleteResponse) Reset()         { *m = CompareAndDeleteResponse{} }
+func (m *CompareAndDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*CompareAndDeleteResponse) ProtoMessage()    {}
+func (*CompareAndDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{16}
+}
+
+func (m *CompareAndDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndDeleteResponse.Unmarshal(m, b)
+}
+func (m *CompareAndDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndDeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *CompareAndDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndDeleteResponse.Merge(m, src)
+}
+func (m *CompareAndDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_CompareAndDeleteResponse.Size(m)
+}
+func (m *CompareAndDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndDeleteResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*Record)(nil), "stack.rpc.store.Record")
+	proto.RegisterType((*ReadOptions)(nil), "stack.rpc.store.ReadOptions")
 	proto.RegisterType((*ReadRequest)(nil), "stack.rpc.store.ReadRequest")
 	proto.RegisterType((*ReadResponse)(nil), "stack.rpc.store.ReadResponse")
+	proto.RegisterType((*WriteOptions)(nil), "stack.rpc.store.WriteOptions")
 	proto.RegisterType((*WriteRequest)(nil), "stack.rpc.store.WriteRequest")
 	proto.RegisterType((*WriteResponse)(nil), "stack.rpc.store.WriteResponse")
+	proto.RegisterType((*DeleteOptions)(nil), "stack.rpc.store.DeleteOptions")
 	proto.RegisterType((*DeleteRequest)(nil), "stack.rpc.store.DeleteRequest")
 	proto.RegisterType((*DeleteResponse)(nil), "stack.rpc.store.DeleteResponse")
+	proto.RegisterType((*ListOptions)(nil), "stack.rpc.store.ListOptions")
 	proto.RegisterType((*ListRequest)(nil), "stack.rpc.store.ListRequest")
 	proto.RegisterType((*ListResponse)(nil), "stack.rpc.store.ListResponse")
+	proto.RegisterType((*CompareAndSwapRequest)(nil), "stack.rpc.store.CompareAndSwapRequest")
+	proto.RegisterType((*CompareAndSwapResponse)(nil), "stack.rpc.store.CompareAndSwapResponse")
+	proto.RegisterType((*CompareAndDeleteRequest)(nil), "stack.rpc.store.CompareAndDeleteRequest")
+	proto.RegisterType((*CompareAndDeleteResponse)(nil), "stack.rpc.store.CompareAndDeleteResponse")
 }
 
 func init() { proto.RegisterFile("store.proto", fileDescriptor_98bbca36ef968dfc) }
 
 var fileDescriptor_98bbca36ef968dfc = []byte{
-	// 317 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xc1, 0x4e, 0x83, 0x40,
-	0x10, 0x86, 0xbb, 0xa5, 0xc5, 0x74, 0x68, 0x6d, 0x33, 0x31, 0x4a, 0x1a, 0xb5, 0x88, 0x17, 0x4e,
-	0x44, 0xeb, 0x13, 0x98, 0x68, 0x6c, 0xa2, 0xa7, 0xf5, 0xe0, 0xb9, 0xd2, 0x39, 0x10, 0x1a, 0xc1,
-	0xdd, 0xad, 0x91, 0x97, 0xf2, 0x19, 0xcd, 0xb2, 0xa0, 0x28, 0x90, 0x18, 0x6f, 0xbb, 0xcc, 0x3f,
-	0xdf, 0xfc, 0xcc, 0xbf, 0xe0, 0x48, 0x95, 0x0a, 0x0a, 0x33, 0x91, 0xaa, 0x14, 0xa7, 0x52, 0xad,
-	0xa3, 0x24, 0x14, 0x59, 0x14, 0x16, 0x9f, 0xfd, 0x15, 0xd8, 0x9c, 0xa2, 0x54, 0x6c, 0x70, 0x06,
-	0x56, 0x42, 0xb9, 0xcb, 0x3c, 0x16, 0x8c, 0xb8, 0x3e, 0xe2, 0x01, 0x0c, 0xdf, 0xd6, 0xdb, 0x1d,
-	0xb9, 0x7d, 0x8f, 0x05, 0x63, 0x6e, 0x2e, 0x78, 0x08, 0x36, 0xbd, 0x67, 0xb1, 0xc8, 0x5d, 0xcb,
-	0x63, 0x81, 0xc5, 0xcb, 0x9b, 0x7f, 0x06, 0x0e, 0xa7, 0xf5, 0x86, 0xd3, 0xeb, 0x8e, 0xa4, 0x42,
-	0x84, 0x41, 0x42, 0xb9, 0x74, 0x99, 0x67, 0x05, 0x23, 0x5e, 0x9c, 0xfd, 0x6b, 0x18, 0x1b, 0x89,
-	0xcc, 0xd2, 0x17, 0x49, 0x78, 0x09, 0x7b, 0xa2, 0x18, 0x6e, 0x64, 0xce, 0xf2, 0x28, 0xfc, 0xe5,
-	0x2f, 0x34, 0xe6, 0x78, 0xa5, 0xd3, 0x88, 0x27, 0x11, 0x2b, 0xaa, 0xc6, 0xd4, 0x10, 0xfd, 0x3f,
-	0x22, 0xa6, 0x30, 0x29, 0x11, 0xc6, 0x86, 0x7f, 0x0e, 0x93, 0x1b, 0xda, 0xd2, 0x37, 0xb4, 0xcd,
-	0xfb, 0x0c, 0xf6, 0x2b, 0x51, 0xd9, 0xb6, 0x00, 0xe7, 0x21, 0x96, 0xaa, 0x6a, 0x6a, 0xec, 0x4f,
-	0x7b, 0x35, 0x82, 0x7f, 0xff, 0xee, 0xf2, 0xa3, 0x0f, 0xc3, 0x47, 0x5d, 0xc1, 0x3b, 0x18, 0x68,
-	0x18, 0x1e, 0x37, 0x7a, 0x6a, 0x26, 0xe6, 0x27, 0x1d, 0xd5, 0xd2, 0x72, 0xef, 0x82, 0xe1, 0x2d,
-	0x0c, 0x74, 0x08, 0x2d, 0xa0, 0x5a, 0x7c, 0x2d, 0xa0, 0x7a, 0x72, 0x7e, 0x0f, 0x57, 0x30, 0x2c,
-	0xb6, 0x88, 0x4d, 0x65, 0x3d, 0xa0, 0xf9, 0x69, 0x57, 0xf9, 0x8b, 0x74, 0x0f, 0xb6, 0xd9, 0x2c,
-	0x36, 0xb5, 0x3f, 0x72, 0x99, 0x2f, 0x3a, 0xeb, 0x15, 0xec, 0xd9, 0x2e, 0xde, 0xf9, 0xd5, 0x67,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x99, 0xc4, 0x25, 0xd1, 0xf6, 0x02, 0x00, 0x00,
+	// 615 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xdd, 0x6e, 0xd3, 0x4c,
+	0x10, 0xad, 0x6b, 0xc7, 0xad, 0x27, 0x69, 0x1b, 0xad, 0xbe, 0xaf, 0xb5, 0x42, 0x5b, 0x22, 0x5f,
+	0x80, 0xb9, 0x09, 0x10, 0x24, 0xe8, 0x6d, 0x55, 0x10, 0x48, 0x80, 0x90, 0xb6, 0x48, 0x15, 0x17,
+	0x08, 0x39, 0xc9, 0x46, 0xb2, 0xe2, 0x64, 0x8d, 0x77, 0x43, 0x1b, 0x5e, 0x86, 0x07, 0xe0, 0x1d,
+	0x11, 0xda, 0xbf, 0xc4, 0xb1, 0xe3, 0x10, 0x5a, 0xee, 0x76, 0x66, 0xc7, 0x67, 0xce, 0x9c, 0x39,
+	0xd9, 0x40, 0x9d, 0x71, 0x9a, 0x91, 0x4e, 0x9a, 0x51, 0x4e, 0xd1, 0x01, 0xe3, 0x51, 0x7f, 0xd4,
+	0xc9, 0xd2, 0x7e, 0x47, 0xa6, 0x03, 0x02, 0x2e, 0x26, 0x7d, 0x9a, 0x0d, 0x50, 0x13, 0xec, 0x11,
+	0x99, 0xf9, 0x56, 0xdb, 0x0a, 0x3d, 0x2c, 0x8e, 0xe8, 0x3f, 0xa8, 0x7d, 0x8b, 0x92, 0x29, 0xf1,
+	0xb7, 0xdb, 0x56, 0xd8, 0xc0, 0x2a, 0x40, 0x87, 0xe0, 0x92, 0x9b, 0x34, 0xce, 0x66, 0xbe, 0xdd,
+	0xb6, 0x42, 0x1b, 0xeb, 0x08, 0xdd, 0x03, 0x4f, 0x9d, 0xbe, 0x8c, 0x99, 0xef, 0xc8, 0xab, 0x5d,
+	0x95, 0x78, 0xcf, 0x82, 0x1f, 0x16, 0xd4, 0x31, 0x89, 0x06, 0x1f, 0x52, 0x1e, 0xd3, 0x09, 0x43,
+	0xc7, 0xe0, 0x4d, 0xa2, 0x31, 0x61, 0x69, 0xd4, 0x27, 0xba, 0xe5, 0x22, 0x21, 0x1a, 0xf3, 0xa8,
+	0x97, 0xa8, 0xc6, 0x1e, 0x56, 0x81, 0x68, 0x9c, 0x66, 0x64, 0x18, 0xdf, 0xc8, 0xc6, 0xbb, 0x58,
+	0x47, 0x22, 0xcf, 0xa6, 0x43, 0x91, 0x77, 0x54, 0x5e, 0x45, 0x02, 0x25, 0x89, 0xc7, 0x31, 0xf7,
+	0x6b, 0x6d, 0x2b, 0x74, 0xb0, 0x0a, 0x44, 0x35, 0x1d, 0x0e, 0x19, 0xe1, 0xbe, 0x2b, 0xd3, 0x3a,
+	0x0a, 0x3e, 0x29, 0x82, 0x98, 0x7c, 0x9d, 0x12, 0xc6, 0x11, 0x02, 0x67, 0x44, 0x66, 0xcc, 0xb7,
+	0xda, 0x76, 0xe8, 0x61, 0x79, 0x46, 0xcf, 0x61, 0x87, 0x2a, 0xfe, 0x92, 0x58, 0xbd, 0x7b, 0xdc,
+	0x29, 0xc8, 0xd9, 0xc9, 0xcd, 0x88, 0x4d, 0x71, 0x70, 0x0e, 0x0d, 0x05, 0xcd, 0x52, 0x3a, 0x61,
+	0x04, 0x3d, 0x85, 0x9d, 0x4c, 0x6a, 0xae, 0xe0, 0xeb, 0xdd, 0xa3, 0x15, 0x38, 0xe2, 0x1e, 0x9b,
+	0xba, 0xe0, 0x23, 0x34, 0xae, 0xb2, 0x98, 0x93, 0xbb, 0xe8, 0xd7, 0x04, 0x9b, 0xf3, 0x44, 0x6f,
+	0x4d, 0x1c, 0x83, 0xef, 0x1a, 0xd5, 0x0c, 0x9d, 0x23, 0xb6, 0xbd, 0x19, 0x31, 0xf4, 0x62, 0xa1,
+	0x89, 0x2d, 0x35, 0x39, 0x29, 0x7d, 0x92, 0x27, 0xbe, 0x10, 0xe5, 0x00, 0xf6, 0x74, 0x6f, 0xa5,
+	0x4a, 0x70, 0x01, 0x7b, 0x2f, 0x49, 0x42, 0xee, 0x34, 0x63, 0xf0, 0xd9, 0x80, 0xac, 0xdb, 0xe3,
+	0x59, 0x71, 0x8f, 0xa7, 0x25, 0xce, 0x4b, 0x4c, 0x16, 0xa4, 0x9b, 0xb0, 0x6f, 0xe0, 0x35, 0x6b,
+	0x61, 0xec, 0x77, 0x31, 0xe3, 0xff, 0xce, 0xd8, 0x5e, 0x85, 0xb1, 0xbd, 0x5b, 0x1a, 0xfb, 0x4a,
+	0x11, 0x34, 0x82, 0x94, 0x7f, 0xe6, 0x1b, 0xd8, 0x3a, 0x37, 0xe1, 0x92, 0xad, 0x15, 0xf0, 0xed,
+	0x6d, 0xfd, 0xd3, 0x82, 0xff, 0x2f, 0xe8, 0x38, 0x8d, 0x32, 0x72, 0x3e, 0x19, 0x5c, 0x5e, 0x47,
+	0xa9, 0xa1, 0xf9, 0x18, 0x5c, 0x55, 0x24, 0x99, 0xae, 0xc1, 0xd2, 0x65, 0x62, 0x2e, 0x9a, 0x0c,
+	0xf4, 0x53, 0x25, 0x8e, 0x42, 0x90, 0xa8, 0xc7, 0xc8, 0x84, 0x9b, 0xf7, 0x42, 0x45, 0x79, 0xcb,
+	0x3a, 0x7f, 0x65, 0x59, 0x1f, 0x0e, 0x8b, 0x64, 0xb5, 0x0b, 0xae, 0xe1, 0x68, 0x71, 0xb3, 0x6c,
+	0xc0, 0xb2, 0xde, 0x65, 0xa6, 0x67, 0xc5, 0x1f, 0xd1, 0xc6, 0x86, 0x6c, 0x81, 0x5f, 0x6e, 0xac,
+	0x48, 0x75, 0x7f, 0xd9, 0x50, 0xbb, 0x14, 0x1f, 0xa3, 0xd7, 0xe0, 0x88, 0x4d, 0xa1, 0xd5, 0x8b,
+	0xd5, 0x4c, 0x5b, 0x27, 0x15, 0xb7, 0x7a, 0xc6, 0xad, 0x27, 0x16, 0x7a, 0x05, 0x8e, 0x78, 0xc9,
+	0xd0, 0xea, 0x87, 0xaf, 0x1a, 0x28, 0xff, 0xfc, 0x05, 0x5b, 0xe8, 0x0d, 0xd4, 0xa4, 0xc2, 0xa8,
+	0x42, 0x79, 0x03, 0x74, 0x5a, 0x75, 0x3d, 0x47, 0x7a, 0x0b, 0xae, 0x9a, 0x1a, 0x55, 0x49, 0x66,
+	0xb0, 0xee, 0x57, 0xde, 0xcf, 0xc1, 0xfa, 0xb0, 0xbf, 0xbc, 0x5f, 0xf4, 0xa0, 0xf4, 0xd1, 0x4a,
+	0xb7, 0xb6, 0x1e, 0xfe, 0xb1, 0x6e, 0xde, 0x24, 0x86, 0x66, 0x71, 0x63, 0x28, 0x5c, 0xf3, 0xf9,
+	0xf2, 0x14, 0x8f, 0x36, 0xa8, 0x34, 0xad, 0x7a, 0xae, 0xfc, 0xcf, 0x7f, 0xf6, 0x7b, 0x00, 0x16,
+	0x96, 0xd4, 0xb7, 0x02, 0x08, 0x00, 0x00,
 }
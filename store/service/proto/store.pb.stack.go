@@ -48,6 +48,8 @@ type StoreService interface {
 	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
 	Write(ctx context.Context, in *WriteRequest, opts ...client.CallOption) (*WriteResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...client.CallOption) (*DeleteResponse, error)
+	CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...client.CallOption) (*CompareAndSwapResponse, error)
+	CompareAndDelete(ctx context.Context, in *CompareAndDeleteRequest, opts ...client.CallOption) (*CompareAndDeleteResponse, error)
 }
 
 type storeService struct {
@@ -141,6 +143,26 @@ func (c *storeService) Delete(ctx context.Context, in *DeleteRequest, opts ...cl
 	return out, nil
 }
 
+func (c *storeService) CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...client.CallOption) (*CompareAndSwapResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.CompareAndSwap", in)
+	out := new(CompareAndSwapResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeService) CompareAndDelete(ctx context.Context, in *CompareAndDeleteRequest, opts ...client.CallOption) (*CompareAndDeleteResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.CompareAndDelete", in)
+	out := new(CompareAndDeleteResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Store service
 
 type StoreHandler interface {
@@ -148,6 +170,8 @@ type StoreHandler interface {
 	Read(context.Context, *ReadRequest, *ReadResponse) error
 	Write(context.Context, *WriteRequest, *WriteResponse) error
 	Delete(context.Context, *DeleteRequest, *DeleteResponse) error
+	CompareAndSwap(context.Context, *CompareAndSwapRequest, *CompareAndSwapResponse) error
+	CompareAndDelete(context.Context, *CompareAndDeleteRequest, *CompareAndDeleteResponse) error
 }
 
 func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.HandlerOption) error {
@@ -156,6 +180,8 @@ func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.Han
 		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
 		Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error
 		Delete(ctx context.Context, in *DeleteRequest, out *DeleteResponse) error
+		CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, out *CompareAndSwapResponse) error
+		CompareAndDelete(ctx context.Context, in *CompareAndDeleteRequest, out *CompareAndDeleteResponse) error
 	}
 	type Store struct {
 		store
@@ -219,3 +245,11 @@ func (h *storeHandler) Write(ctx context.Context, in *WriteRequest, out *WriteRe
 func (h *storeHandler) Delete(ctx context.Context, in *DeleteRequest, out *DeleteResponse) error {
 	return h.StoreHandler.Delete(ctx, in, out)
 }
+
+func (h *storeHandler) CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, out *CompareAndSwapResponse) error {
+	return h.StoreHandler.CompareAndSwap(ctx, in, out)
+}
+
+func (h *storeHandler) CompareAndDelete(ctx context.Context, in *CompareAndDeleteRequest, out *CompareAndDeleteResponse) error {
+	return h.StoreHandler.CompareAndDelete(ctx, in, out)
+}
@@ -4,13 +4,13 @@ package service
 import (
 	"context"
 	"io"
-	"time"
 
 	"github.com/stack-labs/stack/client/mucp"
 
 	"github.com/stack-labs/stack/client"
 	"github.com/stack-labs/stack/store"
 	pb "github.com/stack-labs/stack/store/service/proto"
+	"github.com/stack-labs/stack/util/errors"
 	"github.com/stack-labs/stack/util/options"
 )
 
@@ -24,15 +24,29 @@ type serviceStore struct {
 	Client pb.StoreService
 }
 
-// Sync all the known records
-func (s *serviceStore) List() ([]*store.Record, error) {
-	stream, err := s.Client.List(context.Background(), &pb.ListRequest{}, client.WithAddress(s.Nodes...))
+// List all the known records
+func (s *serviceStore) List(opts ...store.ListOption) ([]*store.Record, error) {
+	var options store.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	stream, err := s.Client.List(context.Background(), &pb.ListRequest{
+		Options: &pb.ListOptions{
+			Namespace: options.Namespace,
+			Table:     options.Table,
+			Prefix:    options.Prefix,
+			Suffix:    options.Suffix,
+			Limit:     uint64(options.Limit),
+			Offset:    uint64(options.Offset),
+		},
+	}, client.WithAddress(s.Nodes...))
 	if err != nil {
 		return nil, err
 	}
 	defer stream.Close()
 
-	var records []*store.Record
+	var recs []*store.Record
 
 	for {
 		rsp, err := stream.Recv()
@@ -40,50 +54,68 @@ func (s *serviceStore) List() ([]*store.Record, error) {
 			break
 		}
 		if err != nil {
-			return records, err
-		}
-		for _, record := range rsp.Records {
-			records = append(records, &store.Record{
-				Key:    record.Key,
-				Value:  record.Value,
-				Expiry: time.Duration(record.Expiry) * time.Second,
-			})
+			return recs, err
 		}
+		recs = append(recs, records(rsp.Records)...)
 	}
 
-	return records, nil
+	return recs, nil
 }
 
-// Read a record with key
-func (s *serviceStore) Read(keys ...string) ([]*store.Record, error) {
-	rsp, err := s.Client.Read(context.Background(), &pb.ReadRequest{
-		Keys: keys,
-	}, client.WithAddress(s.Nodes...))
-	if err != nil {
+// records converts the records of a response
+func records(recs []*pb.Record) []*store.Record {
+	records := make([]*store.Record, 0, len(recs))
+	for _, val := range recs {
+		records = append(records, val.StoreRecord())
+	}
+	return records
+}
+
+// read the records of the request
+func (s *serviceStore) read(req *pb.ReadRequest) ([]*store.Record, error) {
+	rsp, err := s.Client.Read(context.Background(), req, client.WithAddress(s.Nodes...))
+	if err != nil && errors.Parse(err.Error()).Code == 404 {
+		return nil, store.ErrNotFound
+	} else if err != nil {
 		return nil, err
 	}
 
-	records := make([]*store.Record, 0, len(rsp.Records))
-	for _, val := range rsp.Records {
-		records = append(records, &store.Record{
-			Key:    val.Key,
-			Value:  val.Value,
-			Expiry: time.Duration(val.Expiry) * time.Second,
-		})
+	return records(rsp.Records), nil
+}
+
+// Read records with keys
+func (s *serviceStore) Read(keys ...string) ([]*store.Record, error) {
+	return s.read(&pb.ReadRequest{
+		Keys: keys,
+	})
+}
+
+// ReadWith reads the records of the key with options
+func (s *serviceStore) ReadWith(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
 	}
-	return records, nil
+
+	return s.read(&pb.ReadRequest{
+		Keys: []string{key},
+		Options: &pb.ReadOptions{
+			Namespace: options.Namespace,
+			Table:     options.Table,
+			Prefix:    options.Prefix,
+			Suffix:    options.Suffix,
+			Limit:     uint64(options.Limit),
+			Offset:    uint64(options.Offset),
+		},
+	})
 }
 
-// Write a record
+// Write records
 func (s *serviceStore) Write(recs ...*store.Record) error {
 	records := make([]*pb.Record, 0, len(recs))
 
 	for _, record := range recs {
-		records = append(records, &pb.Record{
-			Key:    record.Key,
-			Value:  record.Value,
-			Expiry: int64(record.Expiry.Seconds()),
-		})
+		records = append(records, pb.NewRecord(record))
 	}
 
 	_, err := s.Client.Write(context.Background(), &pb.WriteRequest{
@@ -93,7 +125,26 @@ func (s *serviceStore) Write(recs ...*store.Record) error {
 	return err
 }
 
-// Delete a record with key
+// WriteWith writes a record with options
+func (s *serviceStore) WriteWith(record *store.Record, opts ...store.WriteOption) error {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	_, err := s.Client.Write(context.Background(), &pb.WriteRequest{
+		Records: []*pb.Record{pb.NewRecord(record)},
+		Options: &pb.WriteOptions{
+			Namespace: options.Namespace,
+			Table:     options.Table,
+			Ttl:       pb.Milliseconds(options.TTL),
+		},
+	}, client.WithAddress(s.Nodes...))
+
+	return err
+}
+
+// Delete records with keys
 func (s *serviceStore) Delete(keys ...string) error {
 	_, err := s.Client.Delete(context.Background(), &pb.DeleteRequest{
 		Keys: keys,
@@ -101,6 +152,69 @@ func (s *serviceStore) Delete(keys ...string) error {
 	return err
 }
 
+// DeleteWith deletes a record with key with options
+func (s *serviceStore) DeleteWith(key string, opts ...store.DeleteOption) error {
+	var options store.DeleteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	_, err := s.Client.Delete(context.Background(), &pb.DeleteRequest{
+		Keys: []string{key},
+		Options: &pb.DeleteOptions{
+			Namespace: options.Namespace,
+			Table:     options.Table,
+		},
+	}, client.WithAddress(s.Nodes...))
+	return err
+}
+
+// CompareAndSwap writes the record if the value of its key is old,
+// the store of the service must implement store.CAS
+func (s *serviceStore) CompareAndSwap(record *store.Record, old []byte, opts ...store.WriteOption) error {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	_, err := s.Client.CompareAndSwap(context.Background(), &pb.CompareAndSwapRequest{
+		Record: pb.NewRecord(record),
+		Old:    old,
+		Absent: old == nil,
+		Options: &pb.WriteOptions{
+			Namespace: options.Namespace,
+			Table:     options.Table,
+			Ttl:       pb.Milliseconds(options.TTL),
+		},
+	}, client.WithAddress(s.Nodes...))
+	if err != nil && errors.Parse(err.Error()).Code == 409 {
+		return store.ErrConflict
+	}
+	return err
+}
+
+// CompareAndDelete deletes the key if its value is old,
+// the store of the service must implement store.CAS
+func (s *serviceStore) CompareAndDelete(key string, old []byte, opts ...store.DeleteOption) error {
+	var options store.DeleteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	_, err := s.Client.CompareAndDelete(context.Background(), &pb.CompareAndDeleteRequest{
+		Key: key,
+		Old: old,
+		Options: &pb.DeleteOptions{
+			Namespace: options.Namespace,
+			Table:     options.Table,
+		},
+	}, client.WithAddress(s.Nodes...))
+	if err != nil && errors.Parse(err.Error()).Code == 409 {
+		return store.ErrConflict
+	}
+	return err
+}
+
 // NewStore returns a new store service implementation
 func NewStore(opts ...options.Option) store.Store {
 	options := options.NewOptions(opts...)
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/store/service/handler"
+	pb "github.com/stack-labs/stack/store/service/proto"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+// testService calls the handler in process
+type testService struct {
+	h *handler.Store
+}
+
+func (t *testService) List(ctx context.Context, in *pb.ListRequest, opts ...client.CallOption) (pb.Store_ListService, error) {
+	return nil, errors.InternalServerError("stack.rpc.store", "not implemented")
+}
+
+func (t *testService) Read(ctx context.Context, in *pb.ReadRequest, opts ...client.CallOption) (*pb.ReadResponse, error) {
+	rsp := new(pb.ReadResponse)
+	return rsp, t.h.Read(ctx, in, rsp)
+}
+
+func (t *testService) Write(ctx context.Context, in *pb.WriteRequest, opts ...client.CallOption) (*pb.WriteResponse, error) {
+	rsp := new(pb.WriteResponse)
+	return rsp, t.h.Write(ctx, in, rsp)
+}
+
+func (t *testService) Delete(ctx context.Context, in *pb.DeleteRequest, opts ...client.CallOption) (*pb.DeleteResponse, error) {
+	rsp := new(pb.DeleteResponse)
+	return rsp, t.h.Delete(ctx, in, rsp)
+}
+
+func (t *testService) CompareAndSwap(ctx context.Context, in *pb.CompareAndSwapRequest, opts ...client.CallOption) (*pb.CompareAndSwapResponse, error) {
+	rsp := new(pb.CompareAndSwapResponse)
+	return rsp, t.h.CompareAndSwap(ctx, in, rsp)
+}
+
+func (t *testService) CompareAndDelete(ctx context.Context, in *pb.CompareAndDeleteRequest, opts ...client.CallOption) (*pb.CompareAndDeleteResponse, error) {
+	rsp := new(pb.CompareAndDeleteResponse)
+	return rsp, t.h.CompareAndDelete(ctx, in, rsp)
+}
+
+// plainStore hides compare and swap
+type plainStore struct {
+	store.Store
+}
+
+func TestServiceStoreExpiry(t *testing.T) {
+	s := &serviceStore{Client: &testService{&handler.Store{Store: memory.NewStore()}}}
+
+	// a ttl under a second still expires
+	if err := s.WriteWith(&store.Record{Key: "foo"}, store.WriteTTL(time.Millisecond*100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(&store.Record{Key: "bar", Expiry: time.Millisecond * 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := s.Read("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range recs {
+		if rec.Expiry <= 0 || rec.Expiry > time.Millisecond*100 {
+			t.Fatalf("expected the expiry of %s under 100ms got %v", rec.Key, rec.Expiry)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 150)
+
+	for _, key := range []string{"foo", "bar"} {
+		if _, err := s.Read(key); err != store.ErrNotFound {
+			t.Fatalf("expected %s expired got %v", key, err)
+		}
+	}
+
+	// services sending the expiry in seconds only
+	rec := (&pb.Record{Key: "foo", Expiry: 2}).StoreRecord()
+	if rec.Expiry != time.Second*2 {
+		t.Fatalf("expected an expiry of 2s got %v", rec.Expiry)
+	}
+	if r := pb.NewRecord(&store.Record{Key: "foo", Expiry: time.Millisecond}); r.Expiry != 1 || r.ExpiryMs != 1 {
+		t.Fatalf("expected the expiry rounded up got %v", r)
+	}
+}
+
+func TestServiceStoreCAS(t *testing.T) {
+	s := &serviceStore{Client: &testService{&handler.Store{Store: memory.NewStore()}}}
+
+	cas, ok := store.Store(s).(store.CAS)
+	if !ok {
+		t.Fatal("expected the service store to implement compare and swap")
+	}
+
+	// a nil old value only writes missing keys
+	if err := cas.CompareAndSwap(&store.Record{Key: "foo"}, nil, store.WriteTo("", "cas")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cas.CompareAndSwap(&store.Record{Key: "foo", Value: []byte("bar")}, nil, store.WriteTo("", "cas")); err != store.ErrConflict {
+		t.Fatalf("expected %v got %v", store.ErrConflict, err)
+	}
+
+	// an empty value exists
+	if err := cas.CompareAndSwap(&store.Record{Key: "foo", Value: []byte("bar")}, []byte{}, store.WriteTo("", "cas")); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := s.ReadWith("foo", store.ReadFrom("", "cas"))
+	if err != nil || string(recs[0].Value) != "bar" {
+		t.Fatalf("unexpected records %v: %v", recs, err)
+	}
+
+	if err := cas.CompareAndDelete("foo", []byte("baz"), store.DeleteFrom("", "cas")); err != store.ErrConflict {
+		t.Fatalf("expected %v got %v", store.ErrConflict, err)
+	}
+	if err := cas.CompareAndDelete("foo", []byte("bar"), store.DeleteFrom("", "cas")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ReadWith("foo", store.ReadFrom("", "cas")); err != store.ErrNotFound {
+		t.Fatalf("expected %v got %v", store.ErrNotFound, err)
+	}
+
+	// a ttl under a second still expires
+	if err := cas.CompareAndSwap(&store.Record{Key: "foo", Expiry: time.Millisecond * 100}, nil, store.WriteTo("", "cas")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cas.CompareAndSwap(&store.Record{Key: "bar"}, nil, store.WriteTo("", "cas"), store.WriteTTL(time.Millisecond*100)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 150)
+	for _, key := range []string{"foo", "bar"} {
+		if _, err := s.ReadWith(key, store.ReadFrom("", "cas")); err != store.ErrNotFound {
+			t.Fatalf("expected %s expired got %v", key, err)
+		}
+	}
+
+	// stores without compare and swap are refused
+	s = &serviceStore{Client: &testService{&handler.Store{Store: &plainStore{memory.NewStore()}}}}
+	if err := s.CompareAndSwap(&store.Record{Key: "foo"}, nil); err == nil || errors.Parse(err.Error()).Code != 501 {
+		t.Fatalf("expected unsupported compare and swap got %v", err)
+	}
+}
@@ -9,23 +9,45 @@ import (
 var (
 	// ErrNotFound is returned when a Read key doesn't exist
 	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a compare and swap finds another value
+	ErrConflict = errors.New("conflict")
 )
 
 // Store is a data storage interface
 type Store interface {
 	// List all the known records
-	List() ([]*Record, error)
+	List(opts ...ListOption) ([]*Record, error)
 	// Read records with keys
 	Read(key ...string) ([]*Record, error)
-	// Write records
+	// Write records, all of them or none if supported by the store
 	Write(rec ...*Record) error
 	// Delete records with keys
 	Delete(key ...string) error
+	// ReadWith reads the record with key, or every record
+	// matching the key when reading with a prefix or suffix
+	ReadWith(key string, opts ...ReadOption) ([]*Record, error)
+	// WriteWith writes a record with the options
+	WriteWith(r *Record, opts ...WriteOption) error
+	// DeleteWith deletes the record with key with the options
+	DeleteWith(key string, opts ...DeleteOption) error
+}
+
+// CAS is implemented by stores supporting atomic compare and swap
+type CAS interface {
+	// CompareAndSwap writes the record if the value of its key is old.
+	// A nil old value writes the record only if the key doesn't exist.
+	// ErrConflict is returned if the value is different.
+	CompareAndSwap(r *Record, old []byte, opts ...WriteOption) error
+	// CompareAndDelete deletes the key if its value is old.
+	// ErrConflict is returned if the value is different.
+	CompareAndDelete(key string, old []byte, opts ...DeleteOption) error
 }
 
 // Record represents a data record
 type Record struct {
-	Key    string
-	Value  []byte
+	Key   string
+	Value []byte
+	// Expiry is the time to live when writing and
+	// the remaining time to live when reading
 	Expiry time.Duration
 }
@@ -1,17 +1,79 @@
 package sync
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"sort"
+	gosync "sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/sync/leader"
+	ll "github.com/stack-labs/stack/sync/leader/lock"
+	lr "github.com/stack-labs/stack/sync/leader/registry"
 	"github.com/stack-labs/stack/sync/task"
 	"github.com/stack-labs/stack/sync/task/local"
 	"github.com/stack-labs/stack/util/log"
 )
 
+var (
+	// DefaultHistory is the number of runs kept per cron job
+	DefaultHistory = 100
+	// CronTable is the store table of the cron runs and paused jobs
+	CronTable = "cron"
+
+	// ErrJobNotFound is returned for a job not scheduled
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// Job is a scheduled command
+type Job struct {
+	Id       string
+	Command  string
+	Schedule task.Schedule
+	Paused   bool
+	Running  bool
+	// Next time the job runs, zero if it doesn't
+	Next time.Time
+}
+
+// Run is a run of a job
+type Run struct {
+	Job  string `json:"job"`
+	Node string `json:"node"`
+	// Scheduled time of the run
+	Scheduled time.Time `json:"scheduled"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Error     string    `json:"error,omitempty"`
+}
+
 type syncCron struct {
 	opts Options
+
+	gosync.RWMutex
+	jobs map[string]*job
+}
+
+type job struct {
+	id       string
+	schedule task.Schedule
+	command  task.Command
+	opts     ScheduleOptions
+	exit     chan bool
+
+	gosync.Mutex
+	paused  bool
+	running int
+	// the start time of a one off schedule has passed
+	done bool
+	next time.Time
+	// scheduled time of the last run
+	last    time.Time
+	history []*Run
 }
 
 func backoff(attempts int) time.Duration {
@@ -21,57 +83,402 @@ func backoff(attempts int) time.Duration {
 	return time.Duration(math.Pow(10, float64(attempts))) * time.Millisecond
 }
 
-func (c *syncCron) Schedule(s task.Schedule, t task.Command) error {
-	id := fmt.Sprintf("%s-%s", s.String(), t.String())
+func historyPrefix(id string) string {
+	return "history/" + id + "/"
+}
+
+func pausedKey(id string) string {
+	return "paused/" + id
+}
+
+// next returns the time the job runs after t
+func (j *job) nextAfter(t time.Time) time.Time {
+	j.Lock()
+	defer j.Unlock()
+
+	// one off schedules run once at the start time
+	if len(j.schedule.Cron) == 0 && j.schedule.Interval == time.Duration(0) {
+		if j.done {
+			return time.Time{}
+		}
+		return j.schedule.Time
+	}
+
+	return j.schedule.Next(t)
+}
+
+func (j *job) exited() bool {
+	select {
+	case <-j.exit:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPaused reads the paused key of the job from the store shared by the
+// nodes, the local flag is only used without a store
+func (c *syncCron) isPaused(j *job) bool {
+	if c.opts.Store == nil {
+		j.Lock()
+		defer j.Unlock()
+		return j.paused
+	}
+
+	_, err := c.opts.Store.ReadWith(pausedKey(j.id), store.ReadFrom("", CronTable))
+	if err != nil && err != store.ErrNotFound {
+		log.Logf("[cron] error reading paused state of %s: %v", j.id, err)
+	}
+	return err == nil
+}
+
+// lastScheduled returns the scheduled time of the last run of the job
+func (c *syncCron) lastScheduled(j *job) time.Time {
+	j.Lock()
+	last := j.last
+	j.Unlock()
+
+	if runs, err := c.History(j.id); err == nil && len(runs) > 0 && runs[0].Scheduled.After(last) {
+		last = runs[0].Scheduled
+	}
+
+	return last
+}
+
+func (c *syncCron) record(j *job, r *Run) {
+	j.Lock()
+	j.history = append([]*Run{r}, j.history...)
+	if len(j.history) > c.opts.History {
+		j.history = j.history[:c.opts.History]
+	}
+	j.Unlock()
+
+	if c.opts.Store == nil {
+		return
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		log.Logf("[cron] error encoding run of %s: %v", j.id, err)
+		return
+	}
+
+	if err := c.opts.Store.WriteWith(&store.Record{
+		Key:   fmt.Sprintf("%s%020d", historyPrefix(j.id), r.Scheduled.UnixNano()),
+		Value: b,
+	}, store.WriteTo("", CronTable)); err != nil {
+		log.Logf("[cron] error recording run of %s: %v", j.id, err)
+		return
+	}
+
+	// remove the oldest runs
+	records, err := c.historyRecords(j.id)
+	if err != nil {
+		return
+	}
+	for i := 0; i < len(records)-c.opts.History; i++ {
+		c.opts.Store.DeleteWith(records[i].Key, store.DeleteFrom("", CronTable))
+	}
+}
+
+// historyRecords returns the run records of the job, oldest first
+func (c *syncCron) historyRecords(id string) ([]*store.Record, error) {
+	prefix := historyPrefix(id)
+
+	records, err := c.opts.Store.ReadWith(prefix, store.ReadFrom("", CronTable), store.ReadPrefix())
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	// drop the runs of jobs whose id starts with this one
+	var runs []*store.Record
+	for _, r := range records {
+		if len(r.Key) == len(prefix)+20 {
+			runs = append(runs, r)
+		}
+	}
+
+	sort.Slice(runs, func(i, k int) bool {
+		return runs[i].Key < runs[k].Key
+	})
+
+	return runs, nil
+}
+
+// execute runs the command of the job in the background
+func (c *syncCron) execute(j *job, scheduled time.Time) {
+	j.Lock()
+	j.last = scheduled
+	if len(j.schedule.Cron) == 0 && j.schedule.Interval == time.Duration(0) {
+		j.done = true
+	}
+	running := j.running
+	j.Unlock()
+
+	if c.isPaused(j) {
+		log.Logf("[cron] skipping paused command %s", j.command.Name)
+		return
+	}
+
+	if j.opts.NoOverlap && running > 0 {
+		log.Logf("[cron] skipping command %s, previous run still running", j.command.Name)
+		return
+	}
+
+	j.Lock()
+	j.running++
+	j.Unlock()
 
 	go func() {
-		// run the scheduler
-		tc := s.Run()
-
-		var i int
-
-		for {
-			// leader election
-			e, err := c.opts.Leader.Elect(id)
-			if err != nil {
-				log.Logf("[cron] leader election error: %v", err)
-				time.Sleep(backoff(i))
-				i++
-				continue
+		r := &Run{
+			Job:       j.id,
+			Node:      c.opts.Node,
+			Scheduled: scheduled,
+			Start:     time.Now(),
+		}
+
+		log.Logf("[cron] executing command %s", j.command.Name)
+		if err := c.opts.Task.Run(j.command); err != nil {
+			log.Logf("[cron] error executing command %s: %v", j.command.Name, err)
+			r.Error = err.Error()
+		}
+		r.End = time.Now()
+
+		j.Lock()
+		j.running--
+		j.Unlock()
+
+		c.record(j, r)
+	}()
+}
+
+// lead runs the job while leader until revoked or unscheduled
+func (c *syncCron) lead(j *job, revoked chan bool) {
+	if j.opts.CatchUp {
+		if last := c.lastScheduled(j); !last.IsZero() {
+			if next := j.nextAfter(last); !next.IsZero() && next.Before(time.Now()) {
+				log.Logf("[cron] catching up command %s missed at %s", j.command.Name, next.Format(time.RFC3339))
+				c.execute(j, next)
 			}
+		}
+	}
+
+	for {
+		next := j.nextAfter(time.Now())
 
-			i = 0
-			r := e.Revoked()
-
-			// execute the task
-		Tick:
-			for {
-				select {
-				// schedule tick
-				case _, ok := <-tc:
-					// ticked once
-					if !ok {
-						break Tick
-					}
-
-					log.Logf("[cron] executing command %s", t.Name)
-					if err := c.opts.Task.Run(t); err != nil {
-						log.Logf("[cron] error executing command %s: %v", t.Name, err)
-					}
-				// leader revoked
-				case <-r:
-					break Tick
-				}
+		j.Lock()
+		j.next = next
+		j.Unlock()
+
+		// the schedule is complete
+		if next.IsZero() {
+			select {
+			case <-revoked:
+			case <-j.exit:
 			}
+			return
+		}
 
-			// resign
-			e.Resign()
+		t := time.NewTimer(time.Until(next))
+
+		select {
+		// schedule tick
+		case <-t.C:
+			c.execute(j, next)
+		// leader revoked
+		case <-revoked:
+			t.Stop()
+			return
+		// unscheduled
+		case <-j.exit:
+			t.Stop()
+			return
 		}
-	}()
+	}
+}
+
+func (c *syncCron) run(j *job) {
+	var i int
+
+	for !j.exited() {
+		// leader election, given up once unscheduled
+		e, err := c.opts.Leader.Elect(j.id, leader.Exit(j.exit))
+		if err == leader.ErrCancelled {
+			return
+		} else if err != nil {
+			log.Logf("[cron] leader election error: %v", err)
+			time.Sleep(backoff(i))
+			i++
+			continue
+		}
+
+		i = 0
+
+		if !j.exited() {
+			c.lead(j, e.Revoked())
+		}
+
+		// resign
+		e.Resign()
+	}
+}
+
+func (c *syncCron) Schedule(s task.Schedule, t task.Command, opts ...ScheduleOption) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	var options ScheduleOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	id := fmt.Sprintf("%s-%s", s.String(), t.String())
+
+	// intervals tick from when they're scheduled
+	if s.Time.IsZero() && len(s.Cron) == 0 {
+		s.Time = time.Now()
+	}
+
+	j := &job{
+		id:       id,
+		schedule: s,
+		command:  t,
+		opts:     options,
+		exit:     make(chan bool),
+	}
+
+	c.Lock()
+	if _, ok := c.jobs[id]; ok {
+		c.Unlock()
+		return fmt.Errorf("job %s already scheduled", id)
+	}
+	c.jobs[id] = j
+	c.Unlock()
+
+	go c.run(j)
 
 	return nil
 }
 
+func (c *syncCron) List() ([]*Job, error) {
+	c.RLock()
+	jobs := make([]*job, 0, len(c.jobs))
+	for _, j := range c.jobs {
+		jobs = append(jobs, j)
+	}
+	c.RUnlock()
+
+	list := make([]*Job, 0, len(jobs))
+	for _, j := range jobs {
+		paused := c.isPaused(j)
+
+		j.Lock()
+		list = append(list, &Job{
+			Id:       j.id,
+			Command:  j.command.Name,
+			Schedule: j.schedule,
+			Paused:   paused,
+			Running:  j.running > 0,
+			Next:     j.next,
+		})
+		j.Unlock()
+	}
+
+	sort.Slice(list, func(i, k int) bool {
+		return list[i].Id < list[k].Id
+	})
+
+	return list, nil
+}
+
+// setPaused pauses the job in the store so every node skips it,
+// or locally without a store
+func (c *syncCron) setPaused(id string, paused bool) error {
+	if c.opts.Store == nil {
+		c.RLock()
+		j, ok := c.jobs[id]
+		c.RUnlock()
+
+		if !ok {
+			return ErrJobNotFound
+		}
+
+		j.Lock()
+		j.paused = paused
+		j.Unlock()
+
+		return nil
+	}
+
+	if !paused {
+		return c.opts.Store.DeleteWith(pausedKey(id), store.DeleteFrom("", CronTable))
+	}
+
+	return c.opts.Store.WriteWith(&store.Record{
+		Key:   pausedKey(id),
+		Value: []byte(time.Now().Format(time.RFC3339)),
+	}, store.WriteTo("", CronTable))
+}
+
+func (c *syncCron) Pause(id string) error {
+	return c.setPaused(id, true)
+}
+
+func (c *syncCron) Resume(id string) error {
+	return c.setPaused(id, false)
+}
+
+func (c *syncCron) Unschedule(id string) error {
+	c.Lock()
+	j, ok := c.jobs[id]
+	if !ok {
+		c.Unlock()
+		return ErrJobNotFound
+	}
+	delete(c.jobs, id)
+	c.Unlock()
+
+	close(j.exit)
+
+	return nil
+}
+
+func (c *syncCron) History(id string) ([]*Run, error) {
+	if c.opts.Store == nil {
+		c.RLock()
+		j, ok := c.jobs[id]
+		c.RUnlock()
+
+		if !ok {
+			return nil, ErrJobNotFound
+		}
+
+		j.Lock()
+		defer j.Unlock()
+
+		runs := make([]*Run, len(j.history))
+		copy(runs, j.history)
+		return runs, nil
+	}
+
+	records, err := c.historyRecords(id)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]*Run, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		r := new(Run)
+		if err := json.Unmarshal(records[i].Value, r); err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+
+	return runs, nil
+}
+
 func NewCron(opts ...Option) Cron {
 	var options Options
 	for _, o := range opts {
@@ -82,7 +489,23 @@ func NewCron(opts ...Option) Cron {
 		options.Task = local.NewTask()
 	}
 
+	// elect through the lock if one is set
+	if options.Leader == nil && options.Lock != nil {
+		options.Leader = ll.NewLeader(ll.Lock(options.Lock))
+	} else if options.Leader == nil {
+		options.Leader = lr.NewLeader()
+	}
+
+	if len(options.Node) == 0 {
+		options.Node = uuid.New().String()
+	}
+
+	if options.History <= 0 {
+		options.History = DefaultHistory
+	}
+
 	return &syncCron{
 		opts: options,
+		jobs: make(map[string]*job),
 	}
 }
@@ -0,0 +1,339 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/leader"
+	ll "github.com/stack-labs/stack/sync/leader/lock"
+	lmemory "github.com/stack-labs/stack/sync/lock/memory"
+	"github.com/stack-labs/stack/sync/task"
+)
+
+type counter struct {
+	gosync.Mutex
+	n int
+}
+
+func (c *counter) inc() int {
+	c.Lock()
+	defer c.Unlock()
+	c.n++
+	return c.n
+}
+
+func (c *counter) get() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.n
+}
+
+func newTestCron(s store.Store) Cron {
+	return NewCron(
+		WithLock(lmemory.NewLock()),
+		WithStore(s),
+		WithNode("node-1"),
+		WithHistory(5),
+	)
+}
+
+func TestCron(t *testing.T) {
+	s := memory.NewStore()
+	c := newTestCron(s)
+
+	runs := new(counter)
+	err := c.Schedule(task.Schedule{Interval: time.Millisecond * 20}, task.Command{
+		Name: "job",
+		Func: func() error {
+			if runs.inc()%2 == 0 {
+				return errors.New("failed")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Command != "job" {
+		t.Fatalf("unexpected jobs %v", jobs)
+	}
+	id := jobs[0].Id
+
+	time.Sleep(time.Millisecond * 200)
+
+	history, err := c.History(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 5 {
+		t.Fatalf("expected 5 runs kept got %d", len(history))
+	}
+
+	var failed int
+	for i, r := range history {
+		if r.Node != "node-1" || r.Job != id {
+			t.Fatalf("unexpected run %+v", r)
+		}
+		if r.End.Before(r.Start) {
+			t.Fatalf("run ended before it started %+v", r)
+		}
+		if i > 0 && !r.Scheduled.Before(history[i-1].Scheduled) {
+			t.Fatal("expected the most recent run first")
+		}
+		if len(r.Error) > 0 {
+			failed++
+		}
+	}
+	if failed == 0 {
+		t.Fatal("expected failed runs recorded")
+	}
+
+	if err := c.Pause(id); err != nil {
+		t.Fatal(err)
+	}
+	jobs, _ = c.List()
+	if !jobs[0].Paused || jobs[0].Next.IsZero() {
+		t.Fatalf("unexpected job %+v", jobs[0])
+	}
+
+	// let a running command finish
+	time.Sleep(time.Millisecond * 30)
+	paused := runs.get()
+	time.Sleep(time.Millisecond * 100)
+	if runs.get() != paused {
+		t.Fatal("expected no runs while paused")
+	}
+
+	if err := c.Resume(id); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 100)
+	if runs.get() == paused {
+		t.Fatal("expected runs once resumed")
+	}
+
+	if err := c.Unschedule(id); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 30)
+	unscheduled := runs.get()
+	time.Sleep(time.Millisecond * 100)
+	if runs.get() != unscheduled {
+		t.Fatal("expected no runs once unscheduled")
+	}
+
+	if err := c.Unschedule(id); err != ErrJobNotFound {
+		t.Fatalf("expected %v got %v", ErrJobNotFound, err)
+	}
+	if jobs, _ := c.List(); len(jobs) != 0 {
+		t.Fatalf("expected no jobs got %d", len(jobs))
+	}
+}
+
+func TestCronNoOverlap(t *testing.T) {
+	c := newTestCron(memory.NewStore())
+
+	var running, max counter
+	err := c.Schedule(task.Schedule{Interval: time.Millisecond * 10}, task.Command{
+		Name: "slow",
+		Func: func() error {
+			n := running.inc()
+			max.Lock()
+			if n > max.n {
+				max.n = n
+			}
+			max.Unlock()
+
+			time.Sleep(time.Millisecond * 50)
+
+			running.Lock()
+			running.n--
+			running.Unlock()
+			return nil
+		},
+	}, NoOverlap())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond * 200)
+
+	if max.get() != 1 {
+		t.Fatalf("expected no overlapping runs got %d at once", max.get())
+	}
+}
+
+func TestCronCatchUp(t *testing.T) {
+	start := time.Now().Add(-time.Hour * 3)
+
+	for _, catchUp := range []bool{true, false} {
+		s := memory.NewStore()
+		c := newTestCron(s)
+
+		sched := task.Schedule{Time: start, Interval: time.Hour}
+		cmd := task.Command{Name: fmt.Sprintf("catchup-%v", catchUp), Func: func() error { return nil }}
+		id := fmt.Sprintf("%s-%s", sched.String(), cmd.String())
+
+		// the last run was two hours ago
+		b, _ := json.Marshal(&Run{Job: id, Scheduled: start.Add(time.Hour)})
+		s.WriteWith(&store.Record{
+			Key:   fmt.Sprintf("%s%020d", historyPrefix(id), start.Add(time.Hour).UnixNano()),
+			Value: b,
+		}, store.WriteTo("", CronTable))
+
+		var opts []ScheduleOption
+		if catchUp {
+			opts = append(opts, CatchUp())
+		}
+		if err := c.Schedule(sched, cmd, opts...); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(time.Millisecond * 100)
+
+		history, err := c.History(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !catchUp {
+			if len(history) != 1 {
+				t.Fatalf("expected no catch up run got %d runs", len(history))
+			}
+			continue
+		}
+
+		if len(history) != 2 {
+			t.Fatalf("expected a catch up run got %d runs", len(history))
+		}
+		if !history[0].Scheduled.Equal(start.Add(time.Hour * 2)) {
+			t.Fatalf("expected the missed run at %s got %s", start.Add(time.Hour*2), history[0].Scheduled)
+		}
+	}
+}
+
+func TestCronCronExpression(t *testing.T) {
+	c := NewCron(WithLock(lmemory.NewLock()))
+
+	if err := c.Schedule(task.Schedule{Cron: "bad"}, task.Command{Name: "bad"}); err == nil {
+		t.Fatal("expected invalid cron expression error")
+	}
+
+	sched := task.Schedule{Cron: "0 0 1 1 *", Location: time.UTC}
+	if err := c.Schedule(sched, task.Command{Name: "yearly"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Schedule(sched, task.Command{Name: "yearly"}); err == nil {
+		t.Fatal("expected error scheduling a job twice")
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	jobs, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	if expected := time.Date(now.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC); !jobs[0].Next.Equal(expected) {
+		t.Fatalf("expected next run at %s got %s", expected, jobs[0].Next)
+	}
+
+	// history is kept in memory without a store
+	if runs, err := c.History(jobs[0].Id); err != nil || len(runs) != 0 {
+		t.Fatalf("unexpected history %v: %v", runs, err)
+	}
+	if err := c.Pause("missing"); err != ErrJobNotFound {
+		t.Fatalf("expected %v got %v", ErrJobNotFound, err)
+	}
+}
+
+func TestCronPauseShared(t *testing.T) {
+	s := memory.NewStore()
+	sched := task.Schedule{Interval: time.Hour}
+	cmd := task.Command{Name: "shared"}
+
+	// nodes sharing the store
+	c1, c2 := newTestCron(s), newTestCron(s)
+	for _, c := range []Cron{c1, c2} {
+		if err := c.Schedule(sched, cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs, _ := c1.List()
+	id := jobs[0].Id
+
+	if err := c1.Pause(id); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, _ := c2.List(); !jobs[0].Paused {
+		t.Fatal("expected the job paused on every node")
+	}
+
+	// resuming on another node resumes the node that paused it
+	if err := c2.Resume(id); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, _ := c1.List(); jobs[0].Paused {
+		t.Fatal("expected the job resumed on every node")
+	}
+}
+
+// testLeader reports the elections ended
+type testLeader struct {
+	leader.Leader
+	done chan error
+}
+
+func (l *testLeader) Elect(id string, opts ...leader.ElectOption) (leader.Elected, error) {
+	e, err := l.Leader.Elect(id, opts...)
+	l.done <- err
+	return e, err
+}
+
+func TestCronUnscheduleCandidate(t *testing.T) {
+	lk := lmemory.NewLock()
+	sched := task.Schedule{Interval: time.Hour}
+	cmd := task.Command{Name: "candidate"}
+
+	c1 := NewCron(WithLock(lk), WithStore(memory.NewStore()), WithNode("node-1"))
+	if err := c1.Schedule(sched, cmd); err != nil {
+		t.Fatal(err)
+	}
+	jobs, _ := c1.List()
+	defer c1.Unschedule(jobs[0].Id)
+	time.Sleep(time.Millisecond * 100)
+
+	// the second node waits to be elected
+	l := &testLeader{Leader: ll.NewLeader(ll.Lock(lk), leader.TTL(time.Second)), done: make(chan error, 1)}
+	c2 := NewCron(WithLeader(l), WithStore(memory.NewStore()), WithNode("node-2"))
+	if err := c2.Schedule(sched, cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	jobs, _ = c2.List()
+	if err := c2.Unschedule(jobs[0].Id); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-l.done:
+		if err != leader.ErrCancelled {
+			t.Fatalf("expected %v got %v", leader.ErrCancelled, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the election of the unscheduled job to end")
+	}
+}
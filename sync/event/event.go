@@ -1,6 +1,18 @@
 // Package event provides a distributed log interface
 package event
 
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrClosed is returned using a closed log
+	ErrClosed = errors.New("log closed")
+	// ErrRemoved is returned reading an offset no longer kept in the log
+	ErrRemoved = errors.New("log offset removed")
+)
+
 // Event provides a distributed log interface
 type Event interface {
 	// Log retrieves the log with an id/name
@@ -13,15 +25,29 @@ type Log interface {
 	Close() error
 	// Log ID
 	Id() string
-	// Read will read the next record
+	// Read will read the next record, io.EOF is
+	// returned once there are no more records
 	Read() (*Record, error)
-	// Go to an offset
-	Seek(offset int64) error
+	// SeekTo goes to an offset, reading an offset
+	// no longer kept returns ErrRemoved
+	SeekTo(offset int64) error
 	// Write an event to the log
 	Write(*Record) error
 }
 
 type Record struct {
+	// Offset of the record in the log, set on read and write
+	Offset   int64
 	Metadata map[string]interface{}
 	Data     []byte
 }
+
+type Options struct {
+	Nodes  []string
+	Prefix string
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
+}
+
+type Option func(o *Options)
@@ -0,0 +1,222 @@
+// Package file provides a file backed implementation of the event log for local use
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/stack-labs/stack/sync/event"
+	"github.com/stack-labs/stack/util/segment"
+)
+
+var (
+	// DefaultDir is the directory the logs are kept in
+	DefaultDir = filepath.Join(os.TempDir(), "stack", "event")
+)
+
+type fileEvent struct {
+	opts    event.Options
+	dir     string
+	segOpts []segment.Option
+
+	sync.Mutex
+	// open logs shared by the handles
+	logs map[string]*segment.Log
+}
+
+type fileLog struct {
+	id  string
+	log *segment.Log
+
+	sync.Mutex
+	offset int64
+	closed bool
+}
+
+// entry is a record as written to the log
+type entry struct {
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Data     []byte                 `json:"data,omitempty"`
+}
+
+func (f *fileEvent) open(id string) (*segment.Log, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if l, ok := f.logs[id]; ok {
+		return l, nil
+	}
+
+	// escape the id so it's a single directory
+	name := url.PathEscape(id)
+	if len(id) == 0 || name == "." || name == ".." {
+		return nil, errors.New("invalid log id " + id)
+	}
+
+	l, err := segment.Open(filepath.Join(f.dir, name), f.segOpts...)
+	if err != nil {
+		return nil, err
+	}
+	f.logs[id] = l
+
+	return l, nil
+}
+
+// Log returns a handle to the log starting from its first record
+func (f *fileEvent) Log(id string) (event.Log, error) {
+	l, err := f.open(f.opts.Prefix + id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileLog{
+		id:     id,
+		log:    l,
+		offset: l.First(),
+	}, nil
+}
+
+// Close the open logs, handles to them return event.ErrClosed
+func (f *fileEvent) Close() error {
+	f.Lock()
+	defer f.Unlock()
+
+	var err error
+	for id, l := range f.logs {
+		if cerr := l.Close(); cerr != nil {
+			err = cerr
+		}
+		delete(f.logs, id)
+	}
+	return err
+}
+
+func (l *fileLog) Close() error {
+	l.Lock()
+	l.closed = true
+	l.Unlock()
+	return nil
+}
+
+// First returns the offset of the first record kept in the log
+func (l *fileLog) First() int64 {
+	return l.log.First()
+}
+
+func (l *fileLog) Id() string {
+	return l.id
+}
+
+func (l *fileLog) Read() (*event.Record, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return nil, event.ErrClosed
+	}
+
+	b, err := l.log.Read(l.offset)
+	if err == segment.ErrNotFound {
+		return nil, io.EOF
+	} else if err == segment.ErrRemoved {
+		return nil, event.ErrRemoved
+	} else if err == segment.ErrClosed {
+		return nil, event.ErrClosed
+	} else if err != nil {
+		return nil, err
+	}
+
+	var e entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+
+	r := &event.Record{
+		Offset:   l.offset,
+		Metadata: e.Metadata,
+		Data:     e.Data,
+	}
+	l.offset++
+
+	return r, nil
+}
+
+func (l *fileLog) SeekTo(offset int64) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return event.ErrClosed
+	}
+	if offset < 0 {
+		return errors.New("negative offset")
+	}
+
+	l.offset = offset
+	return nil
+}
+
+func (l *fileLog) Write(r *event.Record) error {
+	l.Lock()
+	closed := l.closed
+	l.Unlock()
+
+	if closed {
+		return event.ErrClosed
+	}
+
+	b, err := json.Marshal(&entry{
+		Metadata: r.Metadata,
+		Data:     r.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	offset, err := l.log.Append(b)
+	if err == segment.ErrClosed {
+		return event.ErrClosed
+	} else if err != nil {
+		return err
+	}
+	r.Offset = offset
+
+	return nil
+}
+
+// NewEvent returns an event log kept in files. Writers of a log are
+// ordered within the process, the directory must not be shared by processes.
+func NewEvent(opts ...event.Option) event.Event {
+	var options event.Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	f := &fileEvent{
+		opts: options,
+		dir:  DefaultDir,
+		logs: make(map[string]*segment.Log),
+	}
+
+	if options.Context != nil {
+		if v, ok := options.Context.Value(dirKey{}).(string); ok && len(v) > 0 {
+			f.dir = v
+		}
+		if v, ok := options.Context.Value(segmentSizeKey{}).(int64); ok && v > 0 {
+			f.segOpts = append(f.segOpts, segment.SegmentSize(v))
+		}
+		if v, ok := options.Context.Value(maxSegmentsKey{}).(int); ok {
+			f.segOpts = append(f.segOpts, segment.MaxSegments(v))
+		}
+		if v, ok := options.Context.Value(syncKey{}).(bool); ok {
+			f.segOpts = append(f.segOpts, segment.Sync(v))
+		}
+	}
+
+	return f
+}
@@ -0,0 +1,248 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stack-labs/stack/sync/event"
+)
+
+func newTestEvent(t *testing.T, dir string) event.Event {
+	return NewEvent(Dir(dir), SegmentSize(256))
+}
+
+func readAll(t *testing.T, l event.Log) []*event.Record {
+	var records []*event.Record
+	for {
+		r, err := l.Read()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+}
+
+func TestConcurrentWriters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	e := newTestEvent(t, dir)
+
+	writers, count := 8, 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			l, err := e.Log("orders")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer l.Close()
+
+			for j := 0; j < count; j++ {
+				if err := l.Write(&event.Record{
+					Metadata: map[string]interface{}{"writer": fmt.Sprintf("%d", w)},
+					Data:     []byte(fmt.Sprintf("%d", j)),
+				}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	l, err := e.Log("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := readAll(t, l)
+	if len(records) != writers*count {
+		t.Fatalf("expected %d records got %d", writers*count, len(records))
+	}
+
+	// offsets are sequential and each writer's records are in order
+	next := make(map[string]int)
+	for i, r := range records {
+		if r.Offset != int64(i) {
+			t.Fatalf("expected offset %d got %d", i, r.Offset)
+		}
+		w := r.Metadata["writer"].(string)
+		if string(r.Data) != fmt.Sprintf("%d", next[w]) {
+			t.Fatalf("writer %s out of order: expected %d got %s", w, next[w], r.Data)
+		}
+		next[w]++
+	}
+}
+
+func TestSeek(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	e := newTestEvent(t, dir)
+
+	l, err := e.Log("audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		r := &event.Record{Data: []byte(fmt.Sprintf("%d", i))}
+		if err := l.Write(r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Offset != int64(i) {
+			t.Fatalf("expected offset %d got %d", i, r.Offset)
+		}
+	}
+
+	if err := l.SeekTo(15); err != nil {
+		t.Fatal(err)
+	}
+	records := readAll(t, l)
+	if len(records) != 5 || string(records[0].Data) != "15" {
+		t.Fatalf("unexpected records after seek %v", records)
+	}
+
+	// reading past the end waits on new writes
+	if err := l.SeekTo(25); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Read(); err != io.EOF {
+		t.Fatalf("expected EOF got %v", err)
+	}
+
+	if err := l.SeekTo(-1); err == nil {
+		t.Fatal("expected error seeking a negative offset")
+	}
+
+	// logs are separate
+	other, err := e.Log("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Read(); err != io.EOF {
+		t.Fatalf("expected EOF got %v", err)
+	}
+
+	l.Close()
+	if _, err := l.Read(); err != event.ErrClosed {
+		t.Fatalf("expected %v got %v", event.ErrClosed, err)
+	}
+
+	if _, err := e.Log(".."); err == nil {
+		t.Fatal("expected invalid log id error")
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	e := newTestEvent(t, dir)
+	l, err := e.Log("accounts/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 30; i++ {
+		if err := l.Write(&event.Record{
+			Metadata: map[string]interface{}{"type": "deposit", "amount": i},
+			Data:     []byte(fmt.Sprintf("%d", i)),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.(*fileEvent).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Write(&event.Record{}); err != event.ErrClosed {
+		t.Fatalf("expected %v got %v", event.ErrClosed, err)
+	}
+
+	// restart
+	e = newTestEvent(t, dir)
+	l, err = e.Log("accounts/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &event.Record{Data: []byte("30")}
+	if err := l.Write(r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Offset != 30 {
+		t.Fatalf("expected offset 30 got %d", r.Offset)
+	}
+
+	records := readAll(t, l)
+	if len(records) != 31 {
+		t.Fatalf("expected 31 records got %d", len(records))
+	}
+	for i, r := range records {
+		if string(r.Data) != fmt.Sprintf("%d", i) {
+			t.Fatalf("expected %d got %s", i, r.Data)
+		}
+	}
+	if records[3].Metadata["type"] != "deposit" || records[3].Metadata["amount"] != float64(3) {
+		t.Fatalf("unexpected metadata %v", records[3].Metadata)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	e := NewEvent(Dir(dir), SegmentSize(64), MaxSegments(2))
+
+	l, err := e.Log("metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		if err := l.Write(&event.Record{Data: []byte(fmt.Sprintf("%d", i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := l.Read(); err != event.ErrRemoved {
+		t.Fatalf("expected %v got %v", event.ErrRemoved, err)
+	}
+
+	// a new handle starts at the first record kept
+	l, err = e.Log("metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := l.(*fileLog).First()
+	if first == 0 {
+		t.Fatal("expected the first segments to be removed")
+	}
+	records := readAll(t, l)
+	if len(records) != int(50-first) || records[0].Offset != first {
+		t.Fatalf("expected records from %d got %d records", first, len(records))
+	}
+}
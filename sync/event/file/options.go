@@ -0,0 +1,42 @@
+package file
+
+import (
+	"context"
+
+	"github.com/stack-labs/stack/sync/event"
+)
+
+type dirKey struct{}
+type segmentSizeKey struct{}
+type maxSegmentsKey struct{}
+type syncKey struct{}
+
+func setOption(k, v interface{}) event.Option {
+	return func(o *event.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// Dir sets the directory the logs are kept in
+func Dir(d string) event.Option {
+	return setOption(dirKey{}, d)
+}
+
+// SegmentSize sets the size in bytes a log segment grows to
+func SegmentSize(n int64) event.Option {
+	return setOption(segmentSizeKey{}, n)
+}
+
+// MaxSegments sets the number of segments kept per log,
+// the oldest records are removed first. Zero keeps all.
+func MaxSegments(n int) event.Option {
+	return setOption(maxSegmentsKey{}, n)
+}
+
+// Sync sets whether every write is synced to disk before returning
+func Sync(b bool) event.Option {
+	return setOption(syncKey{}, b)
+}
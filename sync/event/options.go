@@ -0,0 +1,15 @@
+package event
+
+// Nodes sets the addresses of the underlying event implementation
+func Nodes(a ...string) Option {
+	return func(o *Options) {
+		o.Nodes = a
+	}
+}
+
+// Prefix sets a prefix to any log ids used
+func Prefix(p string) Option {
+	return func(o *Options) {
+		o.Prefix = p
+	}
+}
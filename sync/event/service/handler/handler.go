@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/stack-labs/stack/sync/event"
+	pb "github.com/stack-labs/stack/sync/event/service/proto"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+var (
+	// DefaultLimit of records read when the request has none
+	DefaultLimit int64 = 100
+	// MaxLimit of records read per request
+	MaxLimit int64 = 1000
+)
+
+type Event struct {
+	Event event.Event
+}
+
+func (e *Event) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadResponse) error {
+	if len(req.Id) == 0 {
+		return errors.BadRequest("stack.rpc.event", "log id is blank")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	l, err := e.Event.Log(req.Id)
+	if err != nil {
+		return errors.InternalServerError("stack.rpc.event", err.Error())
+	}
+	defer l.Close()
+
+	// a new handle starts at the first record kept in the log
+	if f, ok := l.(interface{ First() int64 }); ok {
+		rsp.First = f.First()
+	}
+
+	if req.Offset >= 0 {
+		if err := l.SeekTo(req.Offset); err != nil {
+			return errors.BadRequest("stack.rpc.event", err.Error())
+		}
+	}
+
+	for i := int64(0); i < limit; i++ {
+		r, err := l.Read()
+		if err == io.EOF {
+			break
+		} else if err == event.ErrRemoved {
+			return errors.New("stack.rpc.event", err.Error(), 410)
+		} else if err != nil {
+			return errors.InternalServerError("stack.rpc.event", err.Error())
+		}
+
+		var md []byte
+		if len(r.Metadata) > 0 {
+			if md, err = json.Marshal(r.Metadata); err != nil {
+				return errors.InternalServerError("stack.rpc.event", err.Error())
+			}
+		}
+
+		rsp.Records = append(rsp.Records, &pb.Record{
+			Offset:   r.Offset,
+			Metadata: md,
+			Data:     r.Data,
+		})
+	}
+
+	return nil
+}
+
+func (e *Event) Write(ctx context.Context, req *pb.WriteRequest, rsp *pb.WriteResponse) error {
+	if len(req.Id) == 0 {
+		return errors.BadRequest("stack.rpc.event", "log id is blank")
+	}
+	if req.Record == nil {
+		return errors.BadRequest("stack.rpc.event", "record is blank")
+	}
+
+	r := &event.Record{
+		Data: req.Record.Data,
+	}
+	if len(req.Record.Metadata) > 0 {
+		if err := json.Unmarshal(req.Record.Metadata, &r.Metadata); err != nil {
+			return errors.BadRequest("stack.rpc.event", "invalid metadata: "+err.Error())
+		}
+	}
+
+	l, err := e.Event.Log(req.Id)
+	if err != nil {
+		return errors.InternalServerError("stack.rpc.event", err.Error())
+	}
+	defer l.Close()
+
+	if err := l.Write(r); err != nil {
+		return errors.InternalServerError("stack.rpc.event", err.Error())
+	}
+	rsp.Offset = r.Offset
+
+	return nil
+}
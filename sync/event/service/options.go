@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/sync/event"
+)
+
+type clientKey struct{}
+
+// WithClient sets the client used to call the event service
+func WithClient(c client.Client) event.Option {
+	return func(o *event.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, clientKey{}, c)
+	}
+}
@@ -0,0 +1,305 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: event.proto
+
+package stack_rpc_event
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Record struct {
+	// offset of the record in the log
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// json encoded metadata
+	Metadata             []byte   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Record) Reset()         { *m = Record{} }
+func (m *Record) String() string { return proto.CompactTextString(m) }
+func (*Record) ProtoMessage()    {}
+func (*Record) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{0}
+}
+
+func (m *Record) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Record.Unmarshal(m, b)
+}
+func (m *Record) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Record.Marshal(b, m, deterministic)
+}
+func (m *Record) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Record.Merge(m, src)
+}
+func (m *Record) XXX_Size() int {
+	return xxx_messageInfo_Record.Size(m)
+}
+func (m *Record) XXX_DiscardUnknown() {
+	xxx_messageInfo_Record.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Record proto.InternalMessageInfo
+
+func (m *Record) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *Record) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *Record) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type ReadRequest struct {
+	// id of the log
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// offset to read from, the first record kept in the log if negative
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// max number of records to read
+	Limit                int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
+func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadRequest) ProtoMessage()    {}
+func (*ReadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{1}
+}
+
+func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadRequest.Unmarshal(m, b)
+}
+func (m *ReadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadRequest.Merge(m, src)
+}
+func (m *ReadRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadRequest.Size(m)
+}
+func (m *ReadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadRequest proto.InternalMessageInfo
+
+func (m *ReadRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReadRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ReadRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ReadResponse struct {
+	// records from the offset, none at the end of the log
+	Records []*Record `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	// offset of the first record kept in the log
+	First                int64    `protobuf:"varint,2,opt,name=first,proto3" json:"first,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
+func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadResponse) ProtoMessage()    {}
+func (*ReadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{2}
+}
+
+func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadResponse.Unmarshal(m, b)
+}
+func (m *ReadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadResponse.Merge(m, src)
+}
+func (m *ReadResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadResponse.Size(m)
+}
+func (m *ReadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadResponse proto.InternalMessageInfo
+
+func (m *ReadResponse) GetRecords() []*Record {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+func (m *ReadResponse) GetFirst() int64 {
+	if m != nil {
+		return m.First
+	}
+	return 0
+}
+
+type WriteRequest struct {
+	// id of the log
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Record               *Record  `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
+func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
+func (*WriteRequest) ProtoMessage()    {}
+func (*WriteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{3}
+}
+
+func (m *WriteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteRequest.Unmarshal(m, b)
+}
+func (m *WriteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteRequest.Marshal(b, m, deterministic)
+}
+func (m *WriteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteRequest.Merge(m, src)
+}
+func (m *WriteRequest) XXX_Size() int {
+	return xxx_messageInfo_WriteRequest.Size(m)
+}
+func (m *WriteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteRequest proto.InternalMessageInfo
+
+func (m *WriteRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WriteRequest) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+type WriteResponse struct {
+	// offset the record was written at
+	Offset               int64    `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteResponse) Reset()         { *m = WriteResponse{} }
+func (m *WriteResponse) String() string { return proto.CompactTextString(m) }
+func (*WriteResponse) ProtoMessage()    {}
+func (*WriteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{4}
+}
+
+func (m *WriteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteResponse.Unmarshal(m, b)
+}
+func (m *WriteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteResponse.Marshal(b, m, deterministic)
+}
+func (m *WriteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteResponse.Merge(m, src)
+}
+func (m *WriteResponse) XXX_Size() int {
+	return xxx_messageInfo_WriteResponse.Size(m)
+}
+func (m *WriteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteResponse proto.InternalMessageInfo
+
+func (m *WriteResponse) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Record)(nil), "stack.rpc.event.Record")
+	proto.RegisterType((*ReadRequest)(nil), "stack.rpc.event.ReadRequest")
+	proto.RegisterType((*ReadResponse)(nil), "stack.rpc.event.ReadResponse")
+	proto.RegisterType((*WriteRequest)(nil), "stack.rpc.event.WriteRequest")
+	proto.RegisterType((*WriteResponse)(nil), "stack.rpc.event.WriteResponse")
+}
+
+func init() { proto.RegisterFile("event.proto", fileDescriptor_2d17a9d3f0ddf27e) }
+
+var fileDescriptor_2d17a9d3f0ddf27e = []byte{
+	// 277 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xcf, 0x4e, 0x83, 0x40,
+	0x10, 0xc6, 0x05, 0x5a, 0xd4, 0x01, 0x35, 0x99, 0x18, 0x25, 0x44, 0x0d, 0xd9, 0x8b, 0x9c, 0x30,
+	0xd6, 0x67, 0x68, 0x62, 0xe2, 0x41, 0xb3, 0x97, 0x9e, 0x11, 0x86, 0x64, 0xa3, 0x2d, 0xb8, 0xbb,
+	0xfa, 0x2c, 0x3e, 0xae, 0x61, 0x16, 0x9a, 0xfa, 0x07, 0x6f, 0x7c, 0x33, 0xc3, 0xef, 0x9b, 0x6f,
+	0xb2, 0x10, 0xd1, 0x07, 0x6d, 0x6c, 0xd1, 0xe9, 0xd6, 0xb6, 0x78, 0x62, 0x6c, 0x59, 0xbd, 0x14,
+	0xba, 0xab, 0x0a, 0x2e, 0x8b, 0x27, 0x08, 0x25, 0x55, 0xad, 0xae, 0xf1, 0x0c, 0xc2, 0xb6, 0x69,
+	0x0c, 0xd9, 0xc4, 0xcb, 0xbc, 0x3c, 0x90, 0x83, 0xc2, 0x14, 0x0e, 0xd6, 0x64, 0xcb, 0xba, 0xb4,
+	0x65, 0xe2, 0x67, 0x5e, 0x1e, 0xcb, 0xad, 0x46, 0x84, 0x19, 0xd7, 0x03, 0xae, 0xf3, 0xb7, 0x78,
+	0x80, 0x48, 0x52, 0x59, 0x4b, 0x7a, 0x7b, 0x27, 0x63, 0xf1, 0x18, 0x7c, 0x55, 0x33, 0xf2, 0x50,
+	0xfa, 0x6a, 0xd7, 0xc6, 0xff, 0x66, 0x73, 0x0a, 0xf3, 0x57, 0xb5, 0x56, 0x96, 0x59, 0x81, 0x74,
+	0x42, 0xac, 0x20, 0x76, 0x30, 0xd3, 0xb5, 0x1b, 0x43, 0x78, 0x0b, 0xfb, 0x9a, 0xd7, 0x35, 0x89,
+	0x97, 0x05, 0x79, 0xb4, 0x38, 0x2f, 0x7e, 0x24, 0x2a, 0x5c, 0x1c, 0x39, 0xce, 0xf5, 0xe0, 0x46,
+	0x69, 0x33, 0xfa, 0x39, 0x21, 0x1e, 0x21, 0x5e, 0x69, 0x65, 0x69, 0x6a, 0xcd, 0x1b, 0x08, 0x1d,
+	0x80, 0x7f, 0xfb, 0xc7, 0x67, 0x18, 0x13, 0xd7, 0x70, 0x34, 0x00, 0x87, 0x55, 0x27, 0xee, 0xb9,
+	0xf8, 0xf4, 0x60, 0xbe, 0xec, 0x09, 0xb8, 0x84, 0x59, 0x1f, 0x0e, 0x2f, 0xfe, 0x60, 0x6f, 0x0f,
+	0x98, 0x5e, 0x4e, 0x74, 0x9d, 0x8d, 0xd8, 0xc3, 0x7b, 0x98, 0xb3, 0x33, 0xfe, 0x9e, 0xdc, 0x8d,
+	0x98, 0x5e, 0x4d, 0xb5, 0x47, 0xd2, 0x73, 0xc8, 0x8f, 0xe4, 0xee, 0x6b, 0x00, 0xaa, 0x9f, 0x08,
+	0x5c, 0x33, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,110 @@
+// Code generated by protoc-gen-stack. DO NOT EDIT.
+// source: event.proto
+
+package stack_rpc_event
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+import (
+	context "context"
+	api "github.com/stack-labs/stack/api"
+	client "github.com/stack-labs/stack/client"
+	server "github.com/stack-labs/stack/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Event service
+
+func NewEventEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Event service
+
+type EventService interface {
+	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
+	Write(ctx context.Context, in *WriteRequest, opts ...client.CallOption) (*WriteResponse, error)
+}
+
+type eventService struct {
+	c    client.Client
+	name string
+}
+
+func NewEventService(name string, c client.Client) EventService {
+	return &eventService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *eventService) Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error) {
+	req := c.c.NewRequest(c.name, "Event.Read", in)
+	out := new(ReadResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventService) Write(ctx context.Context, in *WriteRequest, opts ...client.CallOption) (*WriteResponse, error) {
+	req := c.c.NewRequest(c.name, "Event.Write", in)
+	out := new(WriteResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Event service
+
+type EventHandler interface {
+	Read(context.Context, *ReadRequest, *ReadResponse) error
+	Write(context.Context, *WriteRequest, *WriteResponse) error
+}
+
+func RegisterEventHandler(s server.Server, hdlr EventHandler, opts ...server.HandlerOption) error {
+	type event interface {
+		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
+		Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error
+	}
+	type Event struct {
+		event
+	}
+	h := &eventHandler{hdlr}
+	return s.Handle(s.NewHandler(&Event{h}, opts...))
+}
+
+type eventHandler struct {
+	EventHandler
+}
+
+func (h *eventHandler) Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error {
+	return h.EventHandler.Read(ctx, in, out)
+}
+
+func (h *eventHandler) Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error {
+	return h.EventHandler.Write(ctx, in, out)
+}
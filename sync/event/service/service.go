@@ -0,0 +1,175 @@
+// Package service implements the event log interface over the event service
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/mucp"
+	"github.com/stack-labs/stack/sync/event"
+	pb "github.com/stack-labs/stack/sync/event/service/proto"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+var (
+	// DefaultBatch is the number of records read from the service at once
+	DefaultBatch int64 = 100
+)
+
+type serviceEvent struct {
+	opts event.Options
+
+	// event service client
+	client pb.EventService
+}
+
+type serviceLog struct {
+	id    string
+	event *serviceEvent
+
+	sync.Mutex
+	// negative until read or seeked, the service
+	// then reads from the first record kept in the log
+	offset int64
+	// records read ahead of the offset
+	buffer []*pb.Record
+	closed bool
+}
+
+func (s *serviceEvent) Log(id string) (event.Log, error) {
+	return &serviceLog{
+		id:     id,
+		event:  s,
+		offset: -1,
+	}, nil
+}
+
+func (l *serviceLog) callOpts() []client.CallOption {
+	return []client.CallOption{client.WithAddress(l.event.opts.Nodes...)}
+}
+
+func (l *serviceLog) Close() error {
+	l.Lock()
+	l.closed = true
+	l.buffer = nil
+	l.Unlock()
+	return nil
+}
+
+func (l *serviceLog) Id() string {
+	return l.id
+}
+
+func (l *serviceLog) Read() (*event.Record, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return nil, event.ErrClosed
+	}
+
+	if len(l.buffer) == 0 {
+		rsp, err := l.event.client.Read(context.Background(), &pb.ReadRequest{
+			Id:     l.event.opts.Prefix + l.id,
+			Offset: l.offset,
+			Limit:  DefaultBatch,
+		}, l.callOpts()...)
+		if err != nil && errors.Parse(err.Error()).Code == 410 {
+			return nil, event.ErrRemoved
+		} else if err != nil {
+			return nil, err
+		}
+		if len(rsp.Records) == 0 {
+			return nil, io.EOF
+		}
+		l.buffer = rsp.Records
+	}
+
+	r := l.buffer[0]
+	l.buffer = l.buffer[1:]
+
+	record := &event.Record{
+		Offset: r.Offset,
+		Data:   r.Data,
+	}
+	if len(r.Metadata) > 0 {
+		if err := json.Unmarshal(r.Metadata, &record.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	l.offset = r.Offset + 1
+
+	return record, nil
+}
+
+func (l *serviceLog) SeekTo(offset int64) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return event.ErrClosed
+	}
+	if offset < 0 {
+		return fmt.Errorf("negative offset %d", offset)
+	}
+
+	l.offset = offset
+	l.buffer = nil
+	return nil
+}
+
+func (l *serviceLog) Write(r *event.Record) error {
+	l.Lock()
+	closed := l.closed
+	l.Unlock()
+
+	if closed {
+		return event.ErrClosed
+	}
+
+	record := &pb.Record{
+		Data: r.Data,
+	}
+	if len(r.Metadata) > 0 {
+		md, err := json.Marshal(r.Metadata)
+		if err != nil {
+			return err
+		}
+		record.Metadata = md
+	}
+
+	rsp, err := l.event.client.Write(context.Background(), &pb.WriteRequest{
+		Id:     l.event.opts.Prefix + l.id,
+		Record: record,
+	}, l.callOpts()...)
+	if err != nil {
+		return err
+	}
+	r.Offset = rsp.Offset
+
+	return nil
+}
+
+// NewEvent returns an event log shared through the event service
+func NewEvent(opts ...event.Option) event.Event {
+	var options event.Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	c := mucp.NewClient()
+	if options.Context != nil {
+		if v, ok := options.Context.Value(clientKey{}).(client.Client); ok {
+			c = v
+		}
+	}
+
+	return &serviceEvent{
+		opts:   options,
+		client: pb.NewEventService("stack.rpc.event", c),
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/sync/event"
+	"github.com/stack-labs/stack/sync/event/file"
+	"github.com/stack-labs/stack/sync/event/service/handler"
+	pb "github.com/stack-labs/stack/sync/event/service/proto"
+)
+
+// testService calls the handler in process
+type testService struct {
+	h *handler.Event
+}
+
+func (t *testService) Read(ctx context.Context, in *pb.ReadRequest, opts ...client.CallOption) (*pb.ReadResponse, error) {
+	rsp := new(pb.ReadResponse)
+	return rsp, t.h.Read(ctx, in, rsp)
+}
+
+func (t *testService) Write(ctx context.Context, in *pb.WriteRequest, opts ...client.CallOption) (*pb.WriteResponse, error) {
+	rsp := new(pb.WriteResponse)
+	return rsp, t.h.Write(ctx, in, rsp)
+}
+
+func TestServiceEvent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := &handler.Event{Event: file.NewEvent(file.Dir(dir))}
+
+	newEvent := func() event.Event {
+		return &serviceEvent{
+			opts:   event.Options{Prefix: "test/"},
+			client: &testService{h},
+		}
+	}
+
+	// services sharing the log
+	writers, count := 4, 60
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			l, _ := newEvent().Log("orders")
+			defer l.Close()
+
+			for j := 0; j < count; j++ {
+				if err := l.Write(&event.Record{
+					Metadata: map[string]interface{}{"writer": w},
+					Data:     []byte(fmt.Sprintf("%d", j)),
+				}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// batch size smaller than the log
+	DefaultBatch = 7
+
+	l, _ := newEvent().Log("orders")
+	next := make(map[float64]int)
+
+	for i := 0; ; i++ {
+		r, err := l.Read()
+		if err == io.EOF {
+			if i != writers*count {
+				t.Fatalf("expected %d records got %d", writers*count, i)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Offset != int64(i) {
+			t.Fatalf("expected offset %d got %d", i, r.Offset)
+		}
+		w := r.Metadata["writer"].(float64)
+		if string(r.Data) != fmt.Sprintf("%d", next[w]) {
+			t.Fatalf("writer %v out of order: expected %d got %s", w, next[w], r.Data)
+		}
+		next[w]++
+	}
+
+	if err := l.SeekTo(100); err != nil {
+		t.Fatal(err)
+	}
+	r, err := l.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Offset != 100 {
+		t.Fatalf("expected offset 100 got %d", r.Offset)
+	}
+
+	// the prefix is applied on the service
+	other, err := h.Event.Log("test/orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, err := other.Read(); err != nil || r.Offset != 0 {
+		t.Fatalf("unexpected record %v: %v", r, err)
+	}
+
+	if err := h.Write(context.TODO(), &pb.WriteRequest{Id: "orders"}, &pb.WriteResponse{}); err == nil {
+		t.Fatal("expected error writing a blank record")
+	}
+}
+
+func TestServiceEventRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := &handler.Event{Event: file.NewEvent(file.Dir(dir), file.SegmentSize(64), file.MaxSegments(2))}
+	e := &serviceEvent{client: &testService{h}}
+
+	l, _ := e.Log("metrics")
+	for i := 0; i < 50; i++ {
+		if err := l.Write(&event.Record{Data: []byte(fmt.Sprintf("%d", i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rsp := new(pb.ReadResponse)
+	if err := h.Read(context.TODO(), &pb.ReadRequest{Id: "metrics", Offset: -1, Limit: 1}, rsp); err != nil {
+		t.Fatal(err)
+	}
+	if rsp.First == 0 || len(rsp.Records) != 1 || rsp.Records[0].Offset != rsp.First {
+		t.Fatalf("expected to read from the first record kept got %v", rsp)
+	}
+
+	// a new handle starts at the first record kept
+	r, err := l.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Offset != rsp.First {
+		t.Fatalf("expected offset %d got %d", rsp.First, r.Offset)
+	}
+
+	if err := l.SeekTo(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Read(); err != event.ErrRemoved {
+		t.Fatalf("expected %v got %v", event.ErrRemoved, err)
+	}
+}
@@ -1,11 +1,22 @@
 // Package leader provides leader election
 package leader
 
+import (
+	"errors"
+)
+
+var (
+	// ErrCancelled is returned by an election whose exit was closed
+	// before it was elected
+	ErrCancelled = errors.New("election cancelled")
+)
+
 // Leader provides leadership election
 type Leader interface {
-	// elect leader
+	// elect leader, blocks until elected or the exit option is closed
 	Elect(id string, opts ...ElectOption) (Elected, error)
-	// follow the leader
+	// follow the leader, the ids of the leaders elected are sent until
+	// the context of the options is done, then the channel is closed
 	Follow() chan string
 }
 
@@ -0,0 +1,302 @@
+// Package lock provides leader election built on the lock interface
+package lock
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/sync/leader"
+	"github.com/stack-labs/stack/sync/lock"
+	"github.com/stack-labs/stack/sync/lock/memory"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultPrefix is prepended to the lock id of every election
+	DefaultPrefix = "leader"
+	// DefaultTTL is the leadership lease used when none is specified
+	DefaultTTL = 15 * time.Second
+
+	errLeaseExpired = errors.New("lease expired")
+)
+
+// lockLeader holds leadership by holding a lock with a ttl. Renewing the
+// lease means releasing and acquiring the lock again, so every attempt to
+// acquire it is guarded by a second lock which the leader also takes
+// while renewing. Candidates can never take the lock mid renewal.
+type lockLeader struct {
+	group string
+	lock  lock.Lock
+	ttl   time.Duration
+	// unique id of this candidate
+	node string
+	// done stops the followers
+	done <-chan struct{}
+
+	sync.RWMutex
+	followers []chan string
+}
+
+type lockElected struct {
+	leader *lockLeader
+	id     string
+	opts   leader.ElectOptions
+
+	sync.Mutex
+	exit    chan bool
+	revoked chan bool
+}
+
+// keys returns the lock id and guard id of the election
+func (l *lockLeader) keys(id string) (string, string) {
+	key := path.Join(DefaultPrefix, l.group, id)
+	return key, path.Join(key, "guard")
+}
+
+// interval is how often the lease is renewed
+func (l *lockLeader) interval() time.Duration {
+	return l.ttl / 3
+}
+
+// poll is how long an attempt waits on a held lock
+func (l *lockLeader) poll() time.Duration {
+	return l.ttl / 10
+}
+
+// acquire tries to take the election lock under the guard. A non zero
+// renewed time means the lock is held since then and is renewed instead.
+func (l *lockLeader) acquire(id string, renewed time.Time) error {
+	key, guard := l.keys(id)
+
+	if err := l.lock.Acquire(guard, lock.TTL(l.ttl), lock.Wait(l.interval())); err != nil {
+		return err
+	}
+	defer l.lock.Release(guard)
+
+	if !renewed.IsZero() {
+		// the lock may already be held by someone else
+		if time.Since(renewed) >= l.ttl {
+			return errLeaseExpired
+		}
+		if err := l.lock.Release(key); err != nil {
+			return err
+		}
+	}
+
+	return l.lock.Acquire(key, lock.TTL(l.ttl), lock.Wait(l.poll()))
+}
+
+// notify sends the new leader to all followers
+func (l *lockLeader) notify(node string) {
+	l.RLock()
+	defer l.RUnlock()
+
+	for _, ch := range l.followers {
+		select {
+		case ch <- node:
+		default:
+		}
+	}
+}
+
+func (l *lockLeader) Elect(id string, opts ...leader.ElectOption) (leader.Elected, error) {
+	var options leader.ElectOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	e := &lockElected{
+		leader: l,
+		id:     id,
+		opts:   options,
+	}
+
+	if err := e.campaign(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Follow streams the id of the leader whenever this process wins an
+// election. The lock interface can not be observed so, unlike the registry
+// leader, elections won by other processes are not seen. The channel is
+// closed once the context of the options is done.
+func (l *lockLeader) Follow() chan string {
+	ch := make(chan string, 1)
+
+	l.Lock()
+	l.followers = append(l.followers, ch)
+	l.Unlock()
+
+	if l.done != nil {
+		go l.unfollow(ch)
+	}
+
+	return ch
+}
+
+// unfollow closes the follower once the context is done
+func (l *lockLeader) unfollow(ch chan string) {
+	<-l.done
+
+	l.Lock()
+	defer l.Unlock()
+
+	for i, f := range l.followers {
+		if f == ch {
+			l.followers = append(l.followers[:i], l.followers[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+// campaign blocks until the election lock is acquired, or the exit
+// of the options is closed
+func (e *lockElected) campaign() error {
+	l := e.leader
+
+	for {
+		err := l.acquire(e.id, time.Time{})
+		if err == nil {
+			break
+		}
+		if err != lock.ErrLockTimeout {
+			log.Debugf("[leader] election %s acquire error: %v", e.id, err)
+		}
+
+		select {
+		case <-e.opts.Exit:
+			return leader.ErrCancelled
+		case <-time.After(l.interval()):
+		}
+	}
+
+	exit := make(chan bool)
+	revoked := make(chan bool)
+
+	e.Lock()
+	e.exit = exit
+	e.revoked = revoked
+	e.Unlock()
+
+	go e.renew(exit, revoked)
+
+	l.notify(l.node)
+
+	return nil
+}
+
+// renew keeps the lease alive and revokes leadership once it is lost
+func (e *lockElected) renew(exit, revoked chan bool) {
+	l := e.leader
+	ticker := time.NewTicker(l.interval())
+	defer ticker.Stop()
+
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case <-ticker.C:
+		}
+
+		// resigning waits for the renewal to finish
+		e.Lock()
+		select {
+		case <-exit:
+			e.Unlock()
+			return
+		default:
+		}
+		if err := l.acquire(e.id, renewed); err != nil {
+			log.Debugf("[leader] election %s renew error: %v", e.id, err)
+		} else {
+			renewed = time.Now()
+		}
+		e.Unlock()
+
+		if time.Since(renewed) > l.ttl {
+			log.Debugf("[leader] election %s lease expired", e.id)
+			close(revoked)
+			return
+		}
+	}
+}
+
+func (e *lockElected) Id() string {
+	return e.id
+}
+
+func (e *lockElected) Reelect() error {
+	if err := e.Resign(); err != nil {
+		return err
+	}
+	return e.campaign()
+}
+
+func (e *lockElected) Resign() error {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.exit == nil {
+		return nil
+	}
+
+	select {
+	case <-e.exit:
+		return nil
+	default:
+		close(e.exit)
+	}
+
+	select {
+	case <-e.revoked:
+		// the lock may be held by someone else
+		return nil
+	default:
+	}
+
+	key, _ := e.leader.keys(e.id)
+	return e.leader.lock.Release(key)
+}
+
+func (e *lockElected) Revoked() chan bool {
+	e.Lock()
+	defer e.Unlock()
+	return e.revoked
+}
+
+// NewLeader returns a leader election built on a lock
+func NewLeader(opts ...leader.Option) leader.Leader {
+	options := leader.Options{
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.TTL <= time.Duration(0) {
+		options.TTL = DefaultTTL
+	}
+
+	lk, ok := options.Context.Value(lockKey{}).(lock.Lock)
+	if !ok {
+		lk = memory.NewLock()
+	}
+
+	return &lockLeader{
+		group: options.Group,
+		lock:  lk,
+		ttl:   options.TTL,
+		node:  uuid.New().String(),
+		done:  options.Context.Done(),
+	}
+}
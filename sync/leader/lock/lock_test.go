@@ -0,0 +1,118 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/sync/leader"
+	"github.com/stack-labs/stack/sync/lock/memory"
+)
+
+func TestLockLeader(t *testing.T) {
+	lk := memory.NewLock()
+	ttl := 300 * time.Millisecond
+
+	var leaders []leader.Leader
+	for i := 0; i < 3; i++ {
+		leaders = append(leaders, NewLeader(Lock(lk), leader.TTL(ttl)))
+	}
+
+	ch := make(chan leader.Elected, len(leaders))
+
+	for _, l := range leaders {
+		go func(l leader.Leader) {
+			e, err := l.Elect("foo")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ch <- e
+		}(l)
+	}
+
+	for i := 0; i < len(leaders); i++ {
+		var e leader.Elected
+
+		select {
+		case e = <-ch:
+		case <-time.After(ttl * 10):
+			t.Fatalf("expected leader %d to be elected", i)
+		}
+
+		// hold leadership across several renewals
+		select {
+		case <-ch:
+			t.Fatal("more than one leader elected")
+		case <-e.Revoked():
+			t.Fatal("leadership revoked while renewing")
+		case <-time.After(ttl * 2):
+		}
+
+		if err := e.Resign(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLockLeaderFollow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := NewLeader(leader.Context(ctx), leader.TTL(300*time.Millisecond))
+	follow := l.Follow()
+
+	e, err := l.Elect("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Resign()
+
+	select {
+	case id := <-follow:
+		if id != l.(*lockLeader).node {
+			t.Fatalf("expected leader %s got %s", l.(*lockLeader).node, id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected follow to report the leader")
+	}
+
+	// the channel is closed once the context is done
+	cancel()
+	select {
+	case _, ok := <-follow:
+		if ok {
+			t.Fatal("expected follow to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected follow to stop once the context is done")
+	}
+}
+
+func TestLockLeaderCancel(t *testing.T) {
+	lk := memory.NewLock()
+	ttl := 300 * time.Millisecond
+
+	e, err := NewLeader(Lock(lk), leader.TTL(ttl)).Elect("baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Resign()
+
+	exit := make(chan bool)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := NewLeader(Lock(lk), leader.TTL(ttl)).Elect("baz", leader.Exit(exit))
+		errc <- err
+	}()
+
+	time.Sleep(ttl / 2)
+	close(exit)
+
+	select {
+	case err := <-errc:
+		if err != leader.ErrCancelled {
+			t.Fatalf("expected %v got %v", leader.ErrCancelled, err)
+		}
+	case <-time.After(ttl * 3):
+		t.Fatal("expected the election to be cancelled")
+	}
+}
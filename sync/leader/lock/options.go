@@ -0,0 +1,20 @@
+package lock
+
+import (
+	"context"
+
+	"github.com/stack-labs/stack/sync/leader"
+	"github.com/stack-labs/stack/sync/lock"
+)
+
+type lockKey struct{}
+
+// Lock sets the lock candidates compete for
+func Lock(l lock.Lock) leader.Option {
+	return func(o *leader.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, lockKey{}, l)
+	}
+}
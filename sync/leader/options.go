@@ -1,11 +1,24 @@
 package leader
 
+import (
+	"context"
+	"time"
+)
+
 type Options struct {
 	Nodes []string
 	Group string
+	// TTL is the leadership lease which must be renewed
+	TTL time.Duration
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
 }
 
-type ElectOptions struct{}
+type ElectOptions struct {
+	// Exit cancels the election once closed, if not elected yet
+	Exit chan bool
+}
 
 // Nodes sets the addresses of the underlying systems
 func Nodes(a ...string) Option {
@@ -20,3 +33,28 @@ func Group(g string) Option {
 		o.Group = g
 	}
 }
+
+// TTL sets the leadership lease, a leader that fails to renew
+// within the ttl has its leadership revoked
+func TTL(t time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = t
+	}
+}
+
+// Context sets the context of the options, followers are stopped once it's
+// done. It replaces the context so it must come before the options storing
+// values in it.
+func Context(ctx context.Context) Option {
+	return func(o *Options) {
+		o.Context = ctx
+	}
+}
+
+// Exit cancels the election once ch is closed, if not elected yet.
+// Elect then returns ErrCancelled.
+func Exit(ch chan bool) ElectOption {
+	return func(o *ElectOptions) {
+		o.Exit = ch
+	}
+}
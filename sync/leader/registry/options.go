@@ -0,0 +1,20 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/sync/leader"
+)
+
+type registryKey struct{}
+
+// Registry sets the registry candidates register with
+func Registry(r registry.Registry) leader.Option {
+	return func(o *leader.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, registryKey{}, r)
+	}
+}
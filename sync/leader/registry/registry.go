@@ -0,0 +1,416 @@
+// Package registry provides leader election built on the registry
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/registry/mdns"
+	"github.com/stack-labs/stack/sync/leader"
+	"github.com/stack-labs/stack/util/addr"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultPrefix is prepended to the service name of every election
+	DefaultPrefix = "stack.leader"
+	// DefaultTTL is the leadership lease used when none is specified
+	DefaultTTL = 15 * time.Second
+
+	// metadata keys of a candidate node
+	electionKey  = "election"
+	timestampKey = "timestamp"
+)
+
+type registryLeader struct {
+	opts     leader.Options
+	registry registry.Registry
+	// unique id of this candidate
+	node    string
+	address string
+}
+
+type registryElected struct {
+	leader *registryLeader
+	id     string
+	opts   leader.ElectOptions
+
+	sync.Mutex
+	service *registry.Service
+	exit    chan bool
+	revoked chan bool
+}
+
+// name returns the service name used for the election id
+func (r *registryLeader) name(id string) string {
+	parts := []string{DefaultPrefix}
+	if len(r.opts.Group) > 0 {
+		parts = append(parts, r.opts.Group)
+	}
+	return strings.Join(append(parts, id), ".")
+}
+
+// interval is how often the lease is renewed and the leader checked
+func (r *registryLeader) interval() time.Duration {
+	return r.opts.TTL / 3
+}
+
+// candidate builds a new registration for the election. The timestamp
+// orders candidates, the earliest live candidate is the leader.
+func (r *registryLeader) candidate(id string) *registry.Service {
+	return &registry.Service{
+		Name:    r.name(id),
+		Version: "leader",
+		Nodes: []*registry.Node{{
+			Id:      r.node,
+			Address: r.address,
+			Metadata: map[string]string{
+				electionKey:  id,
+				timestampKey: fmt.Sprintf("%d", time.Now().UnixNano()),
+			},
+		}},
+	}
+}
+
+// lookup returns the nodes currently standing in the election
+func (r *registryLeader) lookup(name string) ([]*registry.Node, error) {
+	services, err := r.registry.GetService(name)
+	if err == registry.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var nodes []*registry.Node
+	for _, s := range services {
+		nodes = append(nodes, s.Nodes...)
+	}
+	return nodes, nil
+}
+
+// current returns the id of the node leading the election
+func (r *registryLeader) current(name string) (string, error) {
+	nodes, err := r.lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	var id string
+	var first int64
+
+	for _, n := range nodes {
+		ts, err := strconv.ParseInt(n.Metadata[timestampKey], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(id) == 0 || ts < first || (ts == first && n.Id < id) {
+			id = n.Id
+			first = ts
+		}
+	}
+
+	return id, nil
+}
+
+// contains checks the node is still registered in the election
+func (r *registryLeader) contains(name string) (bool, error) {
+	nodes, err := r.lookup(name)
+	if err != nil {
+		return false, err
+	}
+	for _, n := range nodes {
+		if n.Id == r.node {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *registryLeader) register(s *registry.Service) error {
+	return r.registry.Register(s, registry.RegisterTTL(r.opts.TTL))
+}
+
+// watch notifies on any change to the election until exit is closed
+func (r *registryLeader) watch(name string, exit chan bool) chan bool {
+	notify := make(chan bool, 1)
+
+	var opts []registry.WatchOption
+	if len(name) > 0 {
+		opts = append(opts, registry.WatchService(name))
+	}
+
+	w, err := r.registry.Watch(opts...)
+	if err != nil {
+		// fallback to polling only
+		log.Debugf("[leader] registry watch error: %v", err)
+		return notify
+	}
+
+	go func() {
+		<-exit
+		w.Stop()
+	}()
+
+	go func() {
+		for {
+			if _, err := w.Next(); err != nil {
+				return
+			}
+			select {
+			case notify <- true:
+			default:
+			}
+		}
+	}()
+
+	return notify
+}
+
+func (r *registryLeader) Elect(id string, opts ...leader.ElectOption) (leader.Elected, error) {
+	var options leader.ElectOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	e := &registryElected{
+		leader: r,
+		id:     id,
+		opts:   options,
+	}
+
+	if err := e.campaign(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Follow sends the id of every newly elected leader of the elections in
+// the group, whichever process wins them. The channel is closed once the
+// context of the options is done.
+func (r *registryLeader) Follow() chan string {
+	exit := make(chan bool)
+	if done := r.opts.Context.Done(); done != nil {
+		go func() {
+			<-done
+			close(exit)
+		}()
+	}
+
+	ch := make(chan string)
+	go r.follow(ch, exit)
+	return ch
+}
+
+// follow sends the id of every newly elected leader in the group until exit is closed
+func (r *registryLeader) follow(ch chan string, exit chan bool) {
+	defer close(ch)
+
+	prefix := r.name("")
+	leaders := make(map[string]string)
+
+	notify := r.watch("", exit)
+	ticker := time.NewTicker(r.interval())
+	defer ticker.Stop()
+
+	for {
+		services, err := r.registry.ListServices()
+		if err != nil {
+			log.Debugf("[leader] registry list error: %v", err)
+		}
+
+		for _, s := range services {
+			if !strings.HasPrefix(s.Name, prefix) {
+				continue
+			}
+			id, err := r.current(s.Name)
+			if err != nil || len(id) == 0 || leaders[s.Name] == id {
+				continue
+			}
+			leaders[s.Name] = id
+
+			select {
+			case ch <- id:
+			case <-exit:
+				return
+			}
+		}
+
+		select {
+		case <-notify:
+		case <-ticker.C:
+		case <-exit:
+			return
+		}
+	}
+}
+
+// campaign registers as a candidate and blocks until elected, or the
+// exit of the options is closed
+func (e *registryElected) campaign() error {
+	r := e.leader
+	s := r.candidate(e.id)
+
+	if err := r.register(s); err != nil {
+		return err
+	}
+
+	exit := make(chan bool)
+	notify := r.watch(s.Name, exit)
+	ticker := time.NewTicker(r.interval())
+	defer ticker.Stop()
+
+	for {
+		id, err := r.current(s.Name)
+		if err != nil {
+			log.Debugf("[leader] election %s lookup error: %v", s.Name, err)
+		} else if id == r.node {
+			break
+		}
+
+		select {
+		case <-notify:
+			continue
+		case <-ticker.C:
+		case <-e.opts.Exit:
+			close(exit)
+			if err := r.registry.Deregister(s); err != nil {
+				log.Debugf("[leader] election %s deregister error: %v", s.Name, err)
+			}
+			return leader.ErrCancelled
+		}
+
+		// a pruned candidate must stand again at the back of the queue
+		if ok, err := r.contains(s.Name); err == nil && !ok {
+			s = r.candidate(e.id)
+		}
+		if err := r.register(s); err != nil {
+			log.Debugf("[leader] election %s register error: %v", s.Name, err)
+		}
+	}
+
+	revoked := make(chan bool)
+
+	e.Lock()
+	e.service = s
+	e.exit = exit
+	e.revoked = revoked
+	e.Unlock()
+
+	go e.renew(s, exit, revoked)
+
+	return nil
+}
+
+// renew keeps the lease alive and revokes leadership once it is lost
+func (e *registryElected) renew(s *registry.Service, exit, revoked chan bool) {
+	r := e.leader
+	ticker := time.NewTicker(r.interval())
+	defer ticker.Stop()
+
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case <-ticker.C:
+		}
+
+		id, err := r.current(s.Name)
+		switch {
+		case err != nil:
+			log.Debugf("[leader] election %s lookup error: %v", s.Name, err)
+		case id != r.node:
+			log.Debugf("[leader] election %s lost to %s", s.Name, id)
+			close(revoked)
+			return
+		default:
+			if err := r.register(s); err != nil {
+				log.Debugf("[leader] election %s renew error: %v", s.Name, err)
+			} else {
+				renewed = time.Now()
+			}
+		}
+
+		if time.Since(renewed) > r.opts.TTL {
+			log.Debugf("[leader] election %s lease expired", s.Name)
+			close(revoked)
+			return
+		}
+	}
+}
+
+func (e *registryElected) Id() string {
+	return e.id
+}
+
+func (e *registryElected) Reelect() error {
+	if err := e.Resign(); err != nil {
+		return err
+	}
+	return e.campaign()
+}
+
+func (e *registryElected) Resign() error {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.exit == nil {
+		return nil
+	}
+
+	select {
+	case <-e.exit:
+		return nil
+	default:
+		close(e.exit)
+	}
+
+	return e.leader.registry.Deregister(e.service)
+}
+
+func (e *registryElected) Revoked() chan bool {
+	e.Lock()
+	defer e.Unlock()
+	return e.revoked
+}
+
+// NewLeader returns a leader election built on the registry
+func NewLeader(opts ...leader.Option) leader.Leader {
+	options := leader.Options{
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.TTL <= time.Duration(0) {
+		options.TTL = DefaultTTL
+	}
+
+	r, ok := options.Context.Value(registryKey{}).(registry.Registry)
+	if !ok {
+		r = mdns.NewRegistry(registry.Addrs(options.Nodes...))
+	}
+
+	host, err := addr.Extract("")
+	if err != nil {
+		host = "127.0.0.1"
+	}
+
+	return &registryLeader{
+		opts:     options,
+		registry: r,
+		node:     uuid.New().String(),
+		address:  net.JoinHostPort(host, "0"),
+	}
+}
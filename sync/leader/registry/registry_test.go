@@ -0,0 +1,197 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/registry/memory"
+	"github.com/stack-labs/stack/sync/leader"
+)
+
+func TestRegistryLeader(t *testing.T) {
+	r := memory.NewRegistry()
+	ttl := 300 * time.Millisecond
+
+	var leaders []leader.Leader
+	for i := 0; i < 3; i++ {
+		leaders = append(leaders, NewLeader(Registry(r), leader.TTL(ttl), leader.Group("test")))
+	}
+
+	// a follower sees the elections won by any candidate
+	follow := NewLeader(Registry(r), leader.TTL(ttl), leader.Group("test")).Follow()
+
+	ch := make(chan leader.Elected, len(leaders))
+
+	for _, l := range leaders {
+		go func(l leader.Leader) {
+			e, err := l.Elect("foo")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ch <- e
+		}(l)
+	}
+
+	for i := 0; i < len(leaders); i++ {
+		var e leader.Elected
+
+		select {
+		case e = <-ch:
+		case <-time.After(ttl * 10):
+			t.Fatalf("expected leader %d to be elected", i)
+		}
+
+		if e.Id() != "foo" {
+			t.Fatalf("expected election id foo got %s", e.Id())
+		}
+
+		// no one else should lead while elected
+		select {
+		case <-ch:
+			t.Fatal("more than one leader elected")
+		case <-time.After(ttl):
+		}
+
+		select {
+		case <-e.Revoked():
+			t.Fatal("leadership revoked while renewing")
+		default:
+		}
+
+		select {
+		case id := <-follow:
+			if len(id) == 0 {
+				t.Fatal("expected leader id from follow")
+			}
+		case <-time.After(ttl * 2):
+			t.Fatal("expected follow to report the leader")
+		}
+
+		if err := e.Resign(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRegistryLeaderRevoked(t *testing.T) {
+	r := memory.NewRegistry()
+	ttl := 300 * time.Millisecond
+
+	l := NewLeader(Registry(r), leader.TTL(ttl))
+	e, err := l.Elect("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another candidate steals the election by being registered earlier
+	s := l.(*registryLeader).candidate("bar")
+	s.Nodes[0].Id = "thief"
+	s.Nodes[0].Metadata[timestampKey] = "0"
+	if err := r.Register(s); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-e.Revoked():
+	case <-time.After(ttl * 3):
+		t.Fatal("expected leadership to be revoked")
+	}
+
+	if err := e.Resign(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryLeaderUnfollow(t *testing.T) {
+	r := memory.NewRegistry()
+	ttl := 300 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := NewLeader(leader.Context(ctx), Registry(r), leader.TTL(ttl))
+	follow := l.Follow()
+
+	e, err := l.Elect("baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Resign(); err != nil {
+		t.Fatal(err)
+	}
+
+	// another election still reaches the follower once baz resigned
+	e, err = l.Elect("qux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Resign()
+
+	timeout := time.After(ttl * 3)
+	for qux := false; !qux; {
+		select {
+		case id, ok := <-follow:
+			if !ok {
+				t.Fatal("expected follow to go on once an election resigned")
+			}
+			qux = id == l.(*registryLeader).node
+		case <-timeout:
+			t.Fatal("expected follow to report the leader of qux")
+		}
+	}
+
+	// the channel is closed once the context is done
+	cancel()
+	timeout = time.After(ttl * 3)
+	for {
+		select {
+		case _, ok := <-follow:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("expected follow to stop once the context is done")
+		}
+	}
+}
+
+func TestRegistryLeaderCancel(t *testing.T) {
+	r := memory.NewRegistry()
+	ttl := 300 * time.Millisecond
+
+	a := NewLeader(Registry(r), leader.TTL(ttl))
+	e, err := a.Elect("quux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Resign()
+
+	b := NewLeader(Registry(r), leader.TTL(ttl))
+	exit := make(chan bool)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := b.Elect("quux", leader.Exit(exit))
+		errc <- err
+	}()
+
+	time.Sleep(ttl / 2)
+	close(exit)
+
+	select {
+	case err := <-errc:
+		if err != leader.ErrCancelled {
+			t.Fatalf("expected %v got %v", leader.ErrCancelled, err)
+		}
+	case <-time.After(ttl * 3):
+		t.Fatal("expected the election to be cancelled")
+	}
+
+	// the cancelled candidate has left the election
+	nodes, err := b.(*registryLeader).lookup(b.(*registryLeader).name("quux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("expected the leader alone in the election got %d nodes", len(nodes))
+	}
+}
@@ -9,14 +9,18 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/stack-labs/stack/registry"
 	"github.com/stack-labs/stack/sync/lock"
 )
 
 var (
 	DefaultPath    = "/sync/lock"
 	DefaultAddress = "localhost:8080"
+	// DefaultService is the name lock servers register with
+	DefaultService = "stack.sync.lock"
 )
 
 type httpLock struct {
@@ -27,16 +31,17 @@ func (h *httpLock) url(do, id string) (string, error) {
 	sum := crc32.ChecksumIEEE([]byte(id))
 	node := h.opts.Nodes[sum%uint32(len(h.opts.Nodes))]
 
+	// a host:port doesn't parse as a url without a scheme
+	if !strings.Contains(node, "://") {
+		node = "http://" + node
+	}
+
 	// parse the host:port or whatever
 	uri, err := url.Parse(node)
 	if err != nil {
 		return "", err
 	}
 
-	if len(uri.Scheme) == 0 {
-		uri.Scheme = "http"
-	}
-
 	// set path
 	// build path
 	path := filepath.Join(DefaultPath, do, h.opts.Prefix, id)
@@ -80,6 +85,10 @@ func (h *httpLock) Acquire(id string, opts ...lock.AcquireOption) error {
 		return nil
 	}
 
+	if rsp.StatusCode == http.StatusRequestTimeout {
+		return lock.ErrLockTimeout
+	}
+
 	// return error
 	return errors.New(string(b))
 }
@@ -119,6 +128,31 @@ func (h *httpLock) Release(id string) error {
 	return errors.New(string(b))
 }
 
+// Lookup returns the addresses of the lock servers in the registry, to be
+// passed to lock.Nodes. The addresses are sorted so every client sends a
+// lock id to the same server.
+func Lookup(r registry.Registry) ([]string, error) {
+	services, err := r.GetService(DefaultService)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []string
+	for _, s := range services {
+		for _, n := range s.Nodes {
+			nodes = append(nodes, n.Address)
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil, registry.ErrNotFound
+	}
+
+	sort.Strings(nodes)
+
+	return nodes, nil
+}
+
 func NewLock(opts ...lock.Option) lock.Lock {
 	var options lock.Options
 	for _, o := range opts {
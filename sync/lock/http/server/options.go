@@ -0,0 +1,67 @@
+package server
+
+import (
+	"time"
+
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/sync/lock"
+)
+
+type Options struct {
+	// Name the server registers with
+	Name string
+	// Address to listen on
+	Address string
+	// Lock backing the server, defaults to a memory lock
+	Lock     lock.Lock
+	Registry registry.Registry
+	// RegisterTTL of the server in the registry
+	RegisterTTL time.Duration
+	// RegisterInterval the server registers itself at
+	RegisterInterval time.Duration
+}
+
+type Option func(o *Options)
+
+// Name of the server in the registry
+func Name(n string) Option {
+	return func(o *Options) {
+		o.Name = n
+	}
+}
+
+// Address to listen on
+func Address(a string) Option {
+	return func(o *Options) {
+		o.Address = a
+	}
+}
+
+// Lock sets the lock backing the server. Servers sharing a store
+// lock exclude each other's holders.
+func Lock(l lock.Lock) Option {
+	return func(o *Options) {
+		o.Lock = l
+	}
+}
+
+// Registry to register the server in
+func Registry(r registry.Registry) Option {
+	return func(o *Options) {
+		o.Registry = r
+	}
+}
+
+// RegisterTTL sets the ttl of the server in the registry
+func RegisterTTL(t time.Duration) Option {
+	return func(o *Options) {
+		o.RegisterTTL = t
+	}
+}
+
+// RegisterInterval sets how often the server registers itself
+func RegisterInterval(t time.Duration) Option {
+	return func(o *Options) {
+		o.RegisterInterval = t
+	}
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/sync/lock"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultPoll is the longest a waiter blocks on the backing lock
+	// before checking its request is still alive
+	DefaultPoll = time.Second
+)
+
+// queues hands out locks to waiters in the order they arrived
+type queues struct {
+	lock lock.Lock
+
+	sync.Mutex
+	queues map[string]*queue
+}
+
+// queue of a lock id. The first waiter holds the lock or is acquiring it
+// from the backing lock, the others wait for their turn.
+type queue struct {
+	waiters []chan bool
+	held    bool
+	// generation of the holder so a stale expiry is ignored
+	gen    uint64
+	expiry *time.Timer
+}
+
+// next hands the lock to the next waiter
+func (q *queues) next(id string, qu *queue) {
+	if qu.expiry != nil {
+		qu.expiry.Stop()
+		qu.expiry = nil
+	}
+
+	qu.held = false
+	qu.waiters = qu.waiters[1:]
+
+	if len(qu.waiters) == 0 {
+		delete(q.queues, id)
+		return
+	}
+
+	close(qu.waiters[0])
+}
+
+// leave removes a waiter giving up before holding the lock
+func (q *queues) leave(id string, ch chan bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	qu, ok := q.queues[id]
+	if !ok {
+		return
+	}
+
+	for i, w := range qu.waiters {
+		if w != ch {
+			continue
+		}
+		// it was our turn
+		if i == 0 {
+			q.next(id, qu)
+			return
+		}
+		qu.waiters = append(qu.waiters[:i], qu.waiters[i+1:]...)
+		return
+	}
+}
+
+func (q *queues) acquire(ctx context.Context, id string, ttl, wait time.Duration) error {
+	ch := make(chan bool)
+
+	q.Lock()
+	qu, ok := q.queues[id]
+	if !ok {
+		qu = new(queue)
+		q.queues[id] = qu
+	}
+	qu.waiters = append(qu.waiters, ch)
+	if len(qu.waiters) == 1 {
+		close(ch)
+	}
+	q.Unlock()
+
+	// zero waits until acquired or the request is gone
+	var timeout <-chan time.Time
+	var deadline time.Time
+	if wait > time.Duration(0) {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		timeout = t.C
+		deadline = time.Now().Add(wait)
+	}
+
+	select {
+	case <-ch:
+	case <-timeout:
+		q.leave(id, ch)
+		return lock.ErrLockTimeout
+	case <-ctx.Done():
+		q.leave(id, ch)
+		return ctx.Err()
+	}
+
+	// our turn, take the backing lock which other servers may hold
+	for {
+		poll := DefaultPoll
+		if !deadline.IsZero() {
+			if left := time.Until(deadline); left <= time.Duration(0) {
+				q.leave(id, ch)
+				return lock.ErrLockTimeout
+			} else if left < poll {
+				poll = left
+			}
+		}
+
+		err := q.lock.Acquire(id, lock.TTL(ttl), lock.Wait(poll))
+		if err == nil {
+			break
+		}
+		if err != lock.ErrLockTimeout {
+			q.leave(id, ch)
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			q.leave(id, ch)
+			return err
+		}
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	qu.held = true
+	qu.gen++
+
+	// the request is gone so nobody would release the lock
+	if err := ctx.Err(); err != nil {
+		q.lock.Release(id)
+		q.next(id, qu)
+		return err
+	}
+
+	if ttl > time.Duration(0) {
+		gen := qu.gen
+		qu.expiry = time.AfterFunc(ttl, func() {
+			q.expire(id, gen)
+		})
+	}
+
+	return nil
+}
+
+func (q *queues) release(id string) error {
+	q.Lock()
+	defer q.Unlock()
+
+	qu, ok := q.queues[id]
+	// not held
+	if !ok || !qu.held {
+		return nil
+	}
+
+	err := q.lock.Release(id)
+	q.next(id, qu)
+
+	return err
+}
+
+// expire releases the lock if the holder didn't before its ttl
+func (q *queues) expire(id string, gen uint64) {
+	q.Lock()
+	defer q.Unlock()
+
+	qu, ok := q.queues[id]
+	if !ok || !qu.held || qu.gen != gen {
+		return
+	}
+
+	log.Logf("[lock] lock %s expired", id)
+
+	if err := q.lock.Release(id); err != nil {
+		log.Logf("[lock] error releasing expired lock %s: %v", id, err)
+	}
+	q.next(id, qu)
+}
+
+func newQueues(l lock.Lock) *queues {
+	return &queues{
+		lock:   l,
+		queues: make(map[string]*queue),
+	}
+}
@@ -2,34 +2,117 @@
 package server
 
 import (
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/registry/mdns"
 	"github.com/stack-labs/stack/sync/lock"
 	lkhttp "github.com/stack-labs/stack/sync/lock/http"
+	"github.com/stack-labs/stack/sync/lock/memory"
+	"github.com/stack-labs/stack/util/addr"
+	"github.com/stack-labs/stack/util/log"
+	mnet "github.com/stack-labs/stack/util/net"
 )
 
+var (
+	DefaultRegisterTTL      = time.Second * 30
+	DefaultRegisterInterval = time.Second * 15
+)
+
+// Service is a lock server registered in the registry
+type Service struct {
+	opts    Options
+	id      string
+	handler http.Handler
+
+	sync.Mutex
+	server  *http.Server
+	address string
+	exit    chan bool
+}
+
+// seconds parses a form value in seconds
+func seconds(r *http.Request, key string) (time.Duration, error) {
+	v := r.Form.Get(key)
+	if len(v) == 0 {
+		return time.Duration(0), nil
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Duration(0), err
+	}
+
+	return time.Duration(n) * time.Second, nil
+}
+
+// Handler serves the lock protocol of the http lock. Waiters of a lock
+// are served in the order they arrived and locks acquired with a ttl
+// are released once it passes.
 func Handler(lk lock.Lock) http.Handler {
+	q := newQueues(lk)
 	mux := http.NewServeMux()
 
-	mux.HandleFunc(lkhttp.DefaultPath, func(w http.ResponseWriter, r *http.Request) {
-		r.ParseForm()
+	acquire := lkhttp.DefaultPath + "/acquire/"
+	release := lkhttp.DefaultPath + "/release/"
 
-		id := r.Form.Get("id")
+	mux.HandleFunc(acquire, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, acquire)
 		if len(id) == 0 {
+			http.Error(w, "missing id", http.StatusBadRequest)
 			return
 		}
 
-		switch r.Method {
-		case "POST":
-			err := lk.Acquire(id)
-			if err != nil {
-				http.Error(w, err.Error(), 500)
-			}
-		case "DELETE":
-			err := lk.Release(id)
-			if err != nil {
-				http.Error(w, err.Error(), 500)
-			}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ttl, err := seconds(r, "ttl")
+		if err != nil {
+			http.Error(w, "invalid ttl", http.StatusBadRequest)
+			return
+		}
+
+		wait, err := seconds(r, "wait")
+		if err != nil {
+			http.Error(w, "invalid wait", http.StatusBadRequest)
+			return
+		}
+
+		err = q.acquire(r.Context(), id, ttl, wait)
+		if err == lock.ErrLockTimeout {
+			http.Error(w, err.Error(), http.StatusRequestTimeout)
+		} else if err != nil {
+			http.Error(w, err.Error(), 500)
+		}
+	})
+
+	mux.HandleFunc(release, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, release)
+		if len(id) == 0 {
+			http.Error(w, "missing id", http.StatusBadRequest)
+			return
+		}
+
+		if err := q.release(id); err != nil {
+			http.Error(w, err.Error(), 500)
 		}
 	})
 
@@ -43,3 +126,148 @@ func Server(lk lock.Lock) *http.Server {
 	}
 	return server
 }
+
+func (s *Service) service() (*registry.Service, error) {
+	s.Lock()
+	address := s.address
+	s.Unlock()
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err = addr.Extract(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &registry.Service{
+		Name:    s.opts.Name,
+		Version: "latest",
+		Nodes: []*registry.Node{{
+			Id:      s.opts.Name + "-" + s.id,
+			Address: mnet.HostPort(host, port),
+			Metadata: map[string]string{
+				"protocol": "http",
+				"registry": s.opts.Registry.String(),
+			},
+		}},
+	}, nil
+}
+
+func (s *Service) register() error {
+	service, err := s.service()
+	if err != nil {
+		return err
+	}
+
+	return s.opts.Registry.Register(service, registry.RegisterTTL(s.opts.RegisterTTL))
+}
+
+func (s *Service) deregister() error {
+	service, err := s.service()
+	if err != nil {
+		return err
+	}
+
+	return s.opts.Registry.Deregister(service)
+}
+
+// Address the service listens on once started
+func (s *Service) Address() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.address
+}
+
+// Start listening and register the service
+func (s *Service) Start() error {
+	ln, err := net.Listen("tcp", s.opts.Address)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Lock server listening on %s", ln.Addr().String())
+
+	server := &http.Server{Handler: s.handler}
+	exit := make(chan bool)
+
+	s.Lock()
+	s.address = ln.Addr().String()
+	s.server = server
+	s.exit = exit
+	s.Unlock()
+
+	if err := s.register(); err != nil {
+		ln.Close()
+		return err
+	}
+
+	go server.Serve(ln)
+
+	go func() {
+		t := time.NewTicker(s.opts.RegisterInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-t.C:
+				if err := s.register(); err != nil {
+					log.Errorf("Lock server register error: %v", err)
+				}
+			case <-exit:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop deregisters the service and closes the listener
+func (s *Service) Stop() error {
+	s.Lock()
+	server := s.server
+	exit := s.exit
+	s.server = nil
+	s.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	close(exit)
+
+	if err := s.deregister(); err != nil {
+		log.Errorf("Lock server deregister error: %v", err)
+	}
+
+	return server.Close()
+}
+
+// NewService returns a lock server backed by a memory lock unless one is set
+func NewService(opts ...Option) *Service {
+	options := Options{
+		Name:             lkhttp.DefaultService,
+		Address:          lkhttp.DefaultAddress,
+		RegisterTTL:      DefaultRegisterTTL,
+		RegisterInterval: DefaultRegisterInterval,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.Lock == nil {
+		options.Lock = memory.NewLock()
+	}
+	if options.Registry == nil {
+		options.Registry = mdns.NewRegistry()
+	}
+
+	return &Service{
+		opts:    options,
+		id:      uuid.New().String(),
+		handler: Handler(options.Lock),
+	}
+}
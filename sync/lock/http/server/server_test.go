@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/registry/memory"
+	"github.com/stack-labs/stack/sync/lock"
+	lkhttp "github.com/stack-labs/stack/sync/lock/http"
+	lmemory "github.com/stack-labs/stack/sync/lock/memory"
+)
+
+func TestService(t *testing.T) {
+	r := memory.NewRegistry()
+
+	s := NewService(Address("127.0.0.1:0"), Registry(r))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	nodes, err := lkhttp.Lookup(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("expected 1 node got %v", nodes)
+	}
+
+	a := lkhttp.NewLock(lock.Nodes(nodes...), lock.Prefix("test"))
+	b := lkhttp.NewLock(lock.Nodes(nodes...), lock.Prefix("test"))
+
+	if err := a.Acquire("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Acquire("foo", lock.Wait(time.Second)); err != lock.ErrLockTimeout {
+		t.Fatalf("expected %v got %v", lock.ErrLockTimeout, err)
+	}
+	if err := b.Acquire("bar", lock.Wait(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- b.Acquire("foo", lock.Wait(time.Second*5))
+	}()
+
+	time.Sleep(time.Millisecond * 50)
+
+	if err := a.Release("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+
+	// the ttl expires the lock
+	if err := b.Release("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Acquire("foo", lock.TTL(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Acquire("foo", lock.Wait(time.Second*3)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lkhttp.Lookup(r); err == nil {
+		t.Fatal("expected the service deregistered")
+	}
+}
+
+func TestFairOrder(t *testing.T) {
+	q := newQueues(lmemory.NewLock())
+	ctx := context.Background()
+
+	if err := q.acquire(ctx, "foo", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var mtx sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := q.acquire(ctx, "foo", 0, time.Second*5); err != nil {
+				t.Error(err)
+				return
+			}
+			mtx.Lock()
+			order = append(order, i)
+			mtx.Unlock()
+			q.release("foo")
+		}(i)
+		// queue the waiters in order
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	// a waiter giving up leaves the queue
+	if err := q.acquire(ctx, "foo", 0, time.Millisecond*10); err != lock.ErrLockTimeout {
+		t.Fatalf("expected %v got %v", lock.ErrLockTimeout, err)
+	}
+
+	// a cancelled request leaves the queue
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := q.acquire(cctx, "foo", 0, 0); err != context.Canceled {
+		t.Fatalf("expected %v got %v", context.Canceled, err)
+	}
+
+	q.release("foo")
+	wg.Wait()
+
+	for i, n := range order {
+		if i != n {
+			t.Fatalf("expected waiters served in order got %v", order)
+		}
+	}
+	if len(order) != 5 {
+		t.Fatalf("expected 5 waiters served got %v", order)
+	}
+
+	q.Lock()
+	defer q.Unlock()
+	if len(q.queues) != 0 {
+		t.Fatalf("expected no queues left got %d", len(q.queues))
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	q := newQueues(lmemory.NewLock())
+	ctx := context.Background()
+
+	if err := q.acquire(ctx, "foo", time.Millisecond*50, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := q.acquire(ctx, "foo", time.Millisecond*50, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < time.Millisecond*40 {
+		t.Fatal("expected the lock held until its ttl")
+	}
+
+	// the first expiry doesn't release the new holder
+	if err := q.release("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.acquire(ctx, "foo", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 100)
+
+	if err := q.acquire(ctx, "foo", 0, time.Millisecond*10); err != lock.ErrLockTimeout {
+		t.Fatalf("expected %v got %v", lock.ErrLockTimeout, err)
+	}
+}
@@ -18,6 +18,15 @@ type Lock interface {
 	Release(id string) error
 }
 
+// Fencing is implemented by locks issuing a fencing token on acquire.
+// The tokens of a lock id increase with every acquire, so a resource
+// can reject the writes of a holder whose lock has since expired.
+type Fencing interface {
+	Lock
+	// AcquireToken acquires the lock and returns its fencing token
+	AcquireToken(id string, opts ...AcquireOption) (uint64, error)
+}
+
 type Options struct {
 	Nodes  []string
 	Prefix string
@@ -63,7 +63,7 @@ func (m *memoryLock) Acquire(id string, opts ...lock.AcquireOption) error {
 			// release the lock if it expired
 			_ = m.Release(id)
 		} else {
-			ttl = time.After(live)
+			ttl = time.After(lk.ttl - live)
 		}
 	}
 
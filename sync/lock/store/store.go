@@ -0,0 +1,283 @@
+// Package store provides a lock kept in a store with fencing tokens
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/sync/lock"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultTable of the locks in the store
+	DefaultTable = "lock"
+	// DefaultTokenTable of the fencing token counters of the locks, apart
+	// from the locks so a counter can't be taken for a lock of that key
+	DefaultTokenTable = "lock_token"
+	// DefaultPoll is how often a held lock is checked while waiting
+	DefaultPoll = time.Millisecond * 50
+
+	// ErrLockLost is returned releasing a lock whose lease expired
+	ErrLockLost = errors.New("lock lost")
+)
+
+type storeLock struct {
+	opts  lock.Options
+	store store.Store
+	// the store supports compare and swap
+	cas store.CAS
+
+	sync.Mutex
+	leases map[string]*lease
+}
+
+// lease is a lock held by this process
+type lease struct {
+	key   string
+	value []byte
+	ttl   time.Duration
+	once  sync.Once
+	exit  chan bool
+}
+
+// stop renewing the lease
+func (l *lease) stop() {
+	l.once.Do(func() {
+		close(l.exit)
+	})
+}
+
+// value of a lock in the store
+type value struct {
+	Owner string `json:"owner"`
+	Token uint64 `json:"token"`
+}
+
+func (s *storeLock) key(id string) string {
+	return path.Join(s.opts.Prefix, id)
+}
+
+func (s *storeLock) read(table, key string) ([]byte, error) {
+	recs, err := s.store.ReadWith(key, store.ReadFrom("", table))
+	if err != nil {
+		return nil, err
+	}
+	return recs[0].Value, nil
+}
+
+// swap writes the record if the key has the old value. Stores without compare
+// and swap write the record and read it back, which only detects most races.
+func (s *storeLock) swap(table string, r *store.Record, old []byte) error {
+	if s.cas != nil {
+		return s.cas.CompareAndSwap(r, old, store.WriteTo("", table))
+	}
+
+	cur, err := s.read(table, r.Key)
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	if (err == store.ErrNotFound) != (old == nil) || (old != nil && string(cur) != string(old)) {
+		return store.ErrConflict
+	}
+
+	if err := s.store.WriteWith(r, store.WriteTo("", table)); err != nil {
+		return err
+	}
+
+	cur, err = s.read(table, r.Key)
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	if string(cur) != string(r.Value) {
+		return store.ErrConflict
+	}
+
+	return nil
+}
+
+// token increments the fencing token counter of the lock
+func (s *storeLock) token(key string) (uint64, error) {
+	for {
+		old, err := s.read(DefaultTokenTable, key)
+		if err != nil && err != store.ErrNotFound {
+			return 0, err
+		}
+
+		var n uint64
+		if old != nil {
+			if n, err = strconv.ParseUint(string(old), 10, 64); err != nil {
+				return 0, err
+			}
+		}
+		n++
+
+		err = s.swap(DefaultTokenTable, &store.Record{Key: key, Value: []byte(strconv.FormatUint(n, 10))}, old)
+		if err == store.ErrConflict {
+			continue
+		}
+		return n, err
+	}
+}
+
+// try takes the lock if it isn't held
+func (s *storeLock) try(key string, ttl time.Duration) (*lease, uint64, error) {
+	if _, err := s.read(DefaultTable, key); err == nil {
+		return nil, 0, store.ErrConflict
+	} else if err != store.ErrNotFound {
+		return nil, 0, err
+	}
+
+	token, err := s.token(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	b, err := json.Marshal(&value{Owner: uuid.New().String(), Token: token})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.swap(DefaultTable, &store.Record{Key: key, Value: b, Expiry: ttl}, nil); err != nil {
+		return nil, 0, err
+	}
+
+	return &lease{
+		key:   key,
+		value: b,
+		ttl:   ttl,
+		exit:  make(chan bool),
+	}, token, nil
+}
+
+// renew the lease until released or lost
+func (s *storeLock) renew(id string, l *lease) {
+	t := time.NewTicker(l.ttl / 3)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-l.exit:
+			return
+		}
+
+		err := s.swap(DefaultTable, &store.Record{Key: l.key, Value: l.value, Expiry: l.ttl}, l.value)
+		if err == store.ErrConflict {
+			log.Logf("[lock] lost lock %s", id)
+			s.Lock()
+			if s.leases[id] == l {
+				delete(s.leases, id)
+			}
+			s.Unlock()
+			return
+		} else if err != nil {
+			log.Logf("[lock] error renewing lock %s: %v", id, err)
+		}
+	}
+}
+
+func (s *storeLock) AcquireToken(id string, opts ...lock.AcquireOption) (uint64, error) {
+	var options lock.AcquireOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// zero waits until acquired
+	var wait <-chan time.Time
+	if options.Wait > time.Duration(0) {
+		t := time.NewTimer(options.Wait)
+		defer t.Stop()
+		wait = t.C
+	}
+
+	key := s.key(id)
+
+	for {
+		l, token, err := s.try(key, options.TTL)
+		if err == nil {
+			s.Lock()
+			s.leases[id] = l
+			s.Unlock()
+
+			// a lease without a ttl never expires
+			if l.ttl > time.Duration(0) {
+				go s.renew(id, l)
+			}
+
+			return token, nil
+		}
+		if err != store.ErrConflict {
+			return 0, err
+		}
+
+		select {
+		case <-time.After(DefaultPoll):
+		case <-wait:
+			return 0, lock.ErrLockTimeout
+		}
+	}
+}
+
+func (s *storeLock) Acquire(id string, opts ...lock.AcquireOption) error {
+	_, err := s.AcquireToken(id, opts...)
+	return err
+}
+
+func (s *storeLock) Release(id string) error {
+	s.Lock()
+	l, ok := s.leases[id]
+	delete(s.leases, id)
+	s.Unlock()
+
+	// not held
+	if !ok {
+		return nil
+	}
+
+	l.stop()
+
+	if s.cas != nil {
+		err := s.cas.CompareAndDelete(l.key, l.value, store.DeleteFrom("", DefaultTable))
+		if err == store.ErrConflict {
+			return ErrLockLost
+		}
+		return err
+	}
+
+	cur, err := s.read(DefaultTable, l.key)
+	if err == store.ErrNotFound || (err == nil && string(cur) != string(l.value)) {
+		return ErrLockLost
+	} else if err != nil {
+		return err
+	}
+
+	return s.store.DeleteWith(l.key, store.DeleteFrom("", DefaultTable))
+}
+
+// NewLock returns a lock kept in the store. Locks are leases renewed while
+// held, expiring after their ttl if the holder dies. Stores implementing
+// store.CAS, such as the store service, are safe to share, others only
+// detect most concurrent acquires.
+// The lock implements lock.Fencing.
+func NewLock(s store.Store, opts ...lock.Option) lock.Lock {
+	var options lock.Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	cas, _ := s.(store.CAS)
+
+	return &storeLock{
+		opts:   options,
+		store:  s,
+		cas:    cas,
+		leases: make(map[string]*lease),
+	}
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/lock"
+)
+
+// plainStore hides compare and swap
+type plainStore struct {
+	store.Store
+}
+
+func TestAcquireRelease(t *testing.T) {
+	for name, s := range map[string]store.Store{
+		"cas":   memory.NewStore(),
+		"plain": &plainStore{memory.NewStore()},
+	} {
+		a := NewLock(s, lock.Prefix("test")).(lock.Fencing)
+		b := NewLock(s, lock.Prefix("test")).(lock.Fencing)
+
+		t1, err := a.AcquireToken("foo")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if _, err := b.AcquireToken("foo", lock.Wait(time.Millisecond*100)); err != lock.ErrLockTimeout {
+			t.Fatalf("%s: expected %v got %v", name, lock.ErrLockTimeout, err)
+		}
+
+		// another id is free
+		if err := b.Acquire("bar"); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if err := a.Release("foo"); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		t2, err := b.AcquireToken("foo", lock.Wait(time.Second))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if t2 <= t1 {
+			t.Fatalf("%s: expected token greater than %d got %d", name, t1, t2)
+		}
+
+		// the token counter of foo isn't the lock foo/token
+		if _, err := a.AcquireToken("foo/token", lock.Wait(time.Millisecond*100)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := a.Release("foo/token"); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		// releasing a lock not held does nothing
+		if err := a.Release("foo"); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := b.Release("foo"); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestWaiters(t *testing.T) {
+	s := memory.NewStore()
+
+	var mtx sync.Mutex
+	var holders, last uint64
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			l := NewLock(s).(lock.Fencing)
+			for j := 0; j < 5; j++ {
+				token, err := l.AcquireToken("foo", lock.TTL(time.Second))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				mtx.Lock()
+				holders++
+				if holders > 1 {
+					t.Error("lock held twice")
+				}
+				if token <= last {
+					t.Errorf("token %d not greater than %d", token, last)
+				}
+				last = token
+				mtx.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mtx.Lock()
+				holders--
+				mtx.Unlock()
+
+				if err := l.Release("foo"); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestLease(t *testing.T) {
+	s := memory.NewStore()
+
+	a := NewLock(s)
+	b := NewLock(s).(lock.Fencing)
+
+	ttl := time.Millisecond * 60
+
+	if err := a.Acquire("foo", lock.TTL(ttl)); err != nil {
+		t.Fatal(err)
+	}
+
+	// renewed past its ttl
+	if _, err := b.AcquireToken("foo", lock.Wait(ttl*3)); err != lock.ErrLockTimeout {
+		t.Fatalf("expected %v got %v", lock.ErrLockTimeout, err)
+	}
+
+	// the holder dies without releasing
+	sl := a.(*storeLock)
+	sl.Lock()
+	sl.leases["foo"].stop()
+	sl.Unlock()
+
+	if _, err := b.AcquireToken("foo", lock.TTL(ttl), lock.Wait(ttl*3)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the expired holder can't release the new holder's lock
+	if err := a.Release("foo"); err != ErrLockLost {
+		t.Fatalf("expected %v got %v", ErrLockLost, err)
+	}
+	if err := b.Release("foo"); err != nil {
+		t.Fatal(err)
+	}
+}
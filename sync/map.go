@@ -0,0 +1,195 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"sort"
+
+	"github.com/stack-labs/stack/codec/json"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	lmemory "github.com/stack-labs/stack/sync/lock/memory"
+)
+
+var (
+	// ErrNilKey is returned when a key is nil
+	ErrNilKey = errors.New("key is nil")
+)
+
+type syncMap struct {
+	opts Options
+}
+
+// key encodes a key into a store record key
+func (m *syncMap) key(k interface{}) (string, error) {
+	if k == nil {
+		return "", ErrNilKey
+	}
+	b, err := m.opts.Codec.Marshal(k)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
+
+// decode decodes a store record key back into a key
+func (m *syncMap) decode(k string) (interface{}, error) {
+	b, err := base64.URLEncoding.DecodeString(k)
+	if err != nil {
+		return nil, err
+	}
+	var key interface{}
+	if err := m.opts.Codec.Unmarshal(b, &key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (m *syncMap) Read(key, val interface{}) error {
+	k, err := m.key(key)
+	if err != nil {
+		return err
+	}
+
+	if err := m.opts.Lock.Acquire(k); err != nil {
+		return err
+	}
+	defer m.opts.Lock.Release(k)
+
+	records, err := m.opts.Store.Read(k)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return store.ErrNotFound
+	}
+
+	return m.opts.Codec.Unmarshal(records[0].Value, val)
+}
+
+func (m *syncMap) Write(key, val interface{}) error {
+	k, err := m.key(key)
+	if err != nil {
+		return err
+	}
+
+	b, err := m.opts.Codec.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	if err := m.opts.Lock.Acquire(k); err != nil {
+		return err
+	}
+	defer m.opts.Lock.Release(k)
+
+	return m.opts.Store.Write(&store.Record{
+		Key:   k,
+		Value: b,
+	})
+}
+
+func (m *syncMap) Delete(key interface{}) error {
+	k, err := m.key(key)
+	if err != nil {
+		return err
+	}
+
+	if err := m.opts.Lock.Acquire(k); err != nil {
+		return err
+	}
+	defer m.opts.Lock.Release(k)
+
+	return m.opts.Store.Delete(k)
+}
+
+func (m *syncMap) Iterate(fn func(key, val interface{}) error) error {
+	records, err := m.opts.Store.List()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+
+	for _, r := range records {
+		// skip records which are not written by a map
+		key, err := m.decode(r.Key)
+		if err != nil {
+			continue
+		}
+
+		if err := m.iterate(r.Key, key, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// iterate runs fn over a single key and writes back a changed value
+func (m *syncMap) iterate(k string, key interface{}, fn func(key, val interface{}) error) error {
+	if err := m.opts.Lock.Acquire(k); err != nil {
+		return err
+	}
+	defer m.opts.Lock.Release(k)
+
+	// read again under the lock
+	records, err := m.opts.Store.Read(k)
+	if err == store.ErrNotFound || (err == nil && len(records) == 0) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var val interface{}
+	if err := m.opts.Codec.Unmarshal(records[0].Value, &val); err != nil {
+		return err
+	}
+
+	if err := fn(key, val); err != nil {
+		return err
+	}
+
+	b, err := m.opts.Codec.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	// unchanged
+	if bytes.Equal(b, records[0].Value) {
+		return nil
+	}
+
+	return m.opts.Store.Write(&store.Record{
+		Key:    k,
+		Value:  b,
+		Expiry: records[0].Expiry,
+	})
+}
+
+// NewMap returns a Map built on the store and lock
+func NewMap(opts ...Option) Map {
+	var options Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.Lock == nil {
+		options.Lock = lmemory.NewLock()
+	}
+
+	if options.Store == nil {
+		options.Store = memory.NewStore()
+	}
+
+	if options.Codec == nil {
+		options.Codec = json.Marshaler{}
+	}
+
+	return &syncMap{
+		opts: options,
+	}
+}
@@ -0,0 +1,118 @@
+package sync
+
+import (
+	gosync "sync"
+	"testing"
+
+	"github.com/stack-labs/stack/store"
+)
+
+func TestMap(t *testing.T) {
+	m := NewMap()
+
+	if err := m.Write("foo", map[string]string{"bar": "baz"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var val map[string]string
+	if err := m.Read("foo", &val); err != nil {
+		t.Fatal(err)
+	}
+	if val["bar"] != "baz" {
+		t.Fatalf("expected baz got %s", val["bar"])
+	}
+
+	if err := m.Delete("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Read("foo", &val); err != store.ErrNotFound {
+		t.Fatalf("expected %v got %v", store.ErrNotFound, err)
+	}
+
+	if err := m.Read(nil, &val); err != ErrNilKey {
+		t.Fatalf("expected %v got %v", ErrNilKey, err)
+	}
+}
+
+func TestMapIterate(t *testing.T) {
+	m := NewMap()
+
+	keys := []string{"a", "b", "c"}
+	for i, k := range keys {
+		if err := m.Write(k, map[string]int{"count": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var seen []string
+	err := m.Iterate(func(key, val interface{}) error {
+		seen = append(seen, key.(string))
+		v := val.(map[string]interface{})
+		v["count"] = v["count"].(float64) + 10
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != len(keys) {
+		t.Fatalf("expected %d keys got %d", len(keys), len(seen))
+	}
+
+	for i, k := range keys {
+		var val map[string]int
+		if err := m.Read(k, &val); err != nil {
+			t.Fatal(err)
+		}
+		if val["count"] != i+10 {
+			t.Fatalf("expected %d got %d for key %s", i+10, val["count"], k)
+		}
+	}
+}
+
+func TestMapIterateSkipsForeignRecords(t *testing.T) {
+	m := NewMap()
+	s := m.(*syncMap).opts.Store
+
+	if err := s.Write(&store.Record{Key: "not base64!", Value: []byte("x")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Write(1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	if err := m.Iterate(func(key, val interface{}) error {
+		n++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 key got %d", n)
+	}
+}
+
+func TestMapConcurrentWrite(t *testing.T) {
+	m := NewMap()
+
+	var wg gosync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := m.Write("counter", i); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var val int
+	if err := m.Read("counter", &val); err != nil {
+		t.Fatal(err)
+	}
+	if val < 0 || val > 9 {
+		t.Fatalf("unexpected value %d", val)
+	}
+}
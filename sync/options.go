@@ -1,12 +1,20 @@
 package sync
 
 import (
+	"github.com/stack-labs/stack/codec"
 	"github.com/stack-labs/stack/store"
 	"github.com/stack-labs/stack/sync/leader"
 	"github.com/stack-labs/stack/sync/lock"
 	"github.com/stack-labs/stack/sync/time"
 )
 
+// WithCodec sets the codec used to encode map keys and values
+func WithCodec(c codec.Marshaler) Option {
+	return func(o *Options) {
+		o.Codec = c
+	}
+}
+
 // WithLeader sets the leader election implementation opton
 func WithLeader(l leader.Leader) Option {
 	return func(o *Options) {
@@ -34,3 +42,32 @@ func WithTime(t time.Time) Option {
 		o.Time = t
 	}
 }
+
+// WithNode sets the node id recorded in the cron runs
+func WithNode(id string) Option {
+	return func(o *Options) {
+		o.Node = id
+	}
+}
+
+// WithHistory sets the number of runs kept per cron job
+func WithHistory(n int) Option {
+	return func(o *Options) {
+		o.History = n
+	}
+}
+
+// CatchUp runs a job once on being scheduled or elected
+// if a run was missed since the last one
+func CatchUp() ScheduleOption {
+	return func(o *ScheduleOptions) {
+		o.CatchUp = true
+	}
+}
+
+// NoOverlap skips a run while the previous one is running
+func NoOverlap() ScheduleOption {
+	return func(o *ScheduleOptions) {
+		o.NoOverlap = true
+	}
+}
@@ -2,6 +2,7 @@
 package sync
 
 import (
+	"github.com/stack-labs/stack/codec"
 	"github.com/stack-labs/stack/store"
 	"github.com/stack-labs/stack/sync/leader"
 	"github.com/stack-labs/stack/sync/lock"
@@ -25,17 +26,43 @@ type Map interface {
 
 // Cron is a distributed scheduler using leader election
 // and distributed task runners. It uses the leader and
-// task interfaces.
+// task interfaces. Runs are kept in the store if one is set.
 type Cron interface {
-	Schedule(task.Schedule, task.Command) error
+	// Schedule a command, the job is identified by the schedule and command name
+	Schedule(task.Schedule, task.Command, ...ScheduleOption) error
+	// List the jobs scheduled
+	List() ([]*Job, error)
+	// Pause running a job until resumed
+	Pause(id string) error
+	// Resume running a paused job
+	Resume(id string) error
+	// Unschedule a job
+	Unschedule(id string) error
+	// History of the runs of a job, most recent first
+	History(id string) ([]*Run, error)
 }
 
 type Options struct {
+	Codec  codec.Marshaler
 	Leader leader.Leader
 	Lock   lock.Lock
 	Store  store.Store
 	Task   task.Task
 	Time   time.Time
+	// Node id recorded in the cron runs
+	Node string
+	// History is the number of runs kept per cron job
+	History int
 }
 
 type Option func(o *Options)
+
+type ScheduleOptions struct {
+	// CatchUp runs a job once on being scheduled or elected
+	// if a run was missed since the last one
+	CatchUp bool
+	// NoOverlap skips a run while the previous one is running
+	NoOverlap bool
+}
+
+type ScheduleOption func(o *ScheduleOptions)
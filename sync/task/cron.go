@@ -0,0 +1,209 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronExpr is a parsed cron expression
+type CronExpr struct {
+	second, minute, hour, dom, month, dow uint64
+	// day of month or week is a wildcard
+	domStar, dowStar bool
+}
+
+type field struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	seconds = field{0, 59, nil}
+	minutes = field{0, 59, nil}
+	hours   = field{0, 23, nil}
+	doms    = field{1, 31, nil}
+	months  = field{1, 12, map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is sunday as well as 0
+	dows = field{0, 7, map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	descriptors = map[string]string{
+		"@yearly":   "0 0 0 1 1 *",
+		"@annually": "0 0 0 1 1 *",
+		"@monthly":  "0 0 0 1 * *",
+		"@weekly":   "0 0 0 * * 0",
+		"@daily":    "0 0 0 * * *",
+		"@midnight": "0 0 0 * * *",
+		"@hourly":   "0 0 * * * *",
+	}
+)
+
+func (f field) value(s string) (uint, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if uint(v) < f.min || uint(v) > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, f.min, f.max)
+	}
+	return uint(v), nil
+}
+
+// parse a comma separated list of values, ranges and steps into a bitset
+func (f field) parse(s string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(s, ",") {
+		step := uint(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			v, err := strconv.ParseUint(part[i+1:], 10, 32)
+			if err != nil || v == 0 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			step = uint(v)
+			part = part[:i]
+		}
+
+		var lo, hi uint
+		var err error
+
+		switch {
+		case part == "*" || part == "?":
+			lo, hi = f.min, f.max
+		case strings.Contains(part, "-"):
+			r := strings.SplitN(part, "-", 2)
+			if lo, err = f.value(r[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(r[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			if lo, err = f.value(part); err != nil {
+				return 0, err
+			}
+			hi = lo
+			// a step from a value runs to the max
+			if step > 1 {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+// ParseCron parses a standard 5 field cron expression of minute, hour,
+// day of month, month and day of week, or 6 fields starting with seconds.
+// Fields are values, names of months and days, ranges, steps and lists.
+// The descriptors @yearly, @monthly, @weekly, @daily and @hourly are supported.
+func ParseCron(expr string) (*CronExpr, error) {
+	expr = strings.TrimSpace(expr)
+	if d, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron expression %q has %d fields, expected 5 or 6", expr, len(fields))
+	}
+
+	c := &CronExpr{
+		domStar: fields[3] == "*" || fields[3] == "?",
+		dowStar: fields[5] == "*" || fields[5] == "?",
+	}
+
+	for i, f := range []struct {
+		field field
+		bits  *uint64
+	}{
+		{seconds, &c.second},
+		{minutes, &c.minute},
+		{hours, &c.hour},
+		{doms, &c.dom},
+		{months, &c.month},
+		{dows, &c.dow},
+	} {
+		bits, err := f.field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %v", expr, err)
+		}
+		*f.bits = bits
+	}
+
+	// sunday is 0
+	if c.dow&(1<<7) > 0 {
+		c.dow |= 1
+	}
+
+	return c, nil
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) > 0
+}
+
+// dayMatches applies the cron rule that when both the day of month and
+// week are restricted a day matching either runs
+func (c *CronExpr) dayMatches(t time.Time) bool {
+	dom := has(c.dom, t.Day())
+	dow := has(c.dow, int(t.Weekday()))
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first time after t the expression matches, in the
+// location of t. The zero time is returned if there's none in five years.
+func (c *CronExpr) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Add(time.Second - time.Duration(t.Nanosecond())).Truncate(time.Second)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !has(c.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !has(c.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !has(c.minute, t.Minute()) {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		if !has(c.second, t.Second()) {
+			t = t.Add(time.Second)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,85 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	from := time.Date(2021, 3, 15, 10, 30, 15, 500, time.UTC) // monday
+
+	testData := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2021, 3, 15, 10, 31, 0, 0, time.UTC)},
+		{"* * * * * *", time.Date(2021, 3, 15, 10, 30, 16, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2021, 3, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2021, 3, 15, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * *", time.Date(2021, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2021, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2021, 3, 15, 11, 0, 0, 0, time.UTC)},
+		{"0 12 * * sat,sun", time.Date(2021, 3, 20, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2021, 3, 21, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// day of month or week when both are set
+		{"0 0 20 * mon", time.Date(2021, 3, 20, 0, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2021, 3, 16, 10, 30, 0, 0, time.UTC)},
+	}
+
+	for _, d := range testData {
+		c, err := ParseCron(d.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", d.expr, err)
+		}
+		if next := c.Next(from); !next.Equal(d.next) {
+			t.Fatalf("%s: expected %s got %s", d.expr, d.next, next)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Fatalf("expected error parsing %q", expr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	from := time.Date(2021, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	s := Schedule{Cron: "0 9 * * *", Location: ny}
+	next := s.Next(from)
+	if expected := time.Date(2021, 3, 15, 9, 0, 0, 0, ny); !next.Equal(expected) {
+		t.Fatalf("expected %s got %s", expected, next)
+	}
+
+	// runs start after the start time
+	s = Schedule{Cron: "0 * * * *", Time: from.Add(time.Hour * 5)}
+	if next := s.Next(from); !next.Equal(from.Add(time.Hour * 5)) {
+		t.Fatalf("expected %s got %s", from.Add(time.Hour*5), next)
+	}
+
+	s = Schedule{Time: from, Interval: time.Minute}
+	if next := s.Next(from.Add(time.Second * 90)); !next.Equal(from.Add(time.Minute * 2)) {
+		t.Fatalf("expected %s got %s", from.Add(time.Minute*2), next)
+	}
+
+	s = Schedule{Time: from}
+	if next := s.Next(from.Add(-time.Second)); !next.Equal(from) {
+		t.Fatalf("expected %s got %s", from, next)
+	}
+	if next := s.Next(from); !next.IsZero() {
+		t.Fatalf("expected no next run got %s", next)
+	}
+
+	if err := (Schedule{Cron: "bad"}).Validate(); err == nil {
+		t.Fatal("expected invalid cron expression")
+	}
+}
@@ -27,7 +27,7 @@ func (l *localTask) Run(t task.Command) error {
 
 	for i := 0; i < l.opts.Pool; i++ {
 		er := <-ch
-		if err != nil {
+		if er != nil {
 			err = er
 			l.mtx.Lock()
 			l.status = fmt.Sprintf("command [%s] status: %s", t.Name, err.Error())
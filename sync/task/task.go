@@ -21,12 +21,17 @@ type Command struct {
 	Func func() error
 }
 
-// Schedule represents a time or interval at which a task should run
+// Schedule represents a time, interval or cron expression at which a task should run
 type Schedule struct {
 	// When to start the schedule. Zero time means immediately
 	Time time.Time
 	// Non zero interval dictates an ongoing schedule
 	Interval time.Duration
+	// Cron expression of 5 or 6 fields, see ParseCron. It takes
+	// precedence over the interval, runs start after Time.
+	Cron string
+	// Location the cron expression is in, local time if not set
+	Location *time.Location
 }
 
 type Options struct {
@@ -46,11 +51,67 @@ func (c Command) String() string {
 	return c.Name
 }
 
-func (s Schedule) Run() <-chan time.Time {
-	d := s.Time.Sub(time.Now())
+// Validate the cron expression of the schedule
+func (s Schedule) Validate() error {
+	if len(s.Cron) == 0 {
+		return nil
+	}
+	_, err := ParseCron(s.Cron)
+	return err
+}
+
+// Next returns the first time after t the schedule runs,
+// or the zero time if it doesn't run again
+func (s Schedule) Next(t time.Time) time.Time {
+	if len(s.Cron) > 0 {
+		c, err := ParseCron(s.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		if t.Before(s.Time) {
+			t = s.Time.Add(-time.Nanosecond)
+		}
+		loc := s.Location
+		if loc == nil {
+			loc = time.Local
+		}
+		return c.Next(t.In(loc))
+	}
+
+	// zero interval runs once at the start time
+	if s.Interval == time.Duration(0) {
+		if t.Before(s.Time) {
+			return s.Time
+		}
+		return time.Time{}
+	}
 
+	// intervals tick from the start time
+	if t.Before(s.Time) {
+		return s.Time.Add(s.Interval)
+	}
+	n := t.Sub(s.Time)/s.Interval + 1
+	return s.Time.Add(n * s.Interval)
+}
+
+func (s Schedule) Run() <-chan time.Time {
 	ch := make(chan time.Time, 1)
 
+	if len(s.Cron) > 0 {
+		go func() {
+			defer close(ch)
+
+			for next := s.Next(time.Now()); !next.IsZero(); next = s.Next(next) {
+				<-time.After(time.Until(next))
+				ch <- next
+			}
+		}()
+
+		return ch
+	}
+
+	d := s.Time.Sub(time.Now())
+
 	go func() {
 		// wait for start time
 		<-time.After(d)
@@ -74,6 +135,13 @@ func (s Schedule) Run() <-chan time.Time {
 }
 
 func (s Schedule) String() string {
+	if len(s.Cron) > 0 {
+		loc := "Local"
+		if s.Location != nil {
+			loc = s.Location.String()
+		}
+		return fmt.Sprintf("%d-%s-%s", s.Time.Unix(), s.Cron, loc)
+	}
 	return fmt.Sprintf("%d-%d", s.Time.Unix(), s.Interval)
 }
 
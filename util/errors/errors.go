@@ -3,6 +3,7 @@
 package errors
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -112,6 +113,16 @@ func Conflict(id, format string, a ...interface{}) error {
 	}
 }
 
+// TooManyRequests generates a 429 error.
+func TooManyRequests(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   429,
+		Detail: fmt.Sprintf(format, a...),
+		Status: http.StatusText(429),
+	}
+}
+
 // InternalServerError generates a 500 error.
 func InternalServerError(id, format string, a ...interface{}) error {
 	return &Error{
@@ -121,3 +132,28 @@ func InternalServerError(id, format string, a ...interface{}) error {
 		Status: http.StatusText(500),
 	}
 }
+
+// ServiceUnavailable generates a 503 error.
+func ServiceUnavailable(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   503,
+		Detail: fmt.Sprintf(format, a...),
+		Status: http.StatusText(503),
+	}
+}
+
+// IsFailure reports whether the error is a failure of the service called
+// rather than of the request: errors of the transport, the server, timed
+// out or cancelled calls. Errors returned for a bad request, e.g. a 404 or
+// 403, are not.
+func IsFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if err == context.DeadlineExceeded || err == context.Canceled {
+		return true
+	}
+	e := Parse(err.Error())
+	return e.Code == 0 || e.Code == 408 || e.Code >= 500
+}
@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"testing"
 )
@@ -45,3 +47,27 @@ func TestErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestIsFailure(t *testing.T) {
+	testData := []struct {
+		err     error
+		failure bool
+	}{
+		{nil, false},
+		{fmt.Errorf("connection refused"), true},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, true},
+		{Timeout("test", "context deadline exceeded"), true},
+		{InternalServerError("test", "internal error"), true},
+		{ServiceUnavailable("test", "unavailable"), true},
+		{NotFound("test", "not found"), false},
+		{Forbidden("test", "forbidden"), false},
+		{TooManyRequests("test", "slow down"), false},
+	}
+
+	for _, d := range testData {
+		if f := IsFailure(d.err); f != d.failure {
+			t.Fatalf("Expected %v to be a failure %v got %v", d.err, d.failure, f)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/stack-labs/stack/server"
+)
+
+type Options struct {
+	// Table of the outbox
+	Table string
+	// Interval between relaying the outbox
+	Interval time.Duration
+	// Backoff after failing to publish, attempts start at 1
+	Backoff func(attempts int) time.Duration
+	// BatchSize of messages read from the outbox at once
+	BatchSize uint
+	// SeenTable of the idempotency keys handled by subscribers
+	SeenTable string
+	// SeenTTL is how long an idempotency key is remembered
+	SeenTTL time.Duration
+}
+
+type Option func(o *Options)
+
+// WithTable sets the table of the outbox
+func WithTable(t string) Option {
+	return func(o *Options) {
+		o.Table = t
+	}
+}
+
+// WithInterval sets the interval between relaying the outbox
+func WithInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = d
+	}
+}
+
+// WithBackoff sets the backoff after failing to publish
+func WithBackoff(fn func(attempts int) time.Duration) Option {
+	return func(o *Options) {
+		o.Backoff = fn
+	}
+}
+
+// WithBatchSize sets the number of messages read from the outbox at once
+func WithBatchSize(n uint) Option {
+	return func(o *Options) {
+		o.BatchSize = n
+	}
+}
+
+// WithSeenTable sets the table of the idempotency keys handled by subscribers.
+// Services subscribing to the same topic should each use their own table.
+func WithSeenTable(t string) Option {
+	return func(o *Options) {
+		o.SeenTable = t
+	}
+}
+
+// WithSeenTTL sets how long an idempotency key is remembered,
+// it should be longer than a message may be redelivered for
+func WithSeenTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.SeenTTL = d
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Table:     DefaultTable,
+		Interval:  DefaultInterval,
+		Backoff:   server.ExponentialBackoff,
+		BatchSize: DefaultBatchSize,
+		SeenTable: DefaultSeenTable,
+		SeenTTL:   DefaultSeenTTL,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return options
+}
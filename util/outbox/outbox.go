@@ -0,0 +1,322 @@
+// Package outbox provides transactional publishing through a store. Messages
+// are written to an outbox table and relayed to the broker in the background,
+// so a message written alongside other records isn't lost if the process
+// dies before publishing it.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/codec"
+	raw "github.com/stack-labs/stack/codec/bytes"
+	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/util/buf"
+	codecu "github.com/stack-labs/stack/util/codec"
+	"github.com/stack-labs/stack/util/log"
+)
+
+const (
+	// IdempotencyKeyHeader identifies a message across redeliveries
+	IdempotencyKeyHeader = "Stack-Idempotency-Key"
+)
+
+var (
+	// DefaultTable of the outbox
+	DefaultTable = "outbox"
+	// DefaultInterval between relaying the outbox
+	DefaultInterval = time.Second
+	// DefaultBatchSize of messages read from the outbox at once
+	DefaultBatchSize uint = 100
+	// DefaultSeenTable of the idempotency keys handled by subscribers
+	DefaultSeenTable = "outbox_seen"
+	// DefaultSeenTTL is how long an idempotency key is remembered
+	DefaultSeenTTL = time.Hour * 24
+)
+
+// Publisher publishes to a topic through the outbox
+type Publisher interface {
+	Publish(ctx context.Context, msg interface{}, opts ...client.PublishOption) error
+}
+
+// Outbox keeps messages in a store until they're published
+type Outbox struct {
+	opts   Options
+	client client.Client
+	store  store.Store
+
+	notify chan struct{}
+	// held relaying so messages are published once in order
+	relaying sync.Mutex
+
+	sync.Mutex
+	running bool
+	exit    chan struct{}
+	done    chan struct{}
+	// failed publish attempts and when to retry
+	attempts int
+	retry    time.Time
+}
+
+// entry is a message as kept in the outbox
+type entry struct {
+	Topic       string            `json:"topic"`
+	ContentType string            `json:"content_type"`
+	Exchange    string            `json:"exchange,omitempty"`
+	Header      map[string]string `json:"header"`
+	Body        []byte            `json:"body"`
+}
+
+type message struct {
+	topic       string
+	contentType string
+	payload     interface{}
+}
+
+type publisher struct {
+	o     *Outbox
+	topic string
+}
+
+func (m *message) Topic() string {
+	return m.topic
+}
+
+func (m *message) ContentType() string {
+	return m.contentType
+}
+
+func (m *message) Payload() interface{} {
+	return m.payload
+}
+
+func (p *publisher) Publish(ctx context.Context, msg interface{}, opts ...client.PublishOption) error {
+	return p.o.Publish(ctx, p.o.client.NewMessage(p.topic, msg), opts...)
+}
+
+func encode(msg client.Message) ([]byte, error) {
+	if d, ok := msg.Payload().(*raw.Frame); ok {
+		return d.Data, nil
+	}
+
+	cf, ok := codecu.DefaultCodecs[msg.ContentType()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported content type %s", msg.ContentType())
+	}
+
+	b := buf.New(nil)
+	if err := cf(b).Write(&codec.Message{
+		Target: msg.Topic(),
+		Type:   codec.Event,
+	}, msg.Payload()); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// Publish encodes the message and writes it to the outbox. The message
+// is published once relayed, with the idempotency key in its header.
+// A key already set in the context metadata is kept.
+func (o *Outbox) Publish(ctx context.Context, msg client.Message, opts ...client.PublishOption) error {
+	var options client.PublishOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	body, err := encode(msg)
+	if err != nil {
+		return err
+	}
+
+	header := make(map[string]string)
+	if md, ok := metadata.FromContext(ctx); ok {
+		for k, v := range md {
+			header[k] = v
+		}
+	}
+
+	key, ok := header[IdempotencyKeyHeader]
+	if !ok || len(key) == 0 {
+		key = uuid.New().String()
+		header[IdempotencyKeyHeader] = key
+	}
+
+	b, err := json.Marshal(&entry{
+		Topic:       msg.Topic(),
+		ContentType: msg.ContentType(),
+		Exchange:    options.Exchange,
+		Header:      header,
+		Body:        body,
+	})
+	if err != nil {
+		return err
+	}
+
+	// keys sort in the order messages are written
+	if err := o.store.WriteWith(&store.Record{
+		Key:   fmt.Sprintf("%020d-%s", time.Now().UnixNano(), key),
+		Value: b,
+	}, store.WriteTo("", o.opts.Table)); err != nil {
+		return err
+	}
+
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// NewPublisher returns a publisher of the topic through the outbox
+func (o *Outbox) NewPublisher(topic string) Publisher {
+	return &publisher{o, topic}
+}
+
+// Relay publishes the messages in the outbox in the order they were
+// written, deleting them once published. It stops at the first message
+// failing to publish, which is retried on the next relay.
+func (o *Outbox) Relay() error {
+	o.relaying.Lock()
+	defer o.relaying.Unlock()
+
+	records, err := o.store.List(
+		store.ListFrom("", o.opts.Table),
+		store.ListLimit(o.opts.BatchSize),
+	)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+
+	for _, r := range records {
+		var e entry
+		if err := json.Unmarshal(r.Value, &e); err != nil {
+			// it can never be published
+			log.Logf("[outbox] dropping corrupt message %s: %v", r.Key, err)
+			o.store.DeleteWith(r.Key, store.DeleteFrom("", o.opts.Table))
+			continue
+		}
+
+		var opts []client.PublishOption
+		if len(e.Exchange) > 0 {
+			opts = append(opts, client.WithExchange(e.Exchange))
+		}
+
+		ctx := metadata.NewContext(context.Background(), e.Header)
+		msg := &message{
+			topic:       e.Topic,
+			contentType: e.ContentType,
+			payload:     &raw.Frame{Data: e.Body},
+		}
+
+		if err := o.client.Publish(ctx, msg, opts...); err != nil {
+			return err
+		}
+
+		// a failed delete publishes the message again,
+		// subscribers drop it by its idempotency key
+		if err := o.store.DeleteWith(r.Key, store.DeleteFrom("", o.opts.Table)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Outbox) relay() {
+	o.Lock()
+	if time.Now().Before(o.retry) {
+		o.Unlock()
+		return
+	}
+	o.Unlock()
+
+	err := o.Relay()
+
+	o.Lock()
+	defer o.Unlock()
+
+	if err == nil {
+		o.attempts = 0
+		return
+	}
+
+	o.attempts++
+	o.retry = time.Now().Add(o.opts.Backoff(o.attempts))
+	log.Logf("[outbox] relay failed %d times, retrying at %s: %v", o.attempts, o.retry.Format(time.RFC3339), err)
+}
+
+func (o *Outbox) run(exit, done chan struct{}) {
+	defer close(done)
+
+	t := time.NewTicker(o.opts.Interval)
+	defer t.Stop()
+
+	for {
+		o.relay()
+
+		select {
+		case <-t.C:
+		case <-o.notify:
+		case <-exit:
+			return
+		}
+	}
+}
+
+// Start relaying the outbox in the background
+func (o *Outbox) Start() error {
+	o.Lock()
+	defer o.Unlock()
+
+	if o.running {
+		return nil
+	}
+
+	o.running = true
+	o.exit = make(chan struct{})
+	o.done = make(chan struct{})
+
+	go o.run(o.exit, o.done)
+
+	return nil
+}
+
+// Stop relaying the outbox. Messages not yet published
+// are kept and relayed once started again.
+func (o *Outbox) Stop() error {
+	o.Lock()
+	if !o.running {
+		o.Unlock()
+		return nil
+	}
+	o.running = false
+	close(o.exit)
+	done := o.done
+	o.Unlock()
+
+	<-done
+	return nil
+}
+
+// NewOutbox returns an outbox kept in the store, relaying messages with the client
+func NewOutbox(c client.Client, s store.Store, opts ...Option) *Outbox {
+	return &Outbox{
+		opts:   newOptions(opts...),
+		client: c,
+		store:  s,
+		notify: make(chan struct{}, 1),
+	}
+}
@@ -0,0 +1,183 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/broker/memory"
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/mucp"
+	"github.com/stack-labs/stack/codec"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/store"
+	smemory "github.com/stack-labs/stack/store/memory"
+)
+
+// testBroker fails publishing while down
+type testBroker struct {
+	broker.Broker
+
+	sync.Mutex
+	down bool
+}
+
+func (t *testBroker) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	t.Lock()
+	down := t.down
+	t.Unlock()
+	if down {
+		return errors.New("broker down")
+	}
+	return t.Broker.Publish(topic, msg, opts...)
+}
+
+func (t *testBroker) setDown(b bool) {
+	t.Lock()
+	t.down = b
+	t.Unlock()
+}
+
+type testMessage struct {
+	topic  string
+	header map[string]string
+}
+
+func (t *testMessage) Topic() string             { return t.topic }
+func (t *testMessage) Payload() interface{}      { return nil }
+func (t *testMessage) ContentType() string       { return "application/json" }
+func (t *testMessage) Header() map[string]string { return t.header }
+func (t *testMessage) Body() []byte              { return nil }
+func (t *testMessage) Codec() codec.Reader       { return nil }
+
+func TestOutbox(t *testing.T) {
+	b := &testBroker{Broker: memory.NewBroker(), down: true}
+	if err := b.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan *broker.Message, 10)
+	if _, err := b.Subscribe("orders", func(e broker.Event) error {
+		received <- e.Message()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := mucp.NewClient(client.Broker(b), client.ContentType("application/json"))
+	s := smemory.NewStore()
+
+	o := NewOutbox(c, s,
+		WithInterval(time.Millisecond*10),
+		WithBackoff(func(int) time.Duration { return time.Millisecond * 10 }),
+	)
+
+	p := o.NewPublisher("orders")
+	for i := 0; i < 3; i++ {
+		if err := p.Publish(context.TODO(), map[string]int{"id": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// nothing is lost while the broker is down
+	if err := o.Relay(); err == nil {
+		t.Fatal("expected relay error")
+	}
+	records, err := s.List(store.ListFrom("", DefaultTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 messages in the outbox got %d", len(records))
+	}
+
+	if err := o.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer o.Stop()
+
+	time.Sleep(time.Millisecond * 30)
+	b.setDown(false)
+
+	keys := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		select {
+		case msg := <-received:
+			var body map[string]int
+			if err := json.Unmarshal(msg.Body, &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["id"] != i {
+				t.Fatalf("expected message %d got %d", i, body["id"])
+			}
+			key := msg.Header[IdempotencyKeyHeader]
+			if len(key) == 0 || keys[key] {
+				t.Fatalf("unexpected idempotency key %q", key)
+			}
+			keys[key] = true
+		case <-time.After(time.Second):
+			t.Fatalf("message %d not relayed", i)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 30)
+	records, err = s.List(store.ListFrom("", DefaultTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("expected empty outbox got %d", len(records))
+	}
+}
+
+func TestSubscriberWrapper(t *testing.T) {
+	s := smemory.NewStore()
+
+	var handled int
+	fn := NewSubscriberWrapper(s)(func(ctx context.Context, msg server.Message) error {
+		handled++
+		if msg.Header()["Fail"] == "true" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	msg := &testMessage{topic: "orders", header: map[string]string{IdempotencyKeyHeader: "1"}}
+
+	if err := fn(context.TODO(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := fn(context.TODO(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if handled != 1 {
+		t.Fatalf("expected the duplicate dropped, handled %d", handled)
+	}
+
+	// the same key of another topic is handled
+	if err := fn(context.TODO(), &testMessage{topic: "other", header: msg.header}); err != nil {
+		t.Fatal(err)
+	}
+	if handled != 2 {
+		t.Fatalf("expected 2 handled got %d", handled)
+	}
+
+	// failed messages are handled again
+	failed := &testMessage{topic: "orders", header: map[string]string{IdempotencyKeyHeader: "2", "Fail": "true"}}
+	fn(context.TODO(), failed)
+	fn(context.TODO(), failed)
+	if handled != 4 {
+		t.Fatalf("expected 4 handled got %d", handled)
+	}
+
+	// messages without a key are always handled
+	fn(context.TODO(), &testMessage{topic: "orders", header: map[string]string{}})
+	fn(context.TODO(), &testMessage{topic: "orders", header: map[string]string{}})
+	if handled != 6 {
+		t.Fatalf("expected 6 handled got %d", handled)
+	}
+}
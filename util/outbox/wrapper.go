@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/util/log"
+)
+
+// keyLock serialises the handling of messages with the same key
+type keyLock struct {
+	sync.Mutex
+	keys map[string]*keyRef
+}
+
+type keyRef struct {
+	sync.Mutex
+	refs int
+}
+
+func (k *keyLock) lock(key string) {
+	k.Lock()
+	ref, ok := k.keys[key]
+	if !ok {
+		ref = new(keyRef)
+		k.keys[key] = ref
+	}
+	ref.refs++
+	k.Unlock()
+
+	ref.Lock()
+}
+
+func (k *keyLock) unlock(key string) {
+	k.Lock()
+	ref := k.keys[key]
+	ref.refs--
+	if ref.refs == 0 {
+		delete(k.keys, key)
+	}
+	k.Unlock()
+
+	ref.Unlock()
+}
+
+func idempotencyKey(ctx context.Context, msg server.Message) string {
+	if key := msg.Header()[IdempotencyKeyHeader]; len(key) > 0 {
+		return key
+	}
+	key, _ := metadata.Get(ctx, IdempotencyKeyHeader)
+	return key
+}
+
+// NewSubscriberWrapper drops messages whose idempotency key was already
+// handled. A key is remembered in the seen table once its message is
+// handled without error. Messages without a key are always handled.
+func NewSubscriberWrapper(s store.Store, opts ...Option) server.SubscriberWrapper {
+	options := newOptions(opts...)
+
+	locks := &keyLock{
+		keys: make(map[string]*keyRef),
+	}
+
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) error {
+			key := idempotencyKey(ctx, msg)
+			if len(key) == 0 {
+				return fn(ctx, msg)
+			}
+
+			id := msg.Topic() + "/" + key
+
+			locks.lock(id)
+			defer locks.unlock(id)
+
+			_, err := s.ReadWith(id, store.ReadFrom("", options.SeenTable))
+			if err == nil {
+				log.Logf("[outbox] dropping duplicate message %s of %s", key, msg.Topic())
+				return nil
+			} else if err != store.ErrNotFound {
+				return err
+			}
+
+			if err := fn(ctx, msg); err != nil {
+				return err
+			}
+
+			return s.WriteWith(&store.Record{
+				Key:    id,
+				Expiry: options.SeenTTL,
+			}, store.WriteTo("", options.SeenTable))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"github.com/stack-labs/stack/config"
+)
+
+type Options struct {
+	// Limiter the tokens are taken from
+	Limiter Limiter
+	// Limits used when no config is set
+	Limits Limits
+	// Config the limits are read from and watched for changes
+	Config config.Config
+	// Path of the limits in the config
+	Path string
+}
+
+type Option func(o *Options)
+
+// WithLimiter sets the limiter, e.g. NewStoreLimiter to share the buckets
+func WithLimiter(l Limiter) Option {
+	return func(o *Options) {
+		o.Limiter = l
+	}
+}
+
+// WithLimits sets static limits
+func WithLimits(l Limits) Option {
+	return func(o *Options) {
+		o.Limits = l
+	}
+}
+
+// WithConfig reads the limits from the config at path and reloads them on change.
+// The server limits are read from path.server and the client limits from path.client.
+func WithConfig(c config.Config, path string) Option {
+	return func(o *Options) {
+		o.Config = c
+		o.Path = path
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Limiter: NewLimiter(),
+		Path:    DefaultPath,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return options
+}
@@ -0,0 +1,85 @@
+// Package ratelimit provides token bucket rate limiting of client calls and server handlers
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket refilled at Rate tokens per second
+// which holds at most Burst tokens. A zero rate is unlimited.
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Limits by service or service/endpoint name
+type Limits map[string]Limit
+
+// Limiter takes tokens from named buckets
+type Limiter interface {
+	// Allow takes a token from the named bucket reporting
+	// false when the bucket is empty
+	Allow(name string, limit Limit) (bool, error)
+}
+
+type bucket struct {
+	Tokens float64 `json:"tokens"`
+	// Last refill as unix nano time
+	Last int64 `json:"last"`
+}
+
+type memoryLimiter struct {
+	sync.Mutex
+	buckets map[string]*bucket
+}
+
+func (l Limit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return math.Max(1, math.Ceil(l.Rate))
+}
+
+// take refills the bucket since the last call and takes a token from it
+func (b *bucket) take(limit Limit, now time.Time) bool {
+	burst := limit.burst()
+
+	if b.Last == 0 {
+		b.Tokens = burst
+	} else if elapsed := now.Sub(time.Unix(0, b.Last)); elapsed > 0 {
+		b.Tokens = math.Min(burst, b.Tokens+elapsed.Seconds()*limit.Rate)
+	}
+	b.Last = now.UnixNano()
+
+	if b.Tokens < 1 {
+		return false
+	}
+	b.Tokens--
+	return true
+}
+
+func (m *memoryLimiter) Allow(name string, limit Limit) (bool, error) {
+	if limit.Rate <= 0 {
+		return true, nil
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	b, ok := m.buckets[name]
+	if !ok {
+		b = new(bucket)
+		m.buckets[name] = b
+	}
+
+	return b.take(limit, time.Now()), nil
+}
+
+// NewLimiter returns a Limiter keeping the buckets in memory
+func NewLimiter() Limiter {
+	return &memoryLimiter{
+		buckets: make(map[string]*bucket),
+	}
+}
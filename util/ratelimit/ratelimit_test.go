@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/codec"
+	"github.com/stack-labs/stack/config"
+	"github.com/stack-labs/stack/pkg/config/source"
+	"github.com/stack-labs/stack/pkg/config/source/memory"
+	"github.com/stack-labs/stack/server"
+	smemory "github.com/stack-labs/stack/store/memory"
+	lmemory "github.com/stack-labs/stack/sync/lock/memory"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+type testRequest struct {
+	service  string
+	endpoint string
+}
+
+func (r *testRequest) Service() string           { return r.service }
+func (r *testRequest) Method() string            { return r.endpoint }
+func (r *testRequest) Endpoint() string          { return r.endpoint }
+func (r *testRequest) ContentType() string       { return "" }
+func (r *testRequest) Header() map[string]string { return nil }
+func (r *testRequest) Body() interface{}         { return nil }
+func (r *testRequest) Read() ([]byte, error)     { return nil, nil }
+func (r *testRequest) Codec() codec.Reader       { return nil }
+func (r *testRequest) Stream() bool              { return false }
+
+func testLimiter(t *testing.T, l Limiter) {
+	limit := Limit{Rate: 20, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		if ok, err := l.Allow("foo", limit); err != nil || !ok {
+			t.Fatalf("Expected token %d to be allowed got %v %v", i, ok, err)
+		}
+	}
+
+	if ok, _ := l.Allow("foo", limit); ok {
+		t.Fatal("Expected empty bucket to reject")
+	}
+
+	// buckets are independent
+	if ok, _ := l.Allow("bar", limit); !ok {
+		t.Fatal("Expected bar to be allowed")
+	}
+
+	// refilled at 20 tokens a second
+	time.Sleep(60 * time.Millisecond)
+
+	if ok, _ := l.Allow("foo", limit); !ok {
+		t.Fatal("Expected refilled bucket to allow")
+	}
+
+	// no rate is unlimited
+	for i := 0; i < 10; i++ {
+		if ok, _ := l.Allow("baz", Limit{}); !ok {
+			t.Fatal("Expected unlimited bucket to allow")
+		}
+	}
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	testLimiter(t, NewLimiter())
+}
+
+func TestStoreLimiter(t *testing.T) {
+	testLimiter(t, NewStoreLimiter(smemory.NewStore(), lmemory.NewLock()))
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	src := memory.NewSource(memory.WithJSON([]byte(`{"stack":{"ratelimit":{"server":{"foo/Foo.Bar":{"rate":0.001,"burst":1}}}}}`)))
+
+	c := config.NewConfig(config.Source(src))
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	h := NewHandlerWrapper(WithConfig(c, DefaultPath))(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})
+
+	call := func(endpoint string) error {
+		return h(context.Background(), &testRequest{service: "foo", endpoint: endpoint}, nil)
+	}
+
+	if err := call("Foo.Bar"); err != nil {
+		t.Fatal(err)
+	}
+	if err := call("Foo.Bar"); err == nil || errors.Parse(err.Error()).Code != 429 {
+		t.Fatalf("Expected 429 got %v", err)
+	}
+	if err := call("Foo.Baz"); err != nil {
+		t.Fatalf("Expected endpoint without limit to be allowed got %v", err)
+	}
+
+	// let the config start watching the source, then lift the limit
+	time.Sleep(100 * time.Millisecond)
+	src.(interface{ Update(*source.ChangeSet) }).Update(&source.ChangeSet{
+		Data:   []byte(`{"stack":{"ratelimit":{"server":{"foo/Foo.Bar":{"rate":1000,"burst":1000}}}}}`),
+		Format: "json",
+	})
+
+	for i := 0; i < 100; i++ {
+		if call("Foo.Bar") == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("Expected limit to be reloaded")
+}
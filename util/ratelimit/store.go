@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/sync/lock"
+)
+
+var (
+	// DefaultPrefix of the bucket keys in the store
+	DefaultPrefix = "ratelimit/"
+)
+
+type storeLimiter struct {
+	store store.Store
+	lock  lock.Lock
+}
+
+func (s *storeLimiter) Allow(name string, limit Limit) (bool, error) {
+	if limit.Rate <= 0 {
+		return true, nil
+	}
+
+	key := DefaultPrefix + name
+
+	if err := s.lock.Acquire(key); err != nil {
+		return false, err
+	}
+	defer s.lock.Release(key)
+
+	b := new(bucket)
+
+	records, err := s.store.Read(key)
+	if err != nil && err != store.ErrNotFound {
+		return false, err
+	}
+	if len(records) > 0 {
+		if err := json.Unmarshal(records[0].Value, b); err != nil {
+			return false, err
+		}
+	}
+
+	ok := b.take(limit, time.Now())
+
+	v, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+
+	// a bucket left to refill completely is the same as a new one
+	refill := math.Ceil((limit.burst() - b.Tokens) / limit.Rate)
+
+	if err := s.store.Write(&store.Record{
+		Key:    key,
+		Value:  v,
+		Expiry: time.Duration(refill+1) * time.Second,
+	}); err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+// NewStoreLimiter returns a Limiter sharing the buckets through the store.
+// The lock serializes updates of a bucket and must be distributed too for
+// the buckets to be shared across processes.
+func NewStoreLimiter(s store.Store, l lock.Lock) Limiter {
+	return &storeLimiter{
+		store: s,
+		lock:  l,
+	}
+}
@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/pkg/config"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultPath of the limits in the config
+	DefaultPath = "stack.ratelimit"
+)
+
+// limits holds the current limits, reloaded when the config changes
+type limits struct {
+	sync.RWMutex
+	limits Limits
+}
+
+func newLimits(opts Options, section string) *limits {
+	l := &limits{limits: opts.Limits}
+
+	if opts.Config == nil {
+		return l
+	}
+
+	path := opts.Path + "." + section
+
+	var v Limits
+	if err := opts.Config.Get(path).Scan(&v); err != nil {
+		log.Errorf("[ratelimit] failed to read %s: %v", path, err)
+	} else if v != nil {
+		l.limits = v
+	}
+
+	w, err := opts.Config.Watch(path)
+	if err != nil {
+		log.Errorf("[ratelimit] failed to watch %s: %v", path, err)
+		return l
+	}
+
+	go l.watch(w, path)
+
+	return l
+}
+
+func (l *limits) watch(w config.Watcher, path string) {
+	defer w.Stop()
+
+	for {
+		val, err := w.Next()
+		if err != nil {
+			log.Debugf("[ratelimit] stopped watching %s: %v", path, err)
+			return
+		}
+
+		var v Limits
+		if err := val.Scan(&v); err != nil {
+			log.Errorf("[ratelimit] failed to reload %s: %v", path, err)
+			continue
+		}
+
+		l.Lock()
+		l.limits = v
+		l.Unlock()
+
+		log.Infof("[ratelimit] reloaded %s", path)
+	}
+}
+
+func (l *limits) get(name string) (Limit, bool) {
+	l.RLock()
+	defer l.RUnlock()
+	limit, ok := l.limits[name]
+	return limit, ok
+}
+
+// allow takes a token from the service bucket and the endpoint bucket,
+// each only if a limit is set for it
+func (l *limits) allow(limiter Limiter, prefix, service, endpoint string) error {
+	for _, name := range []string{service, service + "/" + endpoint} {
+		limit, ok := l.get(name)
+		if !ok {
+			continue
+		}
+
+		ok, err := limiter.Allow(prefix+name, limit)
+		if err != nil {
+			// don't fail the request when the limiter is unavailable
+			log.Warnf("[ratelimit] failed to take token for %s: %v", name, err)
+			continue
+		}
+		if !ok {
+			return errors.TooManyRequests(service, "rate limit exceeded for %s", name)
+		}
+	}
+
+	return nil
+}
+
+// NewHandlerWrapper returns a server.HandlerWrapper enforcing the limits of the
+// service and its endpoints. Requests over the limit fail with a 429.
+func NewHandlerWrapper(opts ...Option) server.HandlerWrapper {
+	options := newOptions(opts...)
+	l := newLimits(options, "server")
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if err := l.allow(options.Limiter, "server/", req.Service(), req.Endpoint()); err != nil {
+				return err
+			}
+			return h(ctx, req, rsp)
+		}
+	}
+}
+
+// NewCallWrapper returns a client.CallWrapper enforcing the limits of calls to
+// services and their endpoints. Calls over the limit fail with a 429.
+func NewCallWrapper(opts ...Option) client.CallWrapper {
+	options := newOptions(opts...)
+	l := newLimits(options, "client")
+
+	return func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			if err := l.allow(options.Limiter, "client/", req.Service(), req.Endpoint()); err != nil {
+				return err
+			}
+			return cf(ctx, node, req, rsp, opts)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+// Package segment is an append only log split into segment files.
+// Entries are addressed by offset, starting from zero.
+package segment
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultSegmentSize is the size a segment grows to before a new one is started
+	DefaultSegmentSize int64 = 16 << 20
+
+	// ErrNotFound is returned reading an offset beyond the end of the log
+	ErrNotFound = errors.New("offset not found")
+	// ErrRemoved is returned reading an offset of a segment removed by retention
+	ErrRemoved = errors.New("offset removed")
+	// ErrCorrupt is returned when an entry fails its checksum
+	ErrCorrupt = errors.New("corrupt log entry")
+	// ErrClosed is returned using a closed log
+	ErrClosed = errors.New("log closed")
+
+	// size of the length and checksum preceding each entry
+	headerSize = 8
+	suffix     = ".log"
+)
+
+type Options struct {
+	// SegmentSize in bytes a segment grows to before a new one is started
+	SegmentSize int64
+	// MaxSegments kept, the oldest are removed first. Zero keeps all.
+	MaxSegments int
+	// Sync every append to disk before returning
+	Sync bool
+}
+
+type Option func(o *Options)
+
+// SegmentSize sets the size a segment grows to before a new one is started
+func SegmentSize(n int64) Option {
+	return func(o *Options) {
+		o.SegmentSize = n
+	}
+}
+
+// MaxSegments sets the number of segments kept
+func MaxSegments(n int) Option {
+	return func(o *Options) {
+		o.MaxSegments = n
+	}
+}
+
+// Sync sets whether every append is synced to disk before returning
+func Sync(b bool) Option {
+	return func(o *Options) {
+		o.Sync = b
+	}
+}
+
+type segment struct {
+	// offset of the first entry
+	base int64
+	path string
+	// positions of the entries in the file
+	positions []int64
+	size      int64
+}
+
+// Log is a segmented append only log kept in a directory
+type Log struct {
+	opts Options
+	dir  string
+
+	sync.RWMutex
+	segments []*segment
+	// file of the last segment
+	file    *os.File
+	closed  bool
+	changed chan struct{}
+}
+
+func segmentPath(dir string, base int64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", base, suffix))
+}
+
+func encode(b []byte) []byte {
+	buf := make([]byte, headerSize+len(b))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(b)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(b))
+	copy(buf[headerSize:], b)
+	return buf
+}
+
+func decode(r io.Reader) ([]byte, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, binary.BigEndian.Uint32(header[0:4]))
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(b) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, ErrCorrupt
+	}
+
+	return b, nil
+}
+
+// load indexes the entries of a segment. A torn write at the tail of the
+// last segment, left by a crash mid append, is truncated away.
+func (s *segment) load(last bool) error {
+	file, err := os.OpenFile(s.path, os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	for {
+		b, err := decode(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if !last {
+				return fmt.Errorf("%s at position %d: %v", s.path, s.size, err)
+			}
+			log.Logf("[segment] truncating %s at position %d: %v", s.path, s.size, err)
+			return file.Truncate(s.size)
+		}
+		s.positions = append(s.positions, s.size)
+		s.size += int64(headerSize + len(b))
+	}
+}
+
+// First returns the offset of the first entry kept
+func (l *Log) First() int64 {
+	l.RLock()
+	defer l.RUnlock()
+	return l.segments[0].base
+}
+
+// Next returns the offset the next entry is appended at
+func (l *Log) Next() int64 {
+	l.RLock()
+	defer l.RUnlock()
+	s := l.segments[len(l.segments)-1]
+	return s.base + int64(len(s.positions))
+}
+
+// Changed returns a channel closed on the next append
+func (l *Log) Changed() <-chan struct{} {
+	l.RLock()
+	defer l.RUnlock()
+	return l.changed
+}
+
+// roll starts a new segment at the next offset and applies retention
+func (l *Log) roll() error {
+	last := l.segments[len(l.segments)-1]
+	base := last.base + int64(len(last.positions))
+
+	file, err := os.OpenFile(segmentPath(l.dir, base), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	l.file.Close()
+	l.file = file
+	l.segments = append(l.segments, &segment{base: base, path: file.Name()})
+
+	for l.opts.MaxSegments > 0 && len(l.segments) > l.opts.MaxSegments {
+		if err := os.Remove(l.segments[0].path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		l.segments = l.segments[1:]
+	}
+
+	return nil
+}
+
+// Append writes an entry to the log and returns its offset
+func (l *Log) Append(b []byte) (int64, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return 0, ErrClosed
+	}
+
+	s := l.segments[len(l.segments)-1]
+	if s.size > 0 && s.size+int64(headerSize+len(b)) > l.opts.SegmentSize {
+		if err := l.roll(); err != nil {
+			return 0, err
+		}
+		s = l.segments[len(l.segments)-1]
+	}
+
+	buf := encode(b)
+	if _, err := l.file.Write(buf); err != nil {
+		// drop a partial write so the log stays readable
+		l.file.Truncate(s.size)
+		return 0, err
+	}
+	if l.opts.Sync {
+		if err := l.file.Sync(); err != nil {
+			return 0, err
+		}
+	}
+
+	offset := s.base + int64(len(s.positions))
+	s.positions = append(s.positions, s.size)
+	s.size += int64(len(buf))
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+
+	return offset, nil
+}
+
+// Read returns the entry at the offset
+func (l *Log) Read(offset int64) ([]byte, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	if l.closed {
+		return nil, ErrClosed
+	}
+
+	if offset < l.segments[0].base {
+		return nil, ErrRemoved
+	}
+
+	i := sort.Search(len(l.segments), func(i int) bool {
+		return l.segments[i].base > offset
+	}) - 1
+
+	s := l.segments[i]
+	n := offset - s.base
+	if n >= int64(len(s.positions)) {
+		return nil, ErrNotFound
+	}
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(s.positions[n], io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return decode(bufio.NewReader(file))
+}
+
+// Close the log
+func (l *Log) Close() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	return l.file.Close()
+}
+
+// Open the log kept in dir, creating it if it doesn't exist
+func Open(dir string, opts ...Option) (*Log, error) {
+	options := Options{
+		SegmentSize: DefaultSegmentSize,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []*segment
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), suffix) {
+			continue
+		}
+		base, err := strconv.ParseInt(strings.TrimSuffix(f.Name(), suffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, &segment{base: base, path: filepath.Join(dir, f.Name())})
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].base < segments[j].base
+	})
+
+	if len(segments) == 0 {
+		segments = append(segments, &segment{base: 0, path: segmentPath(dir, 0)})
+	}
+
+	for i, s := range segments {
+		if i == len(segments)-1 {
+			// create the last segment if it's new
+			f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0600)
+			if err != nil {
+				return nil, err
+			}
+			f.Close()
+		}
+		if err := s.load(i == len(segments)-1); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.OpenFile(segments[len(segments)-1].path, os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Log{
+		opts:     options,
+		dir:      dir,
+		segments: segments,
+		file:     file,
+		changed:  make(chan struct{}),
+	}, nil
+}
@@ -0,0 +1,94 @@
+package segment
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "segment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// room for a few entries per segment
+	l, err := Open(dir, SegmentSize(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		offset, err := l.Append([]byte(fmt.Sprintf("entry-%d", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if offset != int64(i) {
+			t.Fatalf("Expected offset %d got %d", i, offset)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(files) < 2 {
+		t.Fatalf("Expected the log to be split into segments got %d", len(files))
+	}
+
+	if _, err := l.Read(10); err != ErrNotFound {
+		t.Fatalf("Expected %v got %v", ErrNotFound, err)
+	}
+
+	l.Close()
+
+	// append a torn write to the last segment
+	f, err := os.OpenFile(files[len(files)-1], os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 0, 42, 1, 2})
+	f.Close()
+
+	l, err = Open(dir, SegmentSize(64), MaxSegments(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if l.First() != 0 || l.Next() != 10 {
+		t.Fatalf("Expected offsets 0 to 10 got %d to %d", l.First(), l.Next())
+	}
+
+	for i := 0; i < 10; i++ {
+		b, err := l.Read(int64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != fmt.Sprintf("entry-%d", i) {
+			t.Fatalf("Unexpected entry %d %s", i, b)
+		}
+	}
+
+	// rolling a segment applies retention
+	changed := l.Changed()
+
+	for i := 10; i < 20; i++ {
+		if _, err := l.Append([]byte(fmt.Sprintf("entry-%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-changed:
+	default:
+		t.Fatal("Expected append to be notified")
+	}
+
+	if _, err := l.Read(0); err != ErrRemoved {
+		t.Fatalf("Expected %v got %v", ErrRemoved, err)
+	}
+	if b, err := l.Read(19); err != nil || string(b) != "entry-19" {
+		t.Fatalf("Unexpected entry %s %v", b, err)
+	}
+}
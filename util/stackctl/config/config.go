@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+
+	"github.com/stack-labs/stack"
+	cfg "github.com/stack-labs/stack/config"
+	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/pkg/config/service/handler"
+	pb "github.com/stack-labs/stack/pkg/config/service/proto"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/file"
+	"github.com/stack-labs/stack/store/service"
+	"github.com/stack-labs/stack/util/log"
+)
+
+func run(c *cli.Context) error {
+	// config servers sharing the store service serve the same config
+	var s store.Store
+	if nodes := c.StringSlice("store"); len(nodes) > 0 {
+		s = service.NewStore(store.Nodes(nodes...))
+	} else {
+		s = file.NewStore(file.Dir(c.String("dir")))
+	}
+
+	srv := stack.NewService(stack.Name(c.String("name")))
+	if err := srv.Init(); err != nil {
+		log.Fatal("stackctl config init err: ", err)
+	}
+
+	if err := pb.RegisterSourceHandler(srv.Server(), &handler.Config{Store: s}); err != nil {
+		log.Fatal("stackctl config register handler err: ", err)
+	}
+
+	return srv.Run()
+}
+
+// sample writes a sample stack.yml of the stack options
+func sample(c *cli.Context) error {
+	out := c.String("out")
+	if len(out) == 0 {
+		return cfg.Sample(os.Stdout)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return cfg.Sample(f)
+}
+
+func Commands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:  "config",
+			Usage: "Run a config server serving versioned config from a store",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "name",
+					Usage: "Name the server registers with",
+					Value: "stack.rpc.config",
+				},
+				&cli.StringFlag{
+					Name:  "dir",
+					Usage: "Directory the config is kept in",
+					Value: file.DefaultDir,
+				},
+				&cli.StringSliceFlag{
+					Name:  "store",
+					Usage: "Addresses of the store service keeping the config instead of the directory",
+				},
+			},
+			Action: run,
+			Subcommands: []cli.Command{
+				{
+					Name:  "sample",
+					Usage: "Write a sample stack.yml documenting the options",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "out",
+							Usage: "File the sample is written to, defaults to stdout",
+						},
+					},
+					Action: sample,
+				},
+			},
+		},
+	}
+}
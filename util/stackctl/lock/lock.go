@@ -0,0 +1,66 @@
+package lock
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/service"
+	lkhttp "github.com/stack-labs/stack/sync/lock/http"
+	"github.com/stack-labs/stack/sync/lock/http/server"
+	lkstore "github.com/stack-labs/stack/sync/lock/store"
+	"github.com/stack-labs/stack/util/log"
+)
+
+func run(c *cli.Context) error {
+	opts := []server.Option{
+		server.Name(c.String("name")),
+		server.Address(c.String("address")),
+	}
+
+	// lock servers sharing a store exclude each other's holders, the store
+	// service swaps the locks atomically, refusing stores without compare and swap
+	if nodes := c.StringSlice("store"); len(nodes) > 0 {
+		s := service.NewStore(store.Nodes(nodes...))
+		opts = append(opts, server.Lock(lkstore.NewLock(s)))
+	}
+
+	srv := server.NewService(opts...)
+	if err := srv.Start(); err != nil {
+		log.Fatal("stackctl lock server start err: ", err)
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
+	<-ch
+
+	return srv.Stop()
+}
+
+func Commands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:  "lock",
+			Usage: "Run a lock server for the http sync lock",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "name",
+					Usage: "Name the server registers with",
+					Value: lkhttp.DefaultService,
+				},
+				&cli.StringFlag{
+					Name:  "address",
+					Usage: "Address to listen on",
+					Value: ":8080",
+				},
+				&cli.StringSliceFlag{
+					Name:  "store",
+					Usage: "Addresses of the store service backing the locks, in memory if not set. Its store must support compare and swap",
+				},
+			},
+			Action: run,
+		},
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"os"
 
 	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/util/stackctl/config"
+	"github.com/stack-labs/stack/util/stackctl/lock"
+	"github.com/stack-labs/stack/util/stackctl/monitor"
 	"github.com/stack-labs/stack/util/stackctl/new"
+	"github.com/stack-labs/stack/util/stackctl/secret"
 	"github.com/stack-labs/stack/util/stackctl/service"
 )
 
@@ -14,6 +18,10 @@ func main() {
 
 	app.Commands = append(app.Commands, new.Commands()...)
 	app.Commands = append(app.Commands, service.Commands()...)
+	app.Commands = append(app.Commands, lock.Commands()...)
+	app.Commands = append(app.Commands, config.Commands()...)
+	app.Commands = append(app.Commands, monitor.Commands()...)
+	app.Commands = append(app.Commands, secret.Commands()...)
 
 	app.Run(os.Args)
 }
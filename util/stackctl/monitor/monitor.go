@@ -0,0 +1,66 @@
+package monitor
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/stack-labs/stack"
+	"github.com/stack-labs/stack/monitor"
+	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/util/log"
+)
+
+func run(c *cli.Context) error {
+	// use the registry and broker of the configured service
+	s := stack.NewService(stack.Name("stack.rpc.monitor"))
+	if err := s.Init(); err != nil {
+		log.Fatal("stackctl monitor init err: ", err)
+	}
+
+	m := monitor.NewMonitor(
+		monitor.Client(s.Client()),
+		monitor.Registry(s.Options().Registry),
+		monitor.Broker(s.Options().Broker),
+		monitor.Topic(c.String("topic")),
+		monitor.Interval(c.Duration("interval")),
+		monitor.Failures(c.Int("failures")),
+	)
+
+	if err := m.Run(); err != nil {
+		log.Fatal("stackctl monitor run err: ", err)
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
+	<-ch
+
+	return m.Stop()
+}
+
+func Commands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:  "monitor",
+			Usage: "Check the health of the services in the registry and reap failed nodes",
+			Flags: []cli.Flag{
+				&cli.DurationFlag{
+					Name:  "interval",
+					Usage: "Interval the services are checked at",
+					Value: time.Minute,
+				},
+				&cli.IntFlag{
+					Name:  "failures",
+					Usage: "Consecutive failed checks before a node is deregistered, 0 never deregisters",
+				},
+				&cli.StringFlag{
+					Name:  "topic",
+					Usage: "Topic the status changes are published to",
+					Value: monitor.DefaultTopic,
+				},
+			},
+			Action: run,
+		},
+	}
+}
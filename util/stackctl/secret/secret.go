@@ -0,0 +1,128 @@
+package secret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/pkg/config/secret/file"
+)
+
+// key reads the base64 key of the key file, or of the key env
+func key(c *cli.Context) ([]byte, error) {
+	k := os.Getenv(file.DefaultKeyEnv)
+	if p := c.String("key-file"); len(p) > 0 {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		k = string(b)
+	}
+	if len(k) == 0 {
+		return nil, errors.New("the key file or " + file.DefaultKeyEnv + " is required")
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(k))
+}
+
+func keygen(c *cli.Context) error {
+	k := make([]byte, 32)
+	if _, err := rand.Read(k); err != nil {
+		return err
+	}
+	fmt.Println(base64.StdEncoding.EncodeToString(k))
+	return nil
+}
+
+func encrypt(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("the value to encrypt is required")
+	}
+
+	k, err := key(c)
+	if err != nil {
+		return err
+	}
+
+	s, err := file.Encrypt(k, c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ENC(%s)\n", s)
+	return nil
+}
+
+func seal(c *cli.Context) error {
+	k, err := key(c)
+	if err != nil {
+		return err
+	}
+
+	b, err := ioutil.ReadFile(c.String("in"))
+	if err != nil {
+		return err
+	}
+
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(b, &secrets); err != nil {
+		return err
+	}
+
+	sealed, err := file.Seal(k, secrets)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.String("out"), sealed, 0600)
+}
+
+func Commands() []cli.Command {
+	keyFile := &cli.StringFlag{
+		Name:  "key-file",
+		Usage: "File of the base64 AES key, defaults to " + file.DefaultKeyEnv,
+	}
+
+	return []cli.Command{
+		{
+			Name:  "secret",
+			Usage: "Encrypt the secrets of the config",
+			Subcommands: []cli.Command{
+				{
+					Name:   "keygen",
+					Usage:  "Generate a base64 AES-256 key",
+					Action: keygen,
+				},
+				{
+					Name:      "encrypt",
+					Usage:     "Encrypt a value as an ENC(...) config value",
+					ArgsUsage: "value",
+					Flags:     []cli.Flag{keyFile},
+					Action:    encrypt,
+				},
+				{
+					Name:  "seal",
+					Usage: "Encrypt a JSON object of secrets by name into a secrets file",
+					Flags: []cli.Flag{
+						keyFile,
+						&cli.StringFlag{
+							Name:  "in",
+							Usage: "JSON file of the secrets",
+						},
+						&cli.StringFlag{
+							Name:  "out",
+							Usage: "Secrets file written",
+							Value: "secrets.enc",
+						},
+					},
+					Action: seal,
+				},
+			},
+		},
+	}
+}
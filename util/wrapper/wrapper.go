@@ -10,6 +10,7 @@ import (
 
 	"github.com/stack-labs/stack/client"
 	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/util/errors"
 )
 
 type clientWrapper struct {
@@ -68,6 +69,24 @@ type traceWrapper struct {
 	trace trace.Tracer
 }
 
+func (c *traceWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	newCtx, s := c.trace.Start(ctx, req.Service()+"."+req.Endpoint())
+	if s == nil {
+		return c.Client.Call(ctx, req, rsp, opts...)
+	}
+	s.Type = trace.SpanTypeRequestOutbound
+
+	err := c.Client.Call(newCtx, req, rsp, opts...)
+	if err != nil {
+		s.Metadata["error"] = err.Error()
+	}
+
+	// finish the trace
+	c.trace.Finish(s)
+
+	return err
+}
+
 // TraceCall is a call tracing wrapper
 func TraceCall(name string, t trace.Tracer, c client.Client) client.Client {
 	return &traceWrapper{
@@ -90,6 +109,9 @@ func TraceHandler(t trace.Tracer) server.HandlerWrapper {
 
 			// get the span
 			newCtx, s := t.Start(ctx, req.Service()+"."+req.Endpoint())
+			if s == nil {
+				return h(ctx, req, rsp)
+			}
 			s.Type = trace.SpanTypeRequestInbound
 
 			err := h(newCtx, req, rsp)
@@ -146,3 +168,79 @@ func (a *authWrapper) Call(ctx context.Context, req client.Request, rsp interfac
 func AuthClient(auth func() auth.Auth, c client.Client) client.Client {
 	return &authWrapper{c, auth}
 }
+
+// HealthExempt reports whether a request is for the health check of the
+// debug handler the framework serves on the server, which the monitor calls
+// without credentials. The Debug.Health endpoints of other services and of
+// servers without the debug handler are not exempt.
+func HealthExempt(s func() server.Server) func(req server.Request) bool {
+	return func(req server.Request) bool {
+		opts := s().Options()
+		return opts.EnableDebug && req.Service() == opts.Name && req.Endpoint() == "Debug.Health"
+	}
+}
+
+// AuthHandler wraps a server handler to perform authentication and
+// authorization. The bearer token of the Authorization header is inspected
+// and the resulting account, if any, verified against the rules. Requests
+// which are not allowed fail with a 401 when no valid account was provided
+// and with a 403 otherwise. Requests matched by an exempt func are not
+// verified.
+func AuthHandler(a func() auth.Auth, r func() auth.Rules, exempt ...func(req server.Request) bool) server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			aa := a()
+			rules := r()
+			if aa == nil || rules == nil {
+				return h(ctx, req, rsp)
+			}
+
+			for _, e := range exempt {
+				if e(req) {
+					return h(ctx, req, rsp)
+				}
+			}
+
+			// inspect the token if one was provided
+			header, ok := metadata.Get(ctx, "Authorization")
+			if !ok {
+				// grpc lower cases the header keys
+				header, ok = metadata.Get(ctx, "authorization")
+			}
+
+			var account *auth.Account
+			if ok && len(header) > 0 {
+				if !strings.HasPrefix(header, auth.BearerScheme) {
+					return errors.Unauthorized(req.Service(), "invalid authorization header, expected bearer scheme")
+				}
+
+				acc, err := aa.Inspect(strings.TrimPrefix(header, auth.BearerScheme))
+				if err != nil {
+					return errors.Unauthorized(req.Service(), "%v", err)
+				}
+				account = acc
+			}
+
+			res := &auth.Resource{
+				Type:     "service",
+				Name:     req.Service(),
+				Endpoint: req.Endpoint(),
+			}
+
+			if err := rules.Verify(account, res); err == auth.ErrForbidden && account == nil {
+				return errors.Unauthorized(req.Service(), "unauthorized call to %s", req.Endpoint())
+			} else if err == auth.ErrForbidden {
+				return errors.Forbidden(req.Service(), "forbidden call to %s", req.Endpoint())
+			} else if err != nil {
+				return errors.InternalServerError(req.Service(), "error authorizing request: %v", err)
+			}
+
+			// make the account available to the handler
+			if account != nil {
+				ctx = auth.ContextWithAccount(ctx, account)
+			}
+
+			return h(ctx, req, rsp)
+		}
+	}
+}
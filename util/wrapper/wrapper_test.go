@@ -4,7 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stack-labs/stack/auth"
+	"github.com/stack-labs/stack/auth/jwt"
+	"github.com/stack-labs/stack/codec"
 	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/server/mock"
+	"github.com/stack-labs/stack/util/errors"
 )
 
 func TestWrapper(t *testing.T) {
@@ -51,3 +57,112 @@ func TestWrapper(t *testing.T) {
 	}
 
 }
+
+type testRequest struct {
+	service  string
+	endpoint string
+}
+
+func (r *testRequest) Service() string           { return r.service }
+func (r *testRequest) Method() string            { return r.endpoint }
+func (r *testRequest) Endpoint() string          { return r.endpoint }
+func (r *testRequest) ContentType() string       { return "" }
+func (r *testRequest) Header() map[string]string { return nil }
+func (r *testRequest) Body() interface{}         { return nil }
+func (r *testRequest) Read() ([]byte, error)     { return nil, nil }
+func (r *testRequest) Codec() codec.Reader       { return nil }
+func (r *testRequest) Stream() bool              { return false }
+
+func TestAuthHandler(t *testing.T) {
+	testData := []struct {
+		header string
+		rule   *auth.Rule
+		code   int32
+	}{
+		// public access without a token
+		{
+			rule: &auth.Rule{ID: "public", Scope: auth.ScopePublic, Resource: &auth.Resource{Type: "service", Name: "foo", Endpoint: "*"}},
+		},
+		// no rules and no token
+		{
+			code: 401,
+		},
+		// token with a scheme other than bearer
+		{
+			header: "Basic foo",
+			rule:   &auth.Rule{ID: "public", Scope: auth.ScopePublic, Resource: &auth.Resource{Type: "service", Name: "foo", Endpoint: "*"}},
+			code:   401,
+		},
+		// valid token without the required scope
+		{
+			header: auth.BearerScheme + "foo",
+			rule:   &auth.Rule{ID: "admin", Scope: "admin", Resource: &auth.Resource{Type: "service", Name: "foo", Endpoint: "Foo.Bar"}},
+			code:   403,
+		},
+		// valid token for a rule applying to any account
+		{
+			header: auth.BearerScheme + "foo",
+			rule:   &auth.Rule{ID: "account", Scope: auth.ScopeAccount, Resource: &auth.Resource{Type: "service", Name: "foo", Endpoint: "Foo.Bar"}},
+		},
+	}
+
+	for _, d := range testData {
+		rules := jwt.NewRules()
+		if d.rule != nil {
+			rules.Grant(d.rule)
+		}
+
+		wrapper := AuthHandler(
+			func() auth.Auth { return auth.NoopAuth },
+			func() auth.Rules { return rules },
+		)
+
+		var called bool
+		h := wrapper(func(ctx context.Context, req server.Request, rsp interface{}) error {
+			called = true
+			if _, ok := auth.AccountFromContext(ctx); !ok && len(d.header) > 0 {
+				t.Fatal("Expected account in context")
+			}
+			return nil
+		})
+
+		ctx := context.Background()
+		if len(d.header) > 0 {
+			ctx = metadata.Set(ctx, "Authorization", d.header)
+		}
+
+		err := h(ctx, &testRequest{service: "foo", endpoint: "Foo.Bar"}, nil)
+		if d.code == 0 {
+			if err != nil || !called {
+				t.Fatalf("Expected call to succeed got %v", err)
+			}
+			continue
+		}
+
+		if called {
+			t.Fatal("Expected handler not to be called")
+		}
+		if e := errors.Parse(err.Error()); e.Code != d.code {
+			t.Fatalf("Expected code %d got %v", d.code, err)
+		}
+	}
+	// only the health check of the framework debug handler is served without a token
+	srv := mock.NewServer(server.Name("foo"), server.EnableDebug(true))
+	h := AuthHandler(
+		func() auth.Auth { return auth.NoopAuth },
+		func() auth.Rules { return jwt.NewRules() },
+		HealthExempt(func() server.Server { return srv }),
+	)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})
+	if err := h(context.Background(), &testRequest{service: "foo", endpoint: "Debug.Health"}, nil); err != nil {
+		t.Fatalf("Expected the health check to be exempt got %v", err)
+	}
+	if err := h(context.Background(), &testRequest{service: "bar", endpoint: "Debug.Health"}, nil); err == nil {
+		t.Fatal("Expected the health check of another service not to be exempt")
+	}
+	srv.Init(server.EnableDebug(false))
+	if err := h(context.Background(), &testRequest{service: "foo", endpoint: "Debug.Health"}, nil); err == nil {
+		t.Fatal("Expected the health check without the debug handler not to be exempt")
+	}
+}